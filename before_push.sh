@@ -1,5 +1,10 @@
 #!/bin/bash
 
 go test -v -cover ./server
+
+# repositories are tested against mongo when it's available, e.g. mongodb service of docker-compose
+if [ -n "$SIMPLENDI_TEST_MONGO_URI" ]; then
+    SIMPLENDI_TEST_STORAGE=mongo go test -v ./server -check.f "EntityRepoTestSuite|FieldCipherTestSuite|MigrationTestSuite"
+fi
 golint ./server/
 godep save
\ No newline at end of file
@@ -1,19 +1,37 @@
 package main
 
 import (
+	"encoding/base64"
 	"flag"
-	"fmt"
 	"git.simplendi.com/FirmQ/frontend-server/server"
 	"github.com/golang/glog"
 	"github.com/spf13/viper"
 )
 
+// dryRun - migrate command only reports migrations which would be applied
+var dryRun = flag.Bool("dry-run", false, "report pending migrations without applying them")
+
 func main() {
 	flag.Parse()
 
 	viper.SetEnvPrefix("simplendi")
 	viper.AutomaticEnv()
 
+	jwtKeys, err := server.ParseJWTKeys(viper.GetString("jwt_keys"))
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	encryptionKeys, err := server.ParseEncryptionKeys(viper.GetString("encryption_keys"))
+	if err != nil {
+		glog.Fatal(err)
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(viper.GetString("blind_index_key"))
+	if err != nil {
+		glog.Fatal(err)
+	}
+
 	config := &server.Config{
 		NexmoAPIKey:          viper.GetString("nexmo_api_key"),
 		NexmoSecretKey:       viper.GetString("nexmo_secret_key"),
@@ -26,19 +44,153 @@ func main() {
 		EmailSMTPPort:        viper.GetInt("email_smtp_port"),
 		EmailConfirmationTTL: viper.GetDuration("email_confirmation_ttl"),
 		SMSConfirmationTTL:   viper.GetDuration("sms_confirmation_ttl"),
-	}
+		AccessTokenTTL:       viper.GetDuration("access_token_ttl"),
+		RefreshTokenTTL:      viper.GetDuration("refresh_token_ttl"),
+		PasswordResetTTL:     viper.GetDuration("password_reset_ttl"),
+
+		LoginMaxFailures:        viper.GetInt("login_max_failures"),
+		LoginMaxAddressFailures: viper.GetInt("login_max_address_failures"),
+		LoginLockoutBase:        viper.GetDuration("login_lockout_base"),
+		LoginLockoutMax:         viper.GetDuration("login_lockout_max"),
+		LoginFailuresWindow:     viper.GetDuration("login_failures_window"),
+		TrustedProxies:          viper.GetInt("trusted_proxies"),
+		SMSCodeMaxAttempts:      viper.GetInt("sms_code_max_attempts"),
+		TOTPIssuer:              viper.GetString("totp_issuer"),
+		SSOCallbackURL:          viper.GetString("sso_callback_url"),
+		SSOStateTTL:             viper.GetDuration("sso_state_ttl"),
+		ImpersonationTTL:        viper.GetDuration("impersonation_ttl"),
+		EntityRetention:         viper.GetDuration("entity_retention"),
+		EntityImportMaxRows:     viper.GetInt("entity_import_max_rows"),
+		EntityImportMaxBytes:    viper.GetInt("entity_import_max_bytes"),
+		EntityImportStaleAfter:  viper.GetDuration("entity_import_stale_after"),
+
+		JWTKeys:        jwtKeys,
+		JWTActiveKeyID: viper.GetString("jwt_active_key_id"),
+
+		EncryptionKeys:        encryptionKeys,
+		EncryptionActiveKeyID: viper.GetString("encryption_active_key_id"),
+		BlindIndexKey:         blindIndexKey,
+		KeyRotationInterval:   viper.GetDuration("key_rotation_interval"),
 
-	fmt.Printf("%+v\n", config)
+		GRPCTLSCertFile:       viper.GetString("grpc_tls_cert_file"),
+		GRPCTLSKeyFile:        viper.GetString("grpc_tls_key_file"),
+		GRPCClientCAFile:      viper.GetString("grpc_client_ca_file"),
+		GRPCRequireClientCert: viper.GetBool("grpc_require_client_cert"),
+		GatewayCertFile:       viper.GetString("gateway_cert_file"),
+		GatewayKeyFile:        viper.GetString("gateway_key_file"),
+		GatewayCAFile:         viper.GetString("gateway_ca_file"),
+		GRPCServerName:        viper.GetString("grpc_server_name"),
+		HTTPTLSCertFile:       viper.GetString("http_tls_cert_file"),
+		HTTPTLSKeyFile:        viper.GetString("http_tls_key_file"),
+		HTTPClientCAFile:      viper.GetString("http_client_ca_file"),
+
+		Storage:             viper.GetString("storage"),
+		MongoURI:            viper.GetString("mongo_uri"),
+		MongoDatabase:       viper.GetString("mongo_database"),
+		MongoPoolLimit:      viper.GetInt("mongo_pool_limit"),
+		MongoReadPreference: viper.GetString("mongo_read_preference"),
+		MongoDialTimeout:    viper.GetDuration("mongo_dial_timeout"),
+		MongoSocketTimeout:  viper.GetDuration("mongo_socket_timeout"),
+		SkipMigrations:      viper.GetBool("skip_migrations"),
+	}
 
 	srv, err := server.NewServer(config)
 	if err != nil {
-		glog.Error(err)
+		glog.Fatal(err)
 	}
 
 	flag.Set("alsologtostderr", "true")
 	flag.Set("v", "5")
 
+	// keys and passwords of config aren't logged
+	glog.Infof("Server URL: %s, port: %s, storage: %s, database: %s", srv.Config.ServerURL, srv.Config.Port, srv.Config.Storage, srv.Config.MongoDatabase)
+
+	// "frontend-server migrate" applies migrations of database and exits
+	if flag.Arg(0) == "migrate" {
+		results, err := server.RunMigrations(srv.Config, *dryRun)
+		for _, result := range results {
+			glog.Infof("Migration %d: %s, documents: %d, dry run: %v", result.Version, result.Description, result.Changed, result.DryRun)
+		}
+
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		if *dryRun {
+			glog.Infof("%d migrations are pending", len(results))
+		} else {
+			glog.Infof("Database is migrated, %d migrations applied", len(results))
+		}
+		glog.Flush()
+		return
+	}
+
+	// "frontend-server check-entities" reports entities which violate invariants of revisions and exits
+	if flag.Arg(0) == "check-entities" {
+		violations, err := server.CheckEntities(srv.Config)
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		for _, violation := range violations {
+			glog.Warningf("Entity %s: %s (rev %d, count %d)", violation.EntityID, violation.Problem, violation.Rev, violation.Count)
+		}
+
+		glog.Infof("%d violations of entity revisions found", len(violations))
+		glog.Flush()
+		return
+	}
+
+	// "frontend-server export-company <company-id> <file>" writes bundle of company to new file and exits
+	if flag.Arg(0) == "export-company" {
+		manifest, err := server.ExportCompanyFile(srv.Config, flag.Arg(1), flag.Arg(2))
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		for _, file := range manifest.Files {
+			glog.Infof("%s: %d records, sha256 %s", file.Name, file.Records, file.SHA256)
+		}
+
+		glog.Infof("Company %s is exported to %s", manifest.CompanyID, flag.Arg(2))
+		glog.Flush()
+		return
+	}
+
+	// "frontend-server import-company <file>" validates bundle and imports company, with -dry-run only reports
+	if flag.Arg(0) == "import-company" {
+		report, err := server.ImportCompanyFile(srv.Config, flag.Arg(1), *dryRun)
+		if report != nil {
+			for _, problem := range report.Problems {
+				glog.Warningf("Problem: %s", problem)
+			}
+			for oldID, newID := range report.Remapped {
+				glog.Infof("Id %s is taken and is replaced by %s", oldID, newID)
+			}
+		}
+
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		glog.Infof("Company %s: %d users, %d entities, %d revisions, dry run: %v", report.CompanyID, report.Users, report.Entities, report.Revisions, report.DryRun)
+		glog.Flush()
+		return
+	}
+
+	// "frontend-server rotate-keys" seals personal data by active encryption key and exits
+	if flag.Arg(0) == "rotate-keys" {
+		changed, err := server.ResealDocuments(srv.Config)
+		if err != nil {
+			glog.Fatal(err)
+		}
+
+		glog.Infof("%d documents are sealed by active encryption key", changed)
+		glog.Flush()
+		return
+	}
+
 	if err := srv.RunServer(); err != nil {
-		glog.Error(err)
+		glog.Fatal(err)
 	}
 }
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"crypto/subtle"
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAPIKey - error when api key is unknown, revoked or expired
+var ErrInvalidAPIKey = errors.New("api key is invalid, revoked or expired")
+
+// apiKeyLastUsedResolution - last used time of api key isn't updated more often than this, like last seen time of session
+const apiKeyLastUsedResolution = sessionLastSeenResolution
+
+// APIKeyRepo - model for accessing api keys of service accounts in database
+type APIKeyRepo struct {
+	sess *mgo.Database
+	coll string
+}
+
+// NewAPIKeyRepo - returns new instance of APIKeyRepo
+func NewAPIKeyRepo(sess *mgo.Database) *APIKeyRepo {
+	return &APIKeyRepo{
+		sess: sess,
+		coll: "api_keys",
+	}
+}
+
+// newAPIKey - returns new api key of service account and the key itself in format "id.secret",
+// which can't be restored later because only hash of secret is stored
+func newAPIKey(serviceAccount *grpc_gateway_user.User, name string, expiresAt int64) (*grpc_gateway_user.APIKey, string, error) {
+	secret, err := GenerateSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := &grpc_gateway_user.APIKey{
+		Id:               uuid.NewV4().String(),
+		ServiceAccountId: serviceAccount.Id,
+		CompanyId:        serviceAccount.CompanyId,
+		Name:             name,
+		KeyHash:          hashSecretToken(secret),
+		CreatedAt:        time.Now().Unix(),
+		ExpiresAt:        expiresAt,
+	}
+
+	return apiKey, apiKey.Id + "." + secret, nil
+}
+
+// CreateAPIKey - create new api key for service account. Returns stored key and the key itself
+func (ar *APIKeyRepo) CreateAPIKey(serviceAccount *grpc_gateway_user.User, name string, expiresAt int64) (*grpc_gateway_user.APIKey, string, error) {
+	c := ar.sess.C(ar.coll)
+
+	apiKey, key, err := newAPIKey(serviceAccount, name, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := c.Insert(apiKey); err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+// parseAPIKey - split key passed by client to id and secret
+func parseAPIKey(key string) (string, string, error) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return "", "", ErrInvalidAPIKey
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// checkAPIKey - check secret of api key passed by client and whether key is still active
+func checkAPIKey(apiKey *grpc_gateway_user.APIKey, secret string, now int64) error {
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashSecretToken(secret))) != 1 {
+		return ErrInvalidAPIKey
+	}
+
+	if apiKey.IsRevoked || (apiKey.ExpiresAt != 0 && apiKey.ExpiresAt <= now) {
+		return ErrInvalidAPIKey
+	}
+
+	return nil
+}
+
+// isAPIKeyTouchRequired - check whether last used time of api key is too old
+func isAPIKeyTouchRequired(apiKey *grpc_gateway_user.APIKey, now int64) bool {
+	return now-apiKey.LastUsedAt >= int64(apiKeyLastUsedResolution.Seconds())
+}
+
+// GetAPIKeyByID - get api key by id
+func (ar *APIKeyRepo) GetAPIKeyByID(id string) (*grpc_gateway_user.APIKey, error) {
+	c := ar.sess.C(ar.coll)
+	var apiKey grpc_gateway_user.APIKey
+
+	err := c.Find(bson.M{"id": id}).One(&apiKey)
+	return &apiKey, err
+}
+
+// GetAPIKeys - get page of keys of service account
+func (ar *APIKeyRepo) GetAPIKeys(serviceAccountID string, page *Page) (*grpc_gateway_user.APIKeyListResponse, error) {
+	c := ar.sess.C(ar.coll)
+	apiKeys := NewAPIKeyListResponse()
+
+	next, total, err := findPage(c, bson.M{"serviceaccountid": serviceAccountID}, page, &apiKeys.Data)
+	apiKeys.NextCursor = next
+	apiKeys.Total = int64(total)
+	return apiKeys, err
+}
+
+// RevokeAPIKey - revoke api key by id
+func (ar *APIKeyRepo) RevokeAPIKey(id string) error {
+	c := ar.sess.C(ar.coll)
+	return c.Update(bson.M{"id": id}, bson.M{"$set": bson.M{"isrevoked": true}})
+}
+
+// RevokeServiceAccountAPIKeys - revoke all api keys of service accounts
+func (ar *APIKeyRepo) RevokeServiceAccountAPIKeys(serviceAccountIDs ...string) error {
+	c := ar.sess.C(ar.coll)
+	_, err := c.UpdateAll(bson.M{"serviceaccountid": bson.M{"$in": serviceAccountIDs}}, bson.M{"$set": bson.M{"isrevoked": true}})
+	return err
+}
+
+// Authenticate - find active api key by key passed by client and track its usage
+func (ar *APIKeyRepo) Authenticate(key string) (*grpc_gateway_user.APIKey, error) {
+	id, secret, err := parseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := ar.GetAPIKeyByID(id)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now().Unix()
+	if err := checkAPIKey(apiKey, secret, now); err != nil {
+		return nil, err
+	}
+
+	if !isAPIKeyTouchRequired(apiKey, now) {
+		return apiKey, nil
+	}
+
+	c := ar.sess.C(ar.coll)
+	if err := c.Update(bson.M{"id": apiKey.Id}, bson.M{"$set": bson.M{"lastusedat": now}}); err != nil {
+		return nil, err
+	}
+
+	apiKey.LastUsedAt = now
+	return apiKey, nil
+}
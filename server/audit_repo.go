@@ -0,0 +1,49 @@
+package server
+
+import (
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"time"
+)
+
+// Audit actions which aren't rpc calls. Calls made under impersonation are recorded with full method name as action
+const (
+	AuditImpersonationStarted = "impersonation.started"
+)
+
+// AuditRecord - record of audit trail
+type AuditRecord struct {
+	ID        string
+	CreatedAt int64
+	Action    string
+	UserID    string
+	CompanyID string
+
+	// ImpersonatorID - id of platform admin who acted as user
+	ImpersonatorID string
+	Address        string
+	Details        string
+}
+
+// AuditRepo - model for accessing audit trail in database
+type AuditRepo struct {
+	sess *mgo.Database
+	coll string
+}
+
+// NewAuditRepo - returns new instance of AuditRepo
+func NewAuditRepo(sess *mgo.Database) *AuditRepo {
+	return &AuditRepo{
+		sess: sess,
+		coll: "audit_log",
+	}
+}
+
+// Record - add record to audit trail
+func (ar *AuditRepo) Record(record *AuditRecord) error {
+	c := ar.sess.C(ar.coll)
+
+	record.ID = uuid.NewV4().String()
+	record.CreatedAt = time.Now().Unix()
+	return c.Insert(record)
+}
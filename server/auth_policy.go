@@ -0,0 +1,193 @@
+package server
+
+import (
+	"fmt"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"google.golang.org/grpc"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PolicyKind - kind of access policy of rpc
+type PolicyKind int
+
+// Kinds of policies: public methods don't require token, authenticated methods require token and permission,
+// admin methods are available only for platform admins and company-scoped methods require permission in company
+// which id is passed in request field
+const (
+	PolicyPublic PolicyKind = iota
+	PolicyAuthenticated
+	PolicyAdmin
+	PolicyCompany
+)
+
+// AuthPolicy - access policy of rpc
+type AuthPolicy struct {
+	Kind       PolicyKind
+	Permission Permission
+
+	// CompanyField - proto name of request field with company id for company-scoped policies
+	CompanyField string
+
+	// DenyImpersonation - method changes credentials or access of user, so it isn't available for admins
+	// who act as the user
+	DenyImpersonation bool
+}
+
+// Public - policy of method which is available without token
+func Public() AuthPolicy {
+	return AuthPolicy{Kind: PolicyPublic, Permission: PermissionPublic}
+}
+
+// Authenticated - policy of method which is available for users with permission. Handler is responsible
+// for checking company of affected objects
+func Authenticated(perm Permission) AuthPolicy {
+	return AuthPolicy{Kind: PolicyAuthenticated, Permission: perm}
+}
+
+// Admin - policy of method which is available only for platform admins
+func Admin(perm Permission) AuthPolicy {
+	return AuthPolicy{Kind: PolicyAdmin, Permission: perm}
+}
+
+// CompanyScoped - policy of method which is available for users with permission in company from request field
+func CompanyScoped(perm Permission, field string) AuthPolicy {
+	return AuthPolicy{Kind: PolicyCompany, Permission: perm, CompanyField: field}
+}
+
+// NoImpersonation - return copy of policy which denies method in impersonated sessions
+func (p AuthPolicy) NoImpersonation() AuthPolicy {
+	p.DenyImpersonation = true
+	return p
+}
+
+// authPolicies - access policy of each rpc by full method name
+var authPolicies = map[string]AuthPolicy{
+	"/grpc.gateway.user.UserService/Login":                  Public(),
+	"/grpc.gateway.user.UserService/Refresh":                Public(),
+	"/grpc.gateway.user.UserService/ConfirmEmail":           Public(),
+	"/grpc.gateway.user.UserService/RenewInvitation":        Public(),
+	"/grpc.gateway.user.UserService/RequestPasswordReset":   Public(),
+	"/grpc.gateway.user.UserService/ResetPassword":          Public(),
+	"/grpc.gateway.user.UserService/Logout":                 Authenticated(PermissionAuthenticated),
+	"/grpc.gateway.user.UserService/EnrollTOTP":             Authenticated(PermissionAuthenticated).NoImpersonation(),
+	"/grpc.gateway.user.UserService/ConfirmTOTP":            Authenticated(PermissionAuthenticated).NoImpersonation(),
+	"/grpc.gateway.user.UserService/SetPreferredFactor":     Authenticated(PermissionAuthenticated).NoImpersonation(),
+	"/grpc.gateway.user.UserService/UpdateUser":             Authenticated(PermissionAuthenticated),
+	"/grpc.gateway.user.UserService/GetUser":                Authenticated(PermissionAuthenticated),
+	"/grpc.gateway.user.UserService/DeleteUser":             Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/UnlockUser":             Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/Impersonate":            Admin(PermissionUserManage).NoImpersonation(),
+	"/grpc.gateway.user.UserService/ListSessions":           Authenticated(PermissionAuthenticated),
+	"/grpc.gateway.user.UserService/RevokeSession":          Authenticated(PermissionAuthenticated),
+	"/grpc.gateway.user.UserService/ListUserSessions":       Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/RevokeUserSession":      Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/CreateUser":             CompanyScoped(PermissionUserManage, "company_id"),
+	"/grpc.gateway.user.UserService/ResendInvitation":       Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/ListPendingInvitations": CompanyScoped(PermissionUserManage, "id"),
+	"/grpc.gateway.user.UserService/GetUserByCompany":       CompanyScoped(PermissionUserRead, "id"),
+	"/grpc.gateway.user.UserService/GetUsers":               Admin(PermissionUserRead),
+	"/grpc.gateway.user.UserService/CreateServiceAccount":   CompanyScoped(PermissionUserManage, "company_id").NoImpersonation(),
+	"/grpc.gateway.user.UserService/CreateAPIKey":           Authenticated(PermissionUserManage).NoImpersonation(),
+	"/grpc.gateway.user.UserService/ListAPIKeys":            Authenticated(PermissionUserManage),
+	"/grpc.gateway.user.UserService/RevokeAPIKey":           Authenticated(PermissionUserManage).NoImpersonation(),
+
+	"/grpc.gateway.company.CompanyService/CreateCompany": Admin(PermissionCompanyManage),
+	"/grpc.gateway.company.CompanyService/UpdateCompany": Admin(PermissionCompanyManage),
+	"/grpc.gateway.company.CompanyService/DeleteCompany": Admin(PermissionCompanyManage),
+	"/grpc.gateway.company.CompanyService/GetCompanies":  Admin(PermissionCompanyManage),
+	"/grpc.gateway.company.CompanyService/GetCompany":    CompanyScoped(PermissionCompanyRead, "id"),
+
+	"/grpc.gateway.company.CompanyService/SetIdentityProvider": Admin(PermissionCompanyManage),
+	"/grpc.gateway.company.CompanyService/GetIdentityProvider": Admin(PermissionCompanyManage),
+
+	"/grpc.gateway.entity.EntityService/CreateEntity":         Authenticated(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/UpdateEntity":         Authenticated(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/GetLatestEntity":      Authenticated(PermissionEntityRead),
+	"/grpc.gateway.entity.EntityService/GetEntityRevisions":   Authenticated(PermissionEntityRead),
+	"/grpc.gateway.entity.EntityService/GetEntities":          Authenticated(PermissionEntityRead),
+	"/grpc.gateway.entity.EntityService/DeleteEntity":         Authenticated(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/RestoreEntity":        Authenticated(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/ListDeletedEntities":  Authenticated(PermissionEntityRead),
+	"/grpc.gateway.entity.EntityService/SearchEntities":       Authenticated(PermissionEntityRead),
+	"/grpc.gateway.entity.EntityService/PurgeDeletedEntities": Admin(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/ImportEntities":       Authenticated(PermissionEntityWrite),
+	"/grpc.gateway.entity.EntityService/GetEntityImport":      Authenticated(PermissionEntityRead),
+}
+
+// GetAuthPolicy - return access policy of rpc
+func GetAuthPolicy(fullMethod string) (AuthPolicy, bool) {
+	policy, ok := authPolicies[fullMethod]
+	return policy, ok
+}
+
+// ValidateAuthPolicies - check that every method registered in grpc server has access policy
+func ValidateAuthPolicies(s *grpc.Server) error {
+	missed := []string{}
+	for service, info := range s.GetServiceInfo() {
+		for _, method := range info.Methods {
+			fullMethod := "/" + service + "/" + method.Name
+			if _, ok := authPolicies[fullMethod]; !ok {
+				missed = append(missed, fullMethod)
+			}
+		}
+	}
+
+	if len(missed) > 0 {
+		sort.Strings(missed)
+		return fmt.Errorf("access policy isn't declared for methods: %s", strings.Join(missed, ", "))
+	}
+
+	return nil
+}
+
+// Allows - check if policy allows request for user
+func (p AuthPolicy) Allows(user *grpc_gateway_user.User, req interface{}) bool {
+	switch p.Kind {
+	case PolicyPublic:
+		return true
+	case PolicyAuthenticated:
+		return RoleHasPermission(UserRole(user), p.Permission)
+	case PolicyAdmin:
+		return IsPlatformAdmin(user) && RoleHasPermission(UserRole(user), p.Permission)
+	case PolicyCompany:
+		companyID, ok := requestField(req, p.CompanyField)
+		return ok && HasPermission(user, p.Permission, companyID)
+	}
+
+	return false
+}
+
+// AllowsRole - check part of policy which doesn't depend on request. It's used for streaming calls,
+// which requests are received after call is started
+func (p AuthPolicy) AllowsRole(user *grpc_gateway_user.User) bool {
+	if p.Kind == PolicyCompany {
+		return RoleHasPermission(UserRole(user), p.Permission)
+	}
+
+	return p.Allows(user, nil)
+}
+
+// requestField - return value of string field of proto request by proto name of the field
+func requestField(req interface{}, name string) (string, bool) {
+	v := reflect.ValueOf(req)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		for _, part := range strings.Split(t.Field(i).Tag.Get("protobuf"), ",") {
+			if part == "name="+name && v.Field(i).Kind() == reflect.String {
+				return v.Field(i).String(), true
+			}
+		}
+	}
+
+	return "", false
+}
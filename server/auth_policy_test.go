@@ -0,0 +1,89 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"google.golang.org/grpc"
+	. "gopkg.in/check.v1"
+)
+
+type AuthPolicyTestSuite struct{}
+
+var _ = Suite(&AuthPolicyTestSuite{})
+
+func (at *AuthPolicyTestSuite) TestAllows(c *C) {
+	platformAdmin := &grpc_gateway_user.User{Role: server.RolePlatformAdmin}
+	companyAdmin := &grpc_gateway_user.User{Role: server.RoleCompanyAdmin, CompanyId: "c1"}
+	viewer := &grpc_gateway_user.User{Role: server.RoleViewer, CompanyId: "c1"}
+
+	c.Assert(server.Public().Allows(nil, nil), Equals, true)
+
+	policy := server.Authenticated(server.PermissionEntityWrite)
+	c.Assert(policy.Allows(companyAdmin, nil), Equals, true)
+	c.Assert(policy.Allows(viewer, nil), Equals, false)
+
+	policy = server.Admin(server.PermissionCompanyManage)
+	c.Assert(policy.Allows(platformAdmin, nil), Equals, true)
+	c.Assert(policy.Allows(companyAdmin, nil), Equals, false)
+
+	// company id is taken from request field
+	policy = server.CompanyScoped(server.PermissionUserManage, "company_id")
+	c.Assert(policy.Allows(companyAdmin, &grpc_gateway_user.User{CompanyId: "c1"}), Equals, true)
+	c.Assert(policy.Allows(companyAdmin, &grpc_gateway_user.User{CompanyId: "c2"}), Equals, false)
+	c.Assert(policy.Allows(viewer, &grpc_gateway_user.User{CompanyId: "c1"}), Equals, false)
+	c.Assert(policy.Allows(platformAdmin, &grpc_gateway_user.User{CompanyId: "c2"}), Equals, true)
+
+	// request without field is denied
+	c.Assert(policy.Allows(companyAdmin, &grpc_gateway_common.IDRequest{Id: "c1"}), Equals, false)
+
+	policy = server.CompanyScoped(server.PermissionCompanyRead, "id")
+	c.Assert(policy.Allows(viewer, &grpc_gateway_common.IDRequest{Id: "c1"}), Equals, true)
+	c.Assert(policy.Allows(viewer, &grpc_gateway_common.IDRequest{Id: "c2"}), Equals, false)
+
+	// streaming calls are checked by role before requests are received
+	c.Assert(policy.AllowsRole(viewer), Equals, true)
+	c.Assert(server.CompanyScoped(server.PermissionUserManage, "id").AllowsRole(viewer), Equals, false)
+	c.Assert(server.Admin(server.PermissionCompanyManage).AllowsRole(companyAdmin), Equals, false)
+}
+
+func (at *AuthPolicyTestSuite) TestRegistry(c *C) {
+	policy, ok := server.GetAuthPolicy("/grpc.gateway.user.UserService/Login")
+	c.Assert(ok, Equals, true)
+	c.Assert(policy.Kind, Equals, server.PolicyPublic)
+
+	_, ok = server.GetAuthPolicy("/grpc.gateway.user.UserService/Unknown")
+	c.Assert(ok, Equals, false)
+
+	// credentials can't be changed while impersonating
+	policy, ok = server.GetAuthPolicy("/grpc.gateway.user.UserService/EnrollTOTP")
+	c.Assert(ok, Equals, true)
+	c.Assert(policy.DenyImpersonation, Equals, true)
+
+	policy, ok = server.GetAuthPolicy("/grpc.gateway.entity.EntityService/GetEntities")
+	c.Assert(ok, Equals, true)
+	c.Assert(policy.DenyImpersonation, Equals, false)
+}
+
+func (at *AuthPolicyTestSuite) TestValidateAuthPolicies(c *C) {
+	s := grpc.NewServer()
+	storage := server.NewMemoryStorage()
+	grpc_gateway_user.RegisterUserServiceServer(s, server.NewUserServer(&server.Config{}, storage))
+	grpc_gateway_company.RegisterCompanyServiceServer(s, server.NewCompanyServer(storage))
+	grpc_gateway_entity.RegisterEntityServiceServer(s, server.NewEntityServer(&server.Config{}, storage))
+
+	c.Assert(server.ValidateAuthPolicies(s), IsNil)
+
+	// method without policy fails validation
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.UnknownService",
+		HandlerType: (*interface{})(nil),
+		Methods:     []grpc.MethodDesc{{MethodName: "Call"}},
+	}, struct{}{})
+
+	err := server.ValidateAuthPolicies(s)
+	c.Assert(err, NotNil)
+	c.Assert(err, ErrorMatches, ".*/test.UnknownService/Call.*")
+}
@@ -5,13 +5,12 @@ import (
 	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
 	"golang.org/x/net/context"
 	//"google.golang.org/grpc/metadata"
-	google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
 	"gopkg.in/mgo.v2"
 	"net/http"
 )
 
 type companyServer struct {
-	sess *mgo.Session
+	storage Storage
 }
 
 // NewCompanyResponse - create new instance of company response
@@ -28,29 +27,30 @@ func NewCompanyListResponse() *grpc_gateway_company.CompanyListResponse {
 	return message
 }
 
+// NewIdentityProviderResponse - create new instance of identity provider response
+func NewIdentityProviderResponse() *grpc_gateway_company.IdentityProviderResponse {
+	message := &grpc_gateway_company.IdentityProviderResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	return message
+}
+
 // NewCompanyServer - returns new grpc server which provide company-related functionality
-func NewCompanyServer() grpc_gateway_company.CompanyServiceServer {
-	return new(companyServer)
+func NewCompanyServer(storage Storage) grpc_gateway_company.CompanyServiceServer {
+	return &companyServer{storage: storage}
 }
 
 func (c *companyServer) CreateCompany(ctx context.Context, company *grpc_gateway_company.Company) (*grpc_gateway_common.IDResponse, error) {
 	message := NewIDResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := c.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	companyRepo := NewCompanyRepo(sess)
-
-	if err := IsAdminUser(ctx); err != nil {
-		message.Meta.StatusCode = http.StatusForbidden
-		message.Meta.Ok = false
-		message.Meta.Error = err.Error()
-		return message, nil
-	}
+	companyRepo := sess.Companies()
 
 	err = companyRepo.CreateCompany(company)
 	if err == nil {
@@ -67,22 +67,15 @@ func (c *companyServer) CreateCompany(ctx context.Context, company *grpc_gateway
 func (c *companyServer) UpdateCompany(ctx context.Context, company *grpc_gateway_company.Company) (*grpc_gateway_common.CommonResponse, error) {
 	message := NewCommonResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := c.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	// check permissions. only admin user has access
-	if err := IsAdminUser(ctx); err != nil {
-		message.Meta.StatusCode = http.StatusForbidden
-		message.Meta.Ok = false
-		message.Meta.Error = err.Error()
-		return message, nil
-	}
-
-	companyRepo := NewCompanyRepo(sess)
+	companyRepo := sess.Companies()
 	err = companyRepo.UpdateCompany(company)
 	if err == nil {
 		message.Meta.Ok = true
@@ -97,21 +90,15 @@ func (c *companyServer) UpdateCompany(ctx context.Context, company *grpc_gateway
 func (c *companyServer) GetCompany(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_company.CompanyResponse, error) {
 	company := NewCompanyResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := c.storage.Open()
 	if err != nil {
 		company.Meta.Ok = false
 		company.Meta.Error = err.Error()
 		return company, nil
 	}
+	defer sess.Close()
 
-	companyRepo := NewCompanyRepo(sess)
-
-	if err := IsAdminOrCompanyUser(ctx, in.Id); err != nil {
-		company.Meta.Ok = false
-		company.Meta.Error = err.Error()
-		company.Meta.StatusCode = http.StatusForbidden
-		return company, nil
-	}
+	companyRepo := sess.Companies()
 
 	company.Data, err = companyRepo.GetCompanyByID(in.Id)
 
@@ -126,56 +113,151 @@ func (c *companyServer) GetCompany(ctx context.Context, in *grpc_gateway_common.
 	return company, nil
 }
 
-func (c *companyServer) GetCompanies(ctx context.Context, in *google_protobuf1.Empty) (*grpc_gateway_company.CompanyListResponse, error) {
+func (c *companyServer) GetCompanies(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_company.CompanyListResponse, error) {
 	companyList := NewCompanyListResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, CompanySortFields, "name")
 	if err != nil {
+		companyList.Meta.StatusCode = http.StatusBadRequest
 		companyList.Meta.Ok = false
 		companyList.Meta.Error = err.Error()
 		return companyList, nil
 	}
 
-	companyRepo := NewCompanyRepo(sess)
-
-	if err := IsAdminUser(ctx); err != nil {
-		companyList.Meta.StatusCode = http.StatusForbidden
+	sess, err := c.storage.Open()
+	if err != nil {
 		companyList.Meta.Ok = false
 		companyList.Meta.Error = err.Error()
 		return companyList, nil
 	}
+	defer sess.Close()
+
+	companyRepo := sess.Companies()
+
+	companyList, err = companyRepo.GetCompanies(page)
+	if err != nil {
+		companyList.Meta.Ok = false
+		companyList.Meta.Error = err.Error()
+	} else {
+		companyList.Meta.Ok = true
+	}
 
-	companyList, err = companyRepo.GetCompanies()
-	companyList.Meta.Ok = true
 	return companyList, nil
 }
 
 func (c *companyServer) DeleteCompany(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error) {
 	message := NewCommonResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := c.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	companyRepo := NewCompanyRepo(sess)
+	companyRepo := sess.Companies()
 
-	if err := IsAdminUser(ctx); err != nil {
-		message.Meta.StatusCode = http.StatusForbidden
+	err = companyRepo.DeleteCompanyByID(in.Id)
+	if err == nil {
+		err = revokeCompanySessions(sess, in.Id)
+	}
+
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+	} else {
+		message.Meta.Ok = true
+	}
+
+	return message, nil
+}
+
+func (c *companyServer) SetIdentityProvider(ctx context.Context, idp *grpc_gateway_company.IdentityProvider) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := c.storage.Open()
+	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	err = companyRepo.DeleteCompanyByID(in.Id)
+	if _, err := sess.Companies().GetCompanyByID(idp.CompanyId); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrNotFound.Error()
+		message.Meta.StatusCode = http.StatusNotFound
+		return message, nil
+	}
+
+	if idp.Issuer == "" || idp.ClientId == "" || len(idp.AllowedDomains) == 0 {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	repo := sess.IdentityProviders()
+
+	// secret isn't returned to clients, so empty secret keeps the stored one
+	if idp.ClientSecret == "" {
+		if stored, err := repo.GetIdentityProvider(idp.CompanyId); err == nil {
+			idp.ClientSecret = stored.ClientSecret
+		}
+	}
+
+	if err := repo.SetIdentityProvider(idp); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (c *companyServer) GetIdentityProvider(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_company.IdentityProviderResponse, error) {
+	message := NewIdentityProviderResponse()
+
+	sess, err := c.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
-	} else {
-		message.Meta.Ok = true
+		return message, nil
 	}
+	defer sess.Close()
 
+	message.Data, err = sess.IdentityProviders().GetIdentityProvider(in.Id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Data.ClientSecret = ""
+	message.Meta.Ok = true
 	return message, nil
 }
+
+// revokeCompanySessions - revoke sessions of all users and api keys of all service accounts of disabled company
+func revokeCompanySessions(sess StorageSession, companyID string) error {
+	users, err := sess.Users().GetUsersByCompanyID(companyID, nil)
+	if err != nil {
+		return err
+	}
+
+	userIDs := []string{}
+	for _, user := range users.Data {
+		userIDs = append(userIDs, user.Id)
+	}
+
+	if err := sess.Sessions().RevokeUserSessions(userIDs...); err != nil {
+		return err
+	}
+
+	return sess.APIKeys().RevokeServiceAccountAPIKeys(userIDs...)
+}
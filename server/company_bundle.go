@@ -0,0 +1,615 @@
+package server
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBundle - error when company bundle is damaged or can't be imported, problems are listed in report
+var ErrInvalidBundle = errors.New("company bundle can't be imported")
+
+// BundleFormat - format name of company bundles, BundleVersion - version of bundle layout which is written
+const (
+	BundleFormat  = "firmq-company-bundle"
+	BundleVersion = 1
+)
+
+// Files of company bundle, every file contains one json record per line
+const (
+	bundleManifestFile  = "manifest.json"
+	bundleCompanyFile   = "company.ndjson"
+	bundleUsersFile     = "users.ndjson"
+	bundleEntitiesFile  = "entities.ndjson"
+	bundleProvidersFile = "identity_providers.ndjson"
+)
+
+// BundleManifest - description of company bundle
+type BundleManifest struct {
+	Format     string        `json:"format"`
+	Version    int           `json:"version"`
+	CompanyID  string        `json:"company_id"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Files      []*BundleFile `json:"files"`
+}
+
+// BundleFile - file of bundle with number of records and hex sha256 of content
+type BundleFile struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// ImportReport - result of import. Remapped lists ids which were taken in storage and were replaced by new ids.
+// Bundle isn't imported when there are problems, nothing is stored on dry run
+type ImportReport struct {
+	DryRun    bool
+	CompanyID string
+	Users     int
+	Entities  int
+	Revisions int
+	Remapped  map[string]string
+	Problems  []string
+}
+
+// companyBundle - records of one company
+type companyBundle struct {
+	company   *grpc_gateway_company.Company
+	users     []*grpc_gateway_user.User
+	entities  []*grpc_gateway_entity.Entity
+	providers []*grpc_gateway_company.IdentityProvider
+}
+
+// exportedUser - user without secrets, passwords, codes and second factors can't be moved to another environment
+func exportedUser(user *grpc_gateway_user.User) *grpc_gateway_user.User {
+	exported := cloneUser(user)
+	exported.Password = ""
+	exported.EmailCode = ""
+	exported.EmailSentAt = nil
+	exported.SmsCode = ""
+	exported.SmsSentAt = nil
+	exported.TotpEnabled = false
+	exported.TotpSecret = ""
+	exported.TotpPendingSecret = ""
+	exported.TotpLastStep = 0
+	exported.PasswordResetToken = ""
+	exported.PasswordResetSentAt = nil
+
+	if exported.PreferredFactor == FactorTOTP {
+		exported.PreferredFactor = FactorSMS
+	}
+
+	return exported
+}
+
+// ExportCompany - write company, its users without secrets, all entity revisions and identity provider
+// without client secret to w as gzipped tar of ndjson files with manifest
+func ExportCompany(sess StorageSession, companyID string, w io.Writer) (*BundleManifest, error) {
+	company, err := sess.Companies().GetCompanyByID(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := sess.Users().GetUsersByCompanyID(companyID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := sess.Entities().GetCompanyRevisions(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []proto.Message{}
+	provider, err := sess.IdentityProviders().GetIdentityProvider(companyID)
+	if err == nil {
+		provider.ClientSecret = ""
+		providers = append(providers, provider)
+	} else if err != mgo.ErrNotFound {
+		return nil, err
+	}
+
+	files := []struct {
+		name    string
+		records []proto.Message
+	}{
+		{bundleCompanyFile, []proto.Message{company}},
+		{bundleUsersFile, []proto.Message{}},
+		{bundleEntitiesFile, []proto.Message{}},
+		{bundleProvidersFile, providers},
+	}
+	for _, user := range users.Data {
+		files[1].records = append(files[1].records, exportedUser(user))
+	}
+	for _, entity := range entities.Data {
+		files[2].records = append(files[2].records, entity)
+	}
+
+	manifest := &BundleManifest{
+		Format:     BundleFormat,
+		Version:    BundleVersion,
+		CompanyID:  companyID,
+		ExportedAt: time.Now().UTC(),
+		Files:      []*BundleFile{},
+	}
+
+	contents := [][]byte{}
+	marshaler := jsonpb.Marshaler{OrigName: true}
+	for _, file := range files {
+		buf := &bytes.Buffer{}
+		for _, record := range file.records {
+			if err := marshaler.Marshal(buf, record); err != nil {
+				return nil, err
+			}
+			buf.WriteByte('\n')
+		}
+
+		sum := sha256.Sum256(buf.Bytes())
+		manifest.Files = append(manifest.Files, &BundleFile{Name: file.name, Records: len(file.records), SHA256: hex.EncodeToString(sum[:])})
+		contents = append(contents, buf.Bytes())
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+
+	// manifest goes first, so readers know what to expect
+	if err := writeBundleFile(archive, bundleManifestFile, manifestData); err != nil {
+		return nil, err
+	}
+	for i, file := range manifest.Files {
+		if err := writeBundleFile(archive, file.Name, contents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, gz.Close()
+}
+
+// writeBundleFile - add file to archive of bundle
+func writeBundleFile(archive *tar.Writer, name string, data []byte) error {
+	err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = archive.Write(data)
+	return err
+}
+
+// ImportCompany - validate bundle from r and store its records. Ids which are taken in storage are replaced
+// by new ids and references to them are changed too. Returns ErrInvalidBundle if bundle has problems
+func ImportCompany(sess StorageSession, r io.Reader, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{
+		DryRun:   dryRun,
+		Remapped: map[string]string{},
+		Problems: []string{},
+	}
+
+	bundle := readBundle(r, report)
+	if len(report.Problems) > 0 {
+		return report, ErrInvalidBundle
+	}
+
+	if err := planImport(sess, bundle, report); err != nil {
+		return report, err
+	}
+	if len(report.Problems) > 0 {
+		return report, ErrInvalidBundle
+	}
+
+	remapBundle(bundle, report.Remapped)
+	report.CompanyID = bundle.company.Id
+	report.Users = len(bundle.users)
+	report.Revisions = len(bundle.entities)
+
+	ids := map[string]bool{}
+	for _, entity := range bundle.entities {
+		ids[entity.Id] = true
+	}
+	report.Entities = len(ids)
+
+	if dryRun {
+		return report, nil
+	}
+
+	if err := storeBundle(sess, bundle); err != nil {
+		// company id isn't taken by anyone else, so everything of company was written by this import
+		if rollbackErr := removeBundle(sess, bundle); rollbackErr != nil {
+			return report, fmt.Errorf("%v, stored records can't be removed: %v", err, rollbackErr)
+		}
+
+		return report, err
+	}
+
+	return report, nil
+}
+
+// storeBundle - write records of bundle to storage
+func storeBundle(sess StorageSession, bundle *companyBundle) error {
+	if err := sess.Companies().ImportCompany(bundle.company); err != nil {
+		return err
+	}
+
+	for _, user := range bundle.users {
+		if err := sess.Users().ImportUser(user); err != nil {
+			return err
+		}
+	}
+
+	for _, provider := range bundle.providers {
+		if err := sess.IdentityProviders().SetIdentityProvider(provider); err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range bundle.entities {
+		if _, err := sess.Entities().CreateEntity(entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeBundle - remove records of bundle which were stored before import failed
+func removeBundle(sess StorageSession, bundle *companyBundle) error {
+	companyID := bundle.company.Id
+
+	ids := []string{}
+	for _, entity := range bundle.entities {
+		ids = append(ids, entity.Id)
+	}
+
+	if _, err := sess.Entities().RemoveEntities(companyID, ids); err != nil {
+		return err
+	}
+
+	if err := sess.IdentityProviders().RemoveIdentityProvider(companyID); err != nil {
+		return err
+	}
+
+	if _, err := sess.Users().RemoveCompanyUsers(companyID); err != nil {
+		return err
+	}
+
+	if err := sess.Companies().RemoveCompany(companyID); err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// readBundle - read archive, check manifest and checksums and parse records. Problems are added to report
+func readBundle(r io.Reader, report *ImportReport) *companyBundle {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("bundle isn't gzip archive: %v", err))
+		return nil
+	}
+
+	files := map[string][]byte{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("bundle archive is damaged: %v", err))
+			return nil
+		}
+
+		data, err := ioutil.ReadAll(archive)
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("file %s can't be read: %v", header.Name, err))
+			return nil
+		}
+
+		files[header.Name] = data
+	}
+
+	manifest := &BundleManifest{}
+	if err := json.Unmarshal(files[bundleManifestFile], manifest); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("manifest can't be read: %v", err))
+		return nil
+	}
+
+	if manifest.Format != BundleFormat || manifest.Version != BundleVersion {
+		report.Problems = append(report.Problems, fmt.Sprintf("bundle format %s version %d isn't supported", manifest.Format, manifest.Version))
+		return nil
+	}
+
+	listed := map[string]bool{bundleManifestFile: true}
+	for _, file := range manifest.Files {
+		listed[file.Name] = true
+
+		data, ok := files[file.Name]
+		sum := sha256.Sum256(data)
+		switch {
+		case !ok:
+			report.Problems = append(report.Problems, fmt.Sprintf("file %s is missed", file.Name))
+		case hex.EncodeToString(sum[:]) != file.SHA256:
+			report.Problems = append(report.Problems, fmt.Sprintf("checksum of file %s doesn't match", file.Name))
+		case bytes.Count(data, []byte("\n")) != file.Records:
+			report.Problems = append(report.Problems, fmt.Sprintf("file %s has wrong number of records", file.Name))
+		}
+	}
+
+	for name := range files {
+		if !listed[name] {
+			report.Problems = append(report.Problems, fmt.Sprintf("file %s isn't listed in manifest", name))
+		}
+	}
+
+	for _, name := range []string{bundleCompanyFile, bundleUsersFile, bundleEntitiesFile, bundleProvidersFile} {
+		if !listed[name] {
+			report.Problems = append(report.Problems, fmt.Sprintf("file %s isn't listed in manifest", name))
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		return nil
+	}
+
+	bundle := &companyBundle{}
+	companies := []*grpc_gateway_company.Company{}
+	readRecords(files, bundleCompanyFile, report, func() proto.Message {
+		companies = append(companies, &grpc_gateway_company.Company{})
+		return companies[len(companies)-1]
+	})
+	readRecords(files, bundleUsersFile, report, func() proto.Message {
+		bundle.users = append(bundle.users, &grpc_gateway_user.User{})
+		return bundle.users[len(bundle.users)-1]
+	})
+	readRecords(files, bundleEntitiesFile, report, func() proto.Message {
+		bundle.entities = append(bundle.entities, &grpc_gateway_entity.Entity{})
+		return bundle.entities[len(bundle.entities)-1]
+	})
+	readRecords(files, bundleProvidersFile, report, func() proto.Message {
+		bundle.providers = append(bundle.providers, &grpc_gateway_company.IdentityProvider{})
+		return bundle.providers[len(bundle.providers)-1]
+	})
+
+	if len(companies) != 1 || companies[0].Id != manifest.CompanyID {
+		report.Problems = append(report.Problems, "bundle should contain one company from manifest")
+		return nil
+	}
+	bundle.company = companies[0]
+
+	return bundle
+}
+
+// readRecords - parse records of file into messages returned by next
+func readRecords(files map[string][]byte, name string, report *ImportReport, next func() proto.Message) {
+	scanner := bufio.NewScanner(bytes.NewReader(files[name]))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if err := jsonpb.UnmarshalString(scanner.Text(), next()); err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s:%d: %v", name, line, err))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("%s: %v", name, err))
+	}
+}
+
+// planImport - check records of bundle and find ids which are taken in storage
+func planImport(sess StorageSession, bundle *companyBundle, report *ImportReport) error {
+	companyID := bundle.company.Id
+	problem := func(format string, args ...interface{}) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+	}
+
+	taken, err := sess.Companies().HasCompany(companyID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		report.Remapped[companyID] = uuid.NewV4().String()
+	}
+
+	emails := map[string]bool{}
+	for _, user := range bundle.users {
+		if user.CompanyId != companyID {
+			problem("user %s belongs to another company", user.Id)
+		}
+
+		// platform admins aren't members of companies, bundle can't grant access to other companies
+		if role := UserRole(user); user.IsAdmin || role == RolePlatformAdmin || !IsValidRole(role) {
+			problem("user %s has role %s which isn't role of company", user.Id, role)
+		}
+
+		email := strings.ToLower(user.Email)
+		if emails[email] {
+			problem("email %s is duplicated", user.Email)
+		}
+		emails[email] = true
+
+		idTaken, emailTaken, err := sess.Users().HasUser(user.Id, user.Email)
+		if err != nil {
+			return err
+		}
+
+		// email is login of user, so another user with the same email can't be created
+		if emailTaken {
+			problem("email %s is taken by another user", user.Email)
+		}
+		if idTaken {
+			report.Remapped[user.Id] = uuid.NewV4().String()
+		}
+	}
+
+	revisions := map[string]map[int64]bool{}
+	latest := map[string]int{}
+	for _, entity := range bundle.entities {
+		if entity.CompanyId != companyID {
+			problem("entity %s belongs to another company", entity.Id)
+		}
+
+		if revisions[entity.Id] == nil {
+			revisions[entity.Id] = map[int64]bool{}
+
+			taken, err := sess.Entities().HasEntity(entity.Id)
+			if err != nil {
+				return err
+			}
+			if taken {
+				report.Remapped[entity.Id] = uuid.NewV4().String()
+			}
+		}
+
+		if revisions[entity.Id][entity.Rev] {
+			problem("revision %d of entity %s is duplicated", entity.Rev, entity.Id)
+		}
+		revisions[entity.Id][entity.Rev] = true
+
+		if entity.Latest {
+			latest[entity.Id]++
+		}
+	}
+
+	ids := []string{}
+	for id := range revisions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if latest[id] != 1 {
+			problem("entity %s should have one latest revision, it has %d", id, latest[id])
+		}
+	}
+
+	for _, provider := range bundle.providers {
+		if provider.CompanyId != companyID {
+			problem("identity provider belongs to another company")
+		}
+	}
+
+	return nil
+}
+
+// remapBundle - replace ids of records and references to them
+func remapBundle(bundle *companyBundle, remapped map[string]string) {
+	remap := func(id string) string {
+		if newID, ok := remapped[id]; ok {
+			return newID
+		}
+		return id
+	}
+
+	bundle.company.Id = remap(bundle.company.Id)
+
+	for _, user := range bundle.users {
+		user.Id = remap(user.Id)
+		user.CompanyId = bundle.company.Id
+	}
+
+	for _, provider := range bundle.providers {
+		provider.CompanyId = bundle.company.Id
+
+		// client secret isn't exported, so provider is enabled after admin sets it again
+		provider.IsEnabled = false
+	}
+
+	for _, entity := range bundle.entities {
+		entity.Id = remap(entity.Id)
+		entity.CompanyId = bundle.company.Id
+		entity.CreatedBy = remap(entity.CreatedBy)
+		entity.DeletedBy = remap(entity.DeletedBy)
+
+		for _, link := range []*grpc_gateway_entity.EntityLink{entity.Directors, entity.Proxyholders, entity.Trustees, entity.Shareholders} {
+			if link != nil {
+				link.EntityId = remap(link.EntityId)
+			}
+		}
+	}
+}
+
+// ExportCompanyFile - connect to database from config and export company to file
+func ExportCompanyFile(cfg *Config, companyID, path string) (*BundleManifest, error) {
+	pool, err := NewConnectionPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	sess, err := NewMongoStorage(pool).Open()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := ExportCompany(sess, companyID, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+
+	return manifest, err
+}
+
+// ImportCompanyFile - connect to database from config and import company bundle from file
+func ImportCompanyFile(cfg *Config, path string, dryRun bool) (*ImportReport, error) {
+	pool, err := NewConnectionPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	sess, err := NewMongoStorage(pool).Open()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ImportCompany(sess, file, dryRun)
+}
@@ -0,0 +1,349 @@
+package server_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	. "gopkg.in/check.v1"
+	"io"
+	"io/ioutil"
+)
+
+type CompanyBundleTestSuite struct {
+	sess server.StorageSession
+}
+
+var _ = Suite(&CompanyBundleTestSuite{})
+
+func (bt *CompanyBundleTestSuite) SetUpTest(c *C) {
+	var err error
+	bt.sess, err = server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+}
+
+// createBundleCompany - company with user, identity provider and entity with two revisions, one of them
+// links another entity
+func createBundleCompany(c *C, sess server.StorageSession) *grpc_gateway_company.Company {
+	company := &grpc_gateway_company.Company{Name: "Bundle"}
+	c.Assert(sess.Companies().CreateCompany(company), IsNil)
+
+	user := &grpc_gateway_user.User{
+		Id:                 "u1",
+		CompanyId:          company.Id,
+		Email:              "bundle@test.com",
+		Password:           "password-hash",
+		Phone:              "+31611111111",
+		IsEnabled:          true,
+		IsConfirmed:        true,
+		TotpEnabled:        true,
+		TotpSecret:         "totp-secret",
+		PreferredFactor:    server.FactorTOTP,
+		PasswordResetToken: "reset-token",
+		Role:               server.RoleEditor,
+	}
+	c.Assert(sess.Users().ImportUser(user), IsNil)
+
+	c.Assert(sess.IdentityProviders().SetIdentityProvider(&grpc_gateway_company.IdentityProvider{
+		CompanyId:    company.Id,
+		Issuer:       "https://idp.test.com",
+		ClientId:     "client",
+		ClientSecret: "client-secret",
+		IsEnabled:    true,
+	}), IsNil)
+
+	_, err := sess.Entities().CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: company.Id, CommonName: "Director", Latest: true, CreatedBy: "u1"})
+	c.Assert(err, IsNil)
+
+	entity := &grpc_gateway_entity.Entity{Id: "e2", CompanyId: company.Id, CommonName: "Holding", Latest: true, CreatedBy: "u1"}
+	_, err = sess.Entities().CreateEntity(entity)
+	c.Assert(err, IsNil)
+
+	entity.Directors = &grpc_gateway_entity.EntityLink{EntityId: "e1"}
+	_, err = sess.Entities().UpdateEntity(entity, entity.Rev)
+	c.Assert(err, IsNil)
+
+	return company
+}
+
+// exportBundle - export company to bundle in memory
+func exportBundle(c *C, sess server.StorageSession, companyID string) []byte {
+	buf := &bytes.Buffer{}
+	manifest, err := server.ExportCompany(sess, companyID, buf)
+	c.Assert(err, IsNil)
+	c.Assert(manifest.Version, Equals, server.BundleVersion)
+	return buf.Bytes()
+}
+
+// bundleFiles - files of bundle archive
+func bundleFiles(c *C, bundle []byte) map[string][]byte {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	c.Assert(err, IsNil)
+
+	files := map[string][]byte{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return files
+		}
+		c.Assert(err, IsNil)
+
+		files[header.Name], err = ioutil.ReadAll(archive)
+		c.Assert(err, IsNil)
+	}
+}
+
+// writeBundle - archive files as bundle
+func writeBundle(c *C, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	archive := tar.NewWriter(gz)
+	for name, data := range files {
+		c.Assert(archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}), IsNil)
+		_, err := archive.Write(data)
+		c.Assert(err, IsNil)
+	}
+	c.Assert(archive.Close(), IsNil)
+	c.Assert(gz.Close(), IsNil)
+	return buf.Bytes()
+}
+
+// resignBundle - update checksums of manifest after files were changed, like someone who edits bundle does
+func resignBundle(c *C, files map[string][]byte) []byte {
+	manifest := &server.BundleManifest{}
+	c.Assert(json.Unmarshal(files["manifest.json"], manifest), IsNil)
+
+	for _, file := range manifest.Files {
+		sum := sha256.Sum256(files[file.Name])
+		file.SHA256 = hex.EncodeToString(sum[:])
+	}
+
+	data, err := json.Marshal(manifest)
+	c.Assert(err, IsNil)
+	files["manifest.json"] = data
+
+	return writeBundle(c, files)
+}
+
+// failingEntityStorage - entity storage which fails to store the second entity
+type failingEntityStorage struct {
+	server.EntityStorage
+	created int
+}
+
+func (fs *failingEntityStorage) CreateEntity(entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error) {
+	if fs.created++; fs.created > 1 {
+		return nil, errors.New("storage is unavailable")
+	}
+
+	return fs.EntityStorage.CreateEntity(entity)
+}
+
+// failingStorageSession - storage session with failingEntityStorage
+type failingStorageSession struct {
+	server.StorageSession
+	entities *failingEntityStorage
+}
+
+func (fs *failingStorageSession) Entities() server.EntityStorage {
+	return fs.entities
+}
+
+func (bt *CompanyBundleTestSuite) TestExport(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	files := bundleFiles(c, exportBundle(c, bt.sess, company.Id))
+
+	c.Assert(files["manifest.json"], NotNil)
+	c.Assert(bytes.Count(files["users.ndjson"], []byte("\n")), Equals, 1)
+	c.Assert(bytes.Count(files["entities.ndjson"], []byte("\n")), Equals, 3)
+	c.Assert(bytes.Contains(files["users.ndjson"], []byte("+31611111111")), Equals, true)
+
+	// secrets aren't exported
+	for _, secret := range []string{"password-hash", "totp-secret", "reset-token", "client-secret"} {
+		for name, data := range files {
+			c.Assert(bytes.Contains(data, []byte(secret)), Equals, false, Commentf("%s in %s", secret, name))
+		}
+	}
+
+	_, err := server.ExportCompany(bt.sess, "unknown", &bytes.Buffer{})
+	c.Assert(err, NotNil)
+}
+
+func (bt *CompanyBundleTestSuite) TestImport(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	bundle := exportBundle(c, bt.sess, company.Id)
+
+	target, err := server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+
+	// dry run only reports what would be imported
+	report, err := server.ImportCompany(target, bytes.NewReader(bundle), true)
+	c.Assert(err, IsNil)
+	c.Assert(report.DryRun, Equals, true)
+	c.Assert(report.CompanyID, Equals, company.Id)
+	c.Assert(report.Users, Equals, 1)
+	c.Assert(report.Entities, Equals, 2)
+	c.Assert(report.Revisions, Equals, 3)
+	c.Assert(report.Remapped, HasLen, 0)
+
+	_, err = target.Companies().GetCompanyByID(company.Id)
+	c.Assert(err, NotNil)
+
+	report, err = server.ImportCompany(target, bytes.NewReader(bundle), false)
+	c.Assert(err, IsNil)
+
+	imported, err := target.Companies().GetCompanyByID(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(imported.Name, Equals, "Bundle")
+
+	user, err := target.Users().GetUserByID("u1")
+	c.Assert(err, IsNil)
+	c.Assert(user.Phone, Equals, "+31611111111")
+	c.Assert(user.Password, Equals, "")
+	c.Assert(user.TotpEnabled, Equals, false)
+	c.Assert(user.PreferredFactor, Equals, server.FactorSMS)
+
+	idp, err := target.IdentityProviders().GetIdentityProvider(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(idp.IsEnabled, Equals, false)
+
+	revs, err := target.Entities().GetEntityRevs("e2", company.Id, nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 2)
+	c.Assert(revs.Data[0].Latest, Equals, true)
+	c.Assert(revs.Data[0].Directors.EntityId, Equals, "e1")
+}
+
+func (bt *CompanyBundleTestSuite) TestImportRemapsTakenIds(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	bundle := exportBundle(c, bt.sess, company.Id)
+
+	// target has company and entity with the same ids, but user email isn't taken
+	target, err := server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+	c.Assert(target.Companies().ImportCompany(&grpc_gateway_company.Company{Id: company.Id, Name: "Other", IsEnabled: true}), IsNil)
+	_, err = target.Entities().CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: company.Id, Latest: true})
+	c.Assert(err, IsNil)
+	c.Assert(target.Users().ImportUser(&grpc_gateway_user.User{Id: "u1", Email: "other@test.com", IsEnabled: true, IsConfirmed: true}), IsNil)
+
+	report, err := server.ImportCompany(target, bytes.NewReader(bundle), false)
+	c.Assert(err, IsNil)
+	c.Assert(report.Remapped, HasLen, 3)
+
+	companyID := report.Remapped[company.Id]
+	c.Assert(report.CompanyID, Equals, companyID)
+
+	user, err := target.Users().GetUserByID(report.Remapped["u1"])
+	c.Assert(err, IsNil)
+	c.Assert(user.CompanyId, Equals, companyID)
+
+	director, err := target.Entities().GetLatestEntity(report.Remapped["e1"], companyID)
+	c.Assert(err, IsNil)
+	c.Assert(director.CreatedBy, Equals, report.Remapped["u1"])
+
+	// references to remapped entity are changed, entity which isn't taken keeps id
+	holding, err := target.Entities().GetLatestEntity("e2", companyID)
+	c.Assert(err, IsNil)
+	c.Assert(holding.Directors.EntityId, Equals, report.Remapped["e1"])
+
+	// emails can't be remapped, so the same bundle can't be imported again
+	report, err = server.ImportCompany(target, bytes.NewReader(bundle), true)
+	c.Assert(err, Equals, server.ErrInvalidBundle)
+	c.Assert(report.Problems, DeepEquals, []string{"email bundle@test.com is taken by another user"})
+}
+
+func (bt *CompanyBundleTestSuite) TestImportDamagedBundle(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	files := bundleFiles(c, exportBundle(c, bt.sess, company.Id))
+
+	target, err := server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+
+	report, err := server.ImportCompany(target, bytes.NewReader([]byte("not a bundle")), true)
+	c.Assert(err, Equals, server.ErrInvalidBundle)
+	c.Assert(report.Problems, HasLen, 1)
+
+	// changed file doesn't match checksum of manifest
+	changed := map[string][]byte{}
+	for name, data := range files {
+		changed[name] = data
+	}
+	changed["entities.ndjson"] = bytes.Replace(files["entities.ndjson"], []byte("Holding"), []byte("Changed"), -1)
+
+	report, err = server.ImportCompany(target, bytes.NewReader(writeBundle(c, changed)), true)
+	c.Assert(err, Equals, server.ErrInvalidBundle)
+	c.Assert(report.Problems, DeepEquals, []string{"checksum of file entities.ndjson doesn't match"})
+
+	// bundle without manifest can't be imported
+	delete(changed, "manifest.json")
+	_, err = server.ImportCompany(target, bytes.NewReader(writeBundle(c, changed)), true)
+	c.Assert(err, Equals, server.ErrInvalidBundle)
+
+	_, err = target.Companies().GetCompanyByID(company.Id)
+	c.Assert(err, NotNil)
+}
+
+func (bt *CompanyBundleTestSuite) TestImportTamperedRole(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	files := bundleFiles(c, exportBundle(c, bt.sess, company.Id))
+
+	target, err := server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+
+	// bundle with valid checksums can't create platform admins
+	for _, role := range []string{`"role":"platform_admin"`, `"role":"editor","is_admin":true`, `"role":"owner"`} {
+		changed := map[string][]byte{}
+		for name, data := range files {
+			changed[name] = data
+		}
+		changed["users.ndjson"] = bytes.Replace(files["users.ndjson"], []byte(`"role":"editor"`), []byte(role), -1)
+
+		report, err := server.ImportCompany(target, bytes.NewReader(resignBundle(c, changed)), false)
+		c.Assert(err, Equals, server.ErrInvalidBundle, Commentf(role))
+		c.Assert(report.Problems, HasLen, 1)
+		c.Assert(report.Problems[0], Matches, "user u1 has role .* which isn't role of company")
+
+		_, err = target.Users().GetUserByID("u1")
+		c.Assert(err, NotNil)
+	}
+}
+
+func (bt *CompanyBundleTestSuite) TestImportRollback(c *C) {
+	company := createBundleCompany(c, bt.sess)
+	bundle := exportBundle(c, bt.sess, company.Id)
+
+	storage := server.NewMemoryStorage()
+	target, err := storage.Open()
+	c.Assert(err, IsNil)
+
+	// import fails after company, user, provider and the first entity are stored
+	failing := &failingStorageSession{StorageSession: target, entities: &failingEntityStorage{EntityStorage: target.Entities()}}
+	_, err = server.ImportCompany(failing, bytes.NewReader(bundle), false)
+	c.Assert(err, ErrorMatches, "storage is unavailable")
+	c.Assert(failing.entities.created, Equals, 2)
+
+	_, err = target.Companies().GetCompanyByID(company.Id)
+	c.Assert(err, NotNil)
+
+	_, err = target.Users().GetUserByID("u1")
+	c.Assert(err, NotNil)
+
+	_, err = target.IdentityProviders().GetIdentityProvider(company.Id)
+	c.Assert(err, NotNil)
+
+	revs, err := target.Entities().GetCompanyRevisions(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 0)
+
+	// nothing is left, so the same bundle can be imported again
+	_, err = server.ImportCompany(target, bytes.NewReader(bundle), false)
+	c.Assert(err, IsNil)
+}
@@ -43,22 +43,34 @@ func (cr *CompanyRepo) GetCompanyByID(id string) (*grpc_gateway_company.Company,
 	return &company, err
 }
 
-// GetCompanies - get companies from database
-func (cr *CompanyRepo) GetCompanies() (*grpc_gateway_company.CompanyListResponse, error) {
+// GetCompanies - get page of companies from database
+func (cr *CompanyRepo) GetCompanies(page *Page) (*grpc_gateway_company.CompanyListResponse, error) {
 	c := cr.sess.C(cr.coll)
 	companies := NewCompanyListResponse()
 
-	err := c.Find(bson.M{"isenabled": true}).All(&companies.Data)
+	next, total, err := findPage(c, bson.M{"isenabled": true}, page, &companies.Data)
+	companies.NextCursor = next
+	companies.Total = int64(total)
 	return companies, err
 }
 
-// CreateIndexes - create necessary indexes for fast executing
-func (cr *CompanyRepo) CreateIndexes() {
+// ImportCompany - store company with id and state from another environment
+func (cr *CompanyRepo) ImportCompany(company *grpc_gateway_company.Company) error {
 	c := cr.sess.C(cr.coll)
-	c.EnsureIndex(mgo.Index{
-		Key:    []string{"id"},
-		Unique: true,
-	})
+	return c.Insert(company)
+}
+
+// RemoveCompany - remove company from storage, unlike DeleteCompanyByID company isn't kept
+func (cr *CompanyRepo) RemoveCompany(id string) error {
+	c := cr.sess.C(cr.coll)
+	return c.Remove(bson.M{"id": id})
+}
+
+// HasCompany - check if company id is taken by enabled or deleted company
+func (cr *CompanyRepo) HasCompany(id string) (bool, error) {
+	c := cr.sess.C(cr.coll)
+	n, err := c.Find(bson.M{"id": id}).Count()
+	return n > 0, err
 }
 
 // DeleteCompanyByID - set company as disabled from database by id
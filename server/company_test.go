@@ -24,6 +24,7 @@ func (ct *CompanyTestSuite) SetUpSuite(c *C) {
 	var err error
 	flag.Parse()
 	cfg := &server.Config{
+		Storage:              testStorage(),
 		EmailConfirmationTTL: time.Second * 5,
 		SMSConfirmationTTL:   time.Second * 2,
 	}
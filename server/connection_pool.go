@@ -1,24 +1,77 @@
 package server
 
-import "gopkg.in/mgo.v2"
+import (
+	"errors"
+	"gopkg.in/mgo.v2"
+	"strings"
+)
 
-// ConnectionPool - is pool of mongo connections
+// ErrUnknownReadPreference - error when read preference from config isn't supported by mongo
+var ErrUnknownReadPreference = errors.New("unknown mongo read preference")
+
+// ConnectionPool - is pool of mongo connections. It keeps one dialed master session, and every
+// request works with its own copy, which returns socket to the pool when it's closed
 type ConnectionPool struct {
+	session  *mgo.Session
+	database string
 }
 
-var connectionPoolInstance *ConnectionPool
-
-// NewConnectionPool - creates new mongo connection pool
-func NewConnectionPool() *ConnectionPool {
-	return &ConnectionPool{}
+// readPreferences - mongo read preferences by names used in mongo connection strings
+var readPreferences = map[string]mgo.Mode{
+	"primary":            mgo.Primary,
+	"primarypreferred":   mgo.PrimaryPreferred,
+	"secondary":          mgo.Secondary,
+	"secondarypreferred": mgo.SecondaryPreferred,
+	"nearest":            mgo.Nearest,
 }
 
-// GetConnection - get another free connection to mgo. Also it selects database for usage
-func (c *ConnectionPool) GetConnection() (*mgo.Database, error) {
-	session, err := mgo.Dial("mongodb")
+// NewConnectionPool - creates new mongo connection pool. It returns error if mongo is unreachable,
+// so server doesn't start without database
+func NewConnectionPool(cfg *Config) (*ConnectionPool, error) {
+	mode, ok := readPreferences[strings.ToLower(cfg.MongoReadPreference)]
+	if !ok {
+		return nil, ErrUnknownReadPreference
+	}
+
+	info, err := mgo.ParseURL(cfg.MongoURI)
+	if err != nil {
+		return nil, err
+	}
+
+	info.Timeout = cfg.MongoDialTimeout
+	if cfg.MongoPoolLimit > 0 {
+		info.PoolLimit = cfg.MongoPoolLimit
+	}
+
+	session, err := mgo.DialWithInfo(info)
 	if err != nil {
 		return nil, err
 	}
 
-	return session.DB("testdatabase"), nil
+	session.SetSocketTimeout(cfg.MongoSocketTimeout)
+	session.SetMode(mode, true)
+
+	if err := session.Ping(); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	// database from config has priority over database from uri
+	database := cfg.MongoDatabase
+	if database == "" {
+		database = info.Database
+	}
+
+	return &ConnectionPool{session: session, database: database}, nil
+}
+
+// GetConnection - get another free connection to mgo. Also it selects database for usage.
+// Connection should be returned to pool by sess.Session.Close()
+func (c *ConnectionPool) GetConnection() (*mgo.Database, error) {
+	return c.session.Copy().DB(c.database), nil
+}
+
+// Close - close master session and all free connections
+func (c *ConnectionPool) Close() {
+	c.session.Close()
 }
@@ -0,0 +1,30 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	. "gopkg.in/check.v1"
+	"time"
+)
+
+type ConnectionPoolTestSuite struct{}
+
+var _ = Suite(&ConnectionPoolTestSuite{})
+
+func (pt *ConnectionPoolTestSuite) TestUnknownReadPreference(c *C) {
+	_, err := server.NewConnectionPool(&server.Config{MongoURI: "mongodb://127.0.0.1/test", MongoReadPreference: "fastest"})
+	c.Assert(err, Equals, server.ErrUnknownReadPreference)
+}
+
+func (pt *ConnectionPoolTestSuite) TestUnreachable(c *C) {
+	cfg := &server.Config{
+		MongoURI:            "mongodb://127.0.0.1:1/test",
+		MongoReadPreference: "primary",
+		MongoDialTimeout:    time.Millisecond * 500,
+	}
+
+	// server fails on start instead of failing on every request
+	start := time.Now()
+	_, err := server.NewConnectionPool(cfg)
+	c.Assert(err, NotNil)
+	c.Assert(time.Since(start) < time.Second*5, Equals, true)
+}
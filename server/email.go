@@ -60,6 +60,17 @@ func (e *EmailSender) SendEmailConfirmation(name, email, code string) {
 	e.queue <- m
 }
 
+// SendPasswordReset - add email with password reset link to sending queue
+func (e *EmailSender) SendPasswordReset(name, email, token string) {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.config.EmailFrom)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("Password reset for %v", name))
+	m.SetBody("text/html", fmt.Sprintf("%s/reset-password/%v", e.config.ServerURL, token))
+
+	e.queue <- m
+}
+
 // sender - routine for sending emails to smtp-server
 func (e *EmailSender) sender() {
 	d := gomail.NewDialer(e.config.EmailSMTP, e.config.EmailSMTPPort, e.config.EmailUsername, e.config.EmailPassword)
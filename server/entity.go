@@ -3,6 +3,8 @@ package server
 import (
 	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
 	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"github.com/golang/protobuf/proto"
+	google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
 	"github.com/satori/go.uuid"
 	"golang.org/x/net/context"
 	"gopkg.in/mgo.v2"
@@ -11,7 +13,8 @@ import (
 )
 
 type entityServer struct {
-	sess *mgo.Session
+	config  *Config
+	storage Storage
 }
 
 // NewEntityResponse - create new instance of entity response
@@ -29,22 +32,36 @@ func NewEntityListResponse() *grpc_gateway_entity.EntityListResponse {
 	return message
 }
 
+// NewEntityImportResponse - create new instance of entity import response
+func NewEntityImportResponse() *grpc_gateway_entity.EntityImportResponse {
+	message := &grpc_gateway_entity.EntityImportResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	message.Data = &grpc_gateway_entity.EntityImport{}
+	return message
+}
+
 // NewEntityServer - returns new grpc server which provide entity-related functionality
-func NewEntityServer() grpc_gateway_entity.EntityServiceServer {
-	return new(entityServer)
+func NewEntityServer(config *Config, storage Storage) grpc_gateway_entity.EntityServiceServer {
+	return &entityServer{config: config, storage: storage}
 }
 
 func (es *entityServer) CreateEntity(ctx context.Context, entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.EntityResponse, error) {
 	message := NewEntityResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := es.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	currentUser, _ := GetCurrentUser(ctx)
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
 
 	if currentUser.CompanyId == "" {
 		message.Meta.Ok = false
@@ -58,8 +75,11 @@ func (es *entityServer) CreateEntity(ctx context.Context, entity *grpc_gateway_e
 	entity.Id = uuid.NewV4().String()
 	entity.Rev = 0
 	entity.Latest = true
+	entity.IsDeleted = false
+	entity.DeletedAt = 0
+	entity.DeletedBy = ""
 
-	entityRepo := NewEntityRepo(sess)
+	entityRepo := sess.Entities()
 	createdEntity, err := entityRepo.CreateEntity(entity)
 
 	if err != nil {
@@ -75,14 +95,20 @@ func (es *entityServer) CreateEntity(ctx context.Context, entity *grpc_gateway_e
 
 func (es *entityServer) UpdateEntity(ctx context.Context, entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.EntityResponse, error) {
 	message := NewEntityResponse()
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := es.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	currentUser, _ := GetCurrentUser(ctx)
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
 
 	if currentUser.CompanyId == "" {
 		message.Meta.Ok = false
@@ -95,12 +121,15 @@ func (es *entityServer) UpdateEntity(ctx context.Context, entity *grpc_gateway_e
 	entity.CreatedAt = time.Now().Unix()
 	entity.Latest = true
 
-	entityRepo := NewEntityRepo(sess)
-	message.Data, err = entityRepo.UpdateEntity(entity)
+	entityRepo := sess.Entities()
+	// rev of request is revision which was edited by caller
+	message.Data, err = entityRepo.UpdateEntity(entity, entity.Rev)
 
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			message.Meta.StatusCode = http.StatusNotFound
+		} else if err == ErrEntityConflict {
+			message.Meta.StatusCode = http.StatusConflict
 		}
 
 		message.Meta.Ok = false
@@ -128,19 +157,20 @@ func (es *entityServer) GetLatestEntity(ctx context.Context, in *grpc_gateway_co
 		return entity, nil
 	}
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := es.storage.Open()
 	if err != nil {
 		entity.Meta.Ok = false
 		entity.Meta.Error = err.Error()
 		return entity, nil
 	}
+	defer sess.Close()
 
 	companyID := ""
-	if !currentUser.IsAdmin {
+	if !IsPlatformAdmin(currentUser) {
 		companyID = currentUser.CompanyId
 	}
 
-	entityRepo := NewEntityRepo(sess)
+	entityRepo := sess.Entities()
 	entity.Data, err = entityRepo.GetLatestEntity(in.Id, companyID)
 	if err != nil {
 		if err == mgo.ErrNotFound {
@@ -168,9 +198,17 @@ func (es *entityServer) GetLatestEntity(ctx context.Context, in *grpc_gateway_co
 	return entity, nil
 }
 
-func (es *entityServer) GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_entity.EntityListResponse, error) {
+func (es *entityServer) GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_entity.EntityListResponse, error) {
 	entityList := NewEntityListResponse()
 
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, EntityRevisionSortFields, "-rev")
+	if err != nil {
+		entityList.Meta.StatusCode = http.StatusBadRequest
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+
 	currentUser, err := GetCurrentUser(ctx)
 	if err != nil {
 		entityList.Meta.Ok = false
@@ -184,20 +222,21 @@ func (es *entityServer) GetEntityRevisions(ctx context.Context, in *grpc_gateway
 		return entityList, nil
 	}
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := es.storage.Open()
 	if err != nil {
 		entityList.Meta.Ok = false
 		entityList.Meta.Error = err.Error()
 		return entityList, nil
 	}
+	defer sess.Close()
 
 	companyID := ""
-	if !currentUser.IsAdmin {
+	if !IsPlatformAdmin(currentUser) {
 		companyID = currentUser.CompanyId
 	}
 
-	entityRepo := NewEntityRepo(sess)
-	entityList, err = entityRepo.GetEntityRevs(in.Id, companyID)
+	entityRepo := sess.Entities()
+	entityList, err = entityRepo.GetEntityRevs(in.Id, companyID, page)
 
 	// Retrieve users information
 	createdBys := []string{}
@@ -244,21 +283,322 @@ func (es *entityServer) GetEntities(ctx context.Context, in *grpc_gateway_entity
 		return entityList, nil
 	}
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := es.storage.Open()
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+	defer sess.Close()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, EntitySortFields, "common_name")
+	if err != nil {
+		entityList.Meta.StatusCode = http.StatusBadRequest
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+
+	entityRepo := sess.Entities()
+	entityList, err = entityRepo.GetEntities(currentUser.CompanyId, in, page)
+
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+	} else {
+		entityList.Meta.Ok = true
+	}
+	return entityList, nil
+}
+
+func (es *entityServer) DeleteEntity(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_entity.EntityResponse, error) {
+	message := NewEntityResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	message.Data, err = sess.Entities().DeleteEntity(in.Id, currentUser.CompanyId, currentUser.Id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		} else if err == ErrEntityConflict {
+			message.Meta.StatusCode = http.StatusConflict
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+	} else {
+		message.Meta.Ok = true
+	}
+
+	return message, nil
+}
+
+func (es *entityServer) RestoreEntity(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_entity.EntityResponse, error) {
+	message := NewEntityResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	message.Data, err = sess.Entities().RestoreEntity(in.Id, currentUser.CompanyId, currentUser.Id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		} else if err == ErrEntityConflict {
+			message.Meta.StatusCode = http.StatusConflict
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+	} else {
+		message.Meta.Ok = true
+	}
+
+	return message, nil
+}
+
+func (es *entityServer) ListDeletedEntities(ctx context.Context, in *grpc_gateway_entity.EntityListRequest) (*grpc_gateway_entity.EntityListResponse, error) {
+	entityList := NewEntityListResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = ErrMissedRequiredField.Error()
+		return entityList, nil
+	}
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+	defer sess.Close()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, DeletedEntitySortFields, "-deleted_at")
+	if err != nil {
+		entityList.Meta.StatusCode = http.StatusBadRequest
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+
+	entityList, err = sess.Entities().GetDeletedEntities(currentUser.CompanyId, in, page)
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+	} else {
+		entityList.Meta.Ok = true
+	}
+
+	return entityList, nil
+}
+
+func (es *entityServer) SearchEntities(ctx context.Context, in *grpc_gateway_entity.EntitySearchRequest) (*grpc_gateway_entity.EntityListResponse, error) {
+	entityList := NewEntityListResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = ErrMissedRequiredField.Error()
+		return entityList, nil
+	}
+
+	sess, err := es.storage.Open()
 	if err != nil {
 		entityList.Meta.Ok = false
 		entityList.Meta.Error = err.Error()
 		return entityList, nil
 	}
+	defer sess.Close()
 
-	entityRepo := NewEntityRepo(sess)
-	entityList, err = entityRepo.GetEntities(currentUser.CompanyId, in)
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, EntitySortFields, "common_name")
+	if err != nil {
+		entityList.Meta.StatusCode = http.StatusBadRequest
+		entityList.Meta.Ok = false
+		entityList.Meta.Error = err.Error()
+		return entityList, nil
+	}
 
+	entityList, err = sess.Entities().SearchEntities(currentUser.CompanyId, in, page)
 	if err != nil {
 		entityList.Meta.Ok = false
 		entityList.Meta.Error = err.Error()
 	} else {
 		entityList.Meta.Ok = true
 	}
+
 	return entityList, nil
 }
+
+func (es *entityServer) PurgeDeletedEntities(ctx context.Context, in *google_protobuf1.Empty) (*grpc_gateway_entity.PurgeEntitiesResponse, error) {
+	message := &grpc_gateway_entity.PurgeEntitiesResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	// entities which were deleted within retention period can still be restored
+	deletedBefore := time.Now().Add(-es.config.EntityRetention).Unix()
+	purged, err := sess.Entities().PurgeDeletedEntities(deletedBefore)
+	message.Purged = int64(purged)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+	} else {
+		message.Meta.Ok = true
+	}
+
+	return message, nil
+}
+
+func (es *entityServer) ImportEntities(ctx context.Context, in *grpc_gateway_entity.EntityImportRequest) (*grpc_gateway_entity.EntityImportResponse, error) {
+	message := NewEntityImportResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	entityImport, err := newEntityImport(in, es.config)
+	if err != nil {
+		message.Meta.StatusCode = http.StatusBadRequest
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	entityImport.job.CompanyId = currentUser.CompanyId
+	entityImport.job.CreatedBy = currentUser.Id
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	// session of background import is opened before job is stored, so job isn't left pending without it
+	importSess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err := sess.EntityImports().CreateEntityImport(entityImport.job); err != nil {
+		importSess.Close()
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	// job is changed by background import, so response gets its copy
+	message.Data = proto.Clone(entityImport.job).(*grpc_gateway_entity.EntityImport)
+	go entityImport.run(importSess)
+
+	message.Meta.StatusCode = http.StatusAccepted
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (es *entityServer) GetEntityImport(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_entity.EntityImportResponse, error) {
+	message := NewEntityImportResponse()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if currentUser.CompanyId == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	sess, err := es.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	message.Data, err = sess.EntityImports().GetEntityImport(in.Id, currentUser.CompanyId)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+	} else {
+		message.Meta.Ok = true
+	}
+
+	return message, nil
+}
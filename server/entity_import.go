@@ -0,0 +1,657 @@
+package server
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"github.com/golang/glog"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of imported files
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// Modes of entity import: all rows are imported or none of them, or only rows which pass validation
+const (
+	EntityImportAllRows   = "all_rows"
+	EntityImportValidRows = "valid_rows"
+)
+
+// Statuses of entity import job. Running job stages entities, publishing job makes them visible
+const (
+	EntityImportPending    = "pending"
+	EntityImportRunning    = "running"
+	EntityImportPublishing = "publishing"
+	EntityImportCompleted  = "completed"
+	EntityImportFailed     = "failed"
+)
+
+// maxEntityImportErrors - limit of row errors which are stored with job
+const maxEntityImportErrors = 1000
+
+// entityImportProgressRows - job is saved after every this number of imported rows, so progress can be polled
+const entityImportProgressRows = 100
+
+// entityImportHeartbeat - running job is saved at least this often, so it isn't taken for stopped job
+const entityImportHeartbeat = time.Second * 10
+
+// ErrUnsupportedImportFormat - error when imported file isn't csv or xlsx
+var ErrUnsupportedImportFormat = errors.New("file format should be csv or xlsx")
+
+// ErrUnknownImportMode - error when import mode isn't all_rows or valid_rows
+var ErrUnknownImportMode = errors.New("import mode should be all_rows or valid_rows")
+
+// ErrEmptyImportFile - error when imported file doesn't have header or rows
+var ErrEmptyImportFile = errors.New("file should have header and at least one row")
+
+// ErrTooManyImportRows - error when imported file has more rows than allowed by config
+var ErrTooManyImportRows = errors.New("file has too many rows")
+
+// ErrImportFileTooLarge - error when imported file is larger than allowed by config
+var ErrImportFileTooLarge = errors.New("file is too large")
+
+// ErrEntityImportInterrupted - error of job which was stopped before its entities were published
+var ErrEntityImportInterrupted = errors.New("import was interrupted, nothing is imported")
+
+// ErrEntityImportTaken - error when import job was saved by another process, e.g. by recovery which finished it
+var ErrEntityImportTaken = errors.New("import job was saved by another process")
+
+// ErrEntityImportRejected - error of job in all_rows mode when some rows don't pass validation
+var ErrEntityImportRejected = errors.New("some rows have errors, nothing is imported")
+
+// entityImportStrings - string fields of entity which can be imported, by proto name
+var entityImportStrings = map[string]func(*grpc_gateway_entity.Entity) *string{
+	"common_name":           func(e *grpc_gateway_entity.Entity) *string { return &e.CommonName },
+	"type":                  func(e *grpc_gateway_entity.Entity) *string { return &e.Type },
+	"given_name":            func(e *grpc_gateway_entity.Entity) *string { return &e.GivenName },
+	"middle_name":           func(e *grpc_gateway_entity.Entity) *string { return &e.MiddleName },
+	"family_name":           func(e *grpc_gateway_entity.Entity) *string { return &e.FamilyName },
+	"name_prefix":           func(e *grpc_gateway_entity.Entity) *string { return &e.NamePrefix },
+	"name_suffix":           func(e *grpc_gateway_entity.Entity) *string { return &e.NameSuffix },
+	"gender":                func(e *grpc_gateway_entity.Entity) *string { return &e.Gender },
+	"birthday":              func(e *grpc_gateway_entity.Entity) *string { return &e.Birthday },
+	"birthplace":            func(e *grpc_gateway_entity.Entity) *string { return &e.Birthplace },
+	"birthcountry":          func(e *grpc_gateway_entity.Entity) *string { return &e.Birthcountry },
+	"nationality":           func(e *grpc_gateway_entity.Entity) *string { return &e.Nationality },
+	"kvk":                   func(e *grpc_gateway_entity.Entity) *string { return &e.Kvk },
+	"legal_form":            func(e *grpc_gateway_entity.Entity) *string { return &e.LegalForm },
+	"registered_name":       func(e *grpc_gateway_entity.Entity) *string { return &e.RegisteredName },
+	"registered_office":     func(e *grpc_gateway_entity.Entity) *string { return &e.RegisteredOffice },
+	"date_of_registration":  func(e *grpc_gateway_entity.Entity) *string { return &e.DateOfRegistration },
+	"date_of_establishment": func(e *grpc_gateway_entity.Entity) *string { return &e.DateOfEstablishment },
+	"trade_name":            func(e *grpc_gateway_entity.Entity) *string { return &e.TradeName },
+	"rsin":                  func(e *grpc_gateway_entity.Entity) *string { return &e.Rsin },
+	"issued_capital":        func(e *grpc_gateway_entity.Entity) *string { return &e.IssuedCapital },
+	"paidup_capital":        func(e *grpc_gateway_entity.Entity) *string { return &e.PaidupCapital },
+	"bfi_number":            func(e *grpc_gateway_entity.Entity) *string { return &e.BfiNumber },
+}
+
+// entityImportAddresses - addresses of entity, their fields are imported with name of address as prefix
+var entityImportAddresses = map[string]func(*grpc_gateway_entity.Entity) **grpc_gateway_common.Address{
+	"residential_address": func(e *grpc_gateway_entity.Entity) **grpc_gateway_common.Address { return &e.ResidentialAddress },
+	"visiting_address":    func(e *grpc_gateway_entity.Entity) **grpc_gateway_common.Address { return &e.VisitingAddress },
+	"registered_address":  func(e *grpc_gateway_entity.Entity) **grpc_gateway_common.Address { return &e.RegisteredAddress },
+}
+
+// addressImportFields - fields of address by proto name
+var addressImportFields = map[string]func(*grpc_gateway_common.Address) *string{
+	"address_line_1": func(a *grpc_gateway_common.Address) *string { return &a.AddressLine_1 },
+	"address_line_2": func(a *grpc_gateway_common.Address) *string { return &a.AddressLine_2 },
+	"city":           func(a *grpc_gateway_common.Address) *string { return &a.City },
+	"region":         func(a *grpc_gateway_common.Address) *string { return &a.Region },
+	"postal_code":    func(a *grpc_gateway_common.Address) *string { return &a.PostalCode },
+	"country":        func(a *grpc_gateway_common.Address) *string { return &a.Country },
+}
+
+// entityImportDates - fields with dates, they are converted to EntityDateLayout on import
+var entityImportDates = map[string]bool{
+	"birthday":              true,
+	"date_of_registration":  true,
+	"date_of_establishment": true,
+}
+
+// entityImportFlags - values of is_bfi column
+var entityImportFlags = map[string]bool{
+	"":      false,
+	"0":     false,
+	"no":    false,
+	"nee":   false,
+	"false": false,
+	"1":     true,
+	"yes":   true,
+	"ja":    true,
+	"true":  true,
+}
+
+// entityImportTarget - string field of entity by import name, address is created when its field is set.
+// Returns nil for unknown field
+func entityImportTarget(entity *grpc_gateway_entity.Entity, field string) *string {
+	if target, ok := entityImportStrings[field]; ok {
+		return target(entity)
+	}
+
+	parts := strings.SplitN(field, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	address, ok := entityImportAddresses[parts[0]]
+	target, known := addressImportFields[parts[1]]
+	if !ok || !known {
+		return nil
+	}
+
+	if *address(entity) == nil {
+		*address(entity) = &grpc_gateway_common.Address{}
+	}
+
+	return target(*address(entity))
+}
+
+// isEntityImportField - check if field can be imported
+func isEntityImportField(field string) bool {
+	return field == "is_bfi" || entityImportTarget(&grpc_gateway_entity.Entity{}, field) != nil
+}
+
+// normalizeImportDate - date in EntityDateLayout. Dates are accepted in format DD-MM-YYYY too and as serial
+// numbers of days, which spreadsheets store. Date in unknown format is returned as is and fails validation
+func normalizeImportDate(value string) string {
+	for _, layout := range []string{EntityDateLayout, "02-01-2006", "2-1-2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format(EntityDateLayout)
+		}
+	}
+
+	// serial 1 is 1900-01-01, spreadsheets count nonexistent 1900-02-29, so days are counted from 1899-12-30
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 2958466 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)).Format(EntityDateLayout)
+	}
+
+	return value
+}
+
+// importRow - cells of row of imported file, number is number of row in file where header is the first row
+type importRow struct {
+	number int64
+	cells  []string
+}
+
+// isEmpty - check if all cells of row are blank
+func (r *importRow) isEmpty() bool {
+	for _, cell := range r.cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cell - trimmed value of column, short rows don't have trailing empty cells
+func (r *importRow) cell(index int) string {
+	if index >= len(r.cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.cells[index])
+}
+
+// importTable - header and rows of imported file, empty rows are skipped
+type importTable struct {
+	header []string
+	rows   []*importRow
+}
+
+// newImportTable - table of rows of file, the first row which isn't empty is header
+func newImportTable(rows []*importRow) (*importTable, error) {
+	table := &importTable{}
+	for _, row := range rows {
+		if row.isEmpty() {
+			continue
+		}
+
+		if table.header == nil {
+			table.header = row.cells
+			continue
+		}
+
+		table.rows = append(table.rows, row)
+	}
+
+	if len(table.rows) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+
+	return table, nil
+}
+
+// readCSVTable - read csv file, separator is comma, semicolon or tab, whichever is used in the first line
+func readCSVTable(content []byte) (*importTable, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	firstLine := content
+	if end := bytes.IndexByte(content, '\n'); end >= 0 {
+		firstLine = content[:end]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	for _, separator := range []rune{';', '\t'} {
+		if bytes.Count(firstLine, []byte(string(separator))) > bytes.Count(firstLine, []byte(string(reader.Comma))) {
+			reader.Comma = separator
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*importRow{}
+	for i, record := range records {
+		rows = append(rows, &importRow{number: int64(i + 1), cells: record})
+	}
+
+	return newImportTable(rows)
+}
+
+// importFormat - format of imported file, it's detected by extension of file when it isn't set
+func importFormat(in *grpc_gateway_entity.EntityImportRequest) (string, error) {
+	format := strings.ToLower(in.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(in.FileName)), ".")
+	}
+
+	if format != ImportFormatCSV && format != ImportFormatXLSX {
+		return "", ErrUnsupportedImportFormat
+	}
+
+	return format, nil
+}
+
+// importColumn - column of file which is imported into entity field
+type importColumn struct {
+	index int
+	name  string
+	field string
+}
+
+// newImportColumns - columns of header which are imported. Without mapping all columns are imported and their
+// headers should be field names, with mapping only mapped columns are imported
+func newImportColumns(header []string, mapping []*grpc_gateway_entity.EntityImportColumn) ([]*importColumn, error) {
+	columns := []*importColumn{}
+	fields := map[string]bool{}
+
+	add := func(index int, name, field string) error {
+		if !isEntityImportField(field) {
+			return fmt.Errorf("column %q: unknown entity field %q", name, field)
+		}
+
+		if fields[field] {
+			return fmt.Errorf("column %q: field %q is imported from several columns", name, field)
+		}
+
+		fields[field] = true
+		columns = append(columns, &importColumn{index: index, name: name, field: field})
+		return nil
+	}
+
+	indexes := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := indexes[name]; ok {
+			return nil, fmt.Errorf("column %q is duplicated", name)
+		}
+		indexes[name] = i
+
+		if len(mapping) == 0 {
+			if err := add(i, name, strings.ToLower(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, column := range mapping {
+		index, ok := indexes[strings.TrimSpace(column.Column)]
+		if !ok {
+			return nil, fmt.Errorf("column %q isn't found in file", column.Column)
+		}
+
+		if err := add(index, column.Column, strings.TrimSpace(column.Field)); err != nil {
+			return nil, err
+		}
+	}
+
+	return columns, nil
+}
+
+// entityImport - file which is parsed and is imported by job in background
+type entityImport struct {
+	job     *grpc_gateway_entity.EntityImport
+	rows    []*importRow
+	columns []*importColumn
+
+	// savedStatus, savedAt - status and update time of job when it was saved last time
+	savedStatus string
+	savedAt     int64
+}
+
+// newEntityImport - read file of request and prepare job. Errors of file and mapping are returned
+// immediately, errors of rows are reported by job
+func newEntityImport(in *grpc_gateway_entity.EntityImportRequest, cfg *Config) (*entityImport, error) {
+	// size is checked before parsing, parsed file takes much more memory than its content
+	if len(in.Content) > cfg.EntityImportMaxBytes {
+		return nil, ErrImportFileTooLarge
+	}
+
+	format, err := importFormat(in)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := in.Mode
+	if mode == "" {
+		mode = EntityImportAllRows
+	}
+
+	if mode != EntityImportAllRows && mode != EntityImportValidRows {
+		return nil, ErrUnknownImportMode
+	}
+
+	var table *importTable
+	if format == ImportFormatCSV {
+		table, err = readCSVTable(in.Content)
+	} else {
+		table, err = readXLSXTable(in.Content)
+	}
+
+	if err == ErrEmptyImportFile {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("file can't be read: %v", err)
+	}
+
+	if len(table.rows) > cfg.EntityImportMaxRows {
+		return nil, ErrTooManyImportRows
+	}
+
+	columns, err := newImportColumns(table.header, in.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	job := &grpc_gateway_entity.EntityImport{
+		Id:        uuid.NewV4().String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		FileName:  in.FileName,
+		Format:    format,
+		Mode:      mode,
+		Status:    EntityImportPending,
+		TotalRows: int64(len(table.rows)),
+		Errors:    []*grpc_gateway_entity.EntityImportError{},
+	}
+
+	return &entityImport{job: job, rows: table.rows, columns: columns, savedStatus: job.Status, savedAt: job.UpdatedAt}, nil
+}
+
+// parseRow - entity of row and errors of its cells and entity rules. Common name is composed from names
+// when it's empty
+func (ei *entityImport) parseRow(row *importRow) (*grpc_gateway_entity.Entity, []*grpc_gateway_entity.EntityImportError) {
+	entity := &grpc_gateway_entity.Entity{}
+	errs := []*grpc_gateway_entity.EntityImportError{}
+	columns := map[string]string{}
+
+	for _, column := range ei.columns {
+		columns[column.field] = column.name
+		value := row.cell(column.index)
+
+		if column.field == "is_bfi" {
+			flag, ok := entityImportFlags[strings.ToLower(value)]
+			if !ok {
+				errs = append(errs, &grpc_gateway_entity.EntityImportError{
+					Row:     row.number,
+					Column:  column.name,
+					Field:   column.field,
+					Message: ErrEntityInvalidFlag.Error(),
+				})
+			}
+
+			entity.IsBfi = flag
+			continue
+		}
+
+		if value == "" {
+			continue
+		}
+
+		if entityImportDates[column.field] {
+			value = normalizeImportDate(value)
+		}
+
+		*entityImportTarget(entity, column.field) = value
+	}
+
+	if entity.CommonName == "" {
+		if entity.Type == EntityTypePerson {
+			entity.CommonName = strings.Join(strings.Fields(entity.GivenName+" "+entity.MiddleName+" "+entity.FamilyName), " ")
+		} else {
+			entity.CommonName = entity.RegisteredName
+		}
+	}
+
+	for _, fieldErr := range ValidateEntity(entity) {
+		errs = append(errs, &grpc_gateway_entity.EntityImportError{
+			Row:     row.number,
+			Column:  columns[fieldErr.Field],
+			Field:   fieldErr.Field,
+			Message: fieldErr.Err.Error(),
+		})
+	}
+
+	return entity, errs
+}
+
+// save - store state of job, if it wasn't saved by another process since it was saved last time. Job is reported
+// by its status even if it can't be saved because of storage. Returns ErrEntityImportTaken, if job was saved by
+// another process, then job should be stopped
+func (ei *entityImport) save(imports EntityImportStorage) error {
+	ei.job.UpdatedAt = time.Now().Unix()
+
+	err := imports.UpdateEntityImport(ei.job, ei.savedStatus, ei.savedAt)
+	if err == mgo.ErrNotFound {
+		glog.Warningf("entity import %s: %v", ei.job.Id, ErrEntityImportTaken)
+		return ErrEntityImportTaken
+	}
+	if err != nil {
+		glog.Errorf("entity import %s: %v", ei.job.Id, err)
+		return nil
+	}
+
+	ei.savedStatus = ei.job.Status
+	ei.savedAt = ei.job.UpdatedAt
+	return nil
+}
+
+// finish - store final state of job
+func (ei *entityImport) finish(imports EntityImportStorage, status string, err error) error {
+	ei.job.Status = status
+	ei.job.FinishedAt = time.Now().Unix()
+	if err != nil {
+		ei.job.Error = err.Error()
+	}
+
+	return ei.save(imports)
+}
+
+// stop - remove entities which job staged after it was finished by another process
+func (ei *entityImport) stop(entityRepo EntityStorage) {
+	if _, err := entityRepo.RemoveStagedEntities(ei.job.CompanyId, ei.job.Id); err != nil {
+		glog.Errorf("entity import %s: staged entities aren't removed: %v", ei.job.Id, err)
+	}
+}
+
+// run - validate all rows, stage entities of valid rows and publish them together. In all_rows mode nothing
+// is staged if any row is invalid. If storage fails, staged entities are removed and nothing is imported.
+// Job which is stopped with process is finished by RecoverEntityImports, then this job stops on next save
+func (ei *entityImport) run(sess StorageSession) {
+	defer sess.Close()
+
+	imports := sess.EntityImports()
+	entityRepo := sess.Entities()
+	ei.job.Status = EntityImportRunning
+	if ei.save(imports) != nil {
+		return
+	}
+
+	entities := []*grpc_gateway_entity.Entity{}
+	savedAt := time.Now()
+	for _, row := range ei.rows {
+		if time.Since(savedAt) > entityImportHeartbeat {
+			if ei.save(imports) != nil {
+				return
+			}
+			savedAt = time.Now()
+		}
+
+		entity, errs := ei.parseRow(row)
+		if len(errs) == 0 {
+			entities = append(entities, entity)
+			continue
+		}
+
+		ei.job.InvalidRows++
+		for _, rowErr := range errs {
+			if len(ei.job.Errors) < maxEntityImportErrors {
+				ei.job.Errors = append(ei.job.Errors, rowErr)
+			}
+		}
+	}
+
+	if ei.job.Mode == EntityImportAllRows && ei.job.InvalidRows > 0 {
+		ei.finish(imports, EntityImportFailed, ErrEntityImportRejected)
+		return
+	}
+
+	for _, entity := range entities {
+		entity.Id = uuid.NewV4().String()
+		entity.CompanyId = ei.job.CompanyId
+		entity.CreatedBy = ei.job.CreatedBy
+		entity.CreatedAt = time.Now().Unix()
+
+		if err := entityRepo.StageEntity(entity, ei.job.Id); err != nil {
+			// job which isn't finished is recovered later, if staged entities can't be removed now
+			if _, removeErr := entityRepo.RemoveStagedEntities(ei.job.CompanyId, ei.job.Id); removeErr != nil {
+				glog.Errorf("entity import %s: staged entities aren't removed: %v", ei.job.Id, removeErr)
+				return
+			}
+
+			ei.job.ImportedRows = 0
+			ei.finish(imports, EntityImportFailed, err)
+			return
+		}
+
+		ei.job.ImportedRows++
+		if ei.job.ImportedRows%entityImportProgressRows == 0 || time.Since(savedAt) > entityImportHeartbeat {
+			if ei.save(imports) != nil {
+				ei.stop(entityRepo)
+				return
+			}
+			savedAt = time.Now()
+		}
+	}
+
+	ei.job.Status = EntityImportPublishing
+	if ei.save(imports) != nil {
+		ei.stop(entityRepo)
+		return
+	}
+
+	// publishing job is finished by recovery, if entities can't be published now
+	if _, err := entityRepo.PublishStagedEntities(ei.job.CompanyId, ei.job.Id); err != nil {
+		glog.Errorf("entity import %s: staged entities aren't published: %v", ei.job.Id, err)
+		return
+	}
+
+	ei.finish(imports, EntityImportCompleted, nil)
+}
+
+// RecoverEntityImports - finish import jobs which weren't saved for staleAfter, because their process was
+// stopped. Entities of publishing jobs are published, other jobs fail before their staged entities are removed,
+// so their processes can't publish them anymore. Returns number of finished jobs
+func RecoverEntityImports(storage Storage, staleAfter time.Duration) (int, error) {
+	sess, err := storage.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer sess.Close()
+
+	imports := sess.EntityImports()
+	entityRepo := sess.Entities()
+
+	jobs, err := imports.GetStaleEntityImports(time.Now().Add(-staleAfter).Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	finished := 0
+	for _, job := range jobs {
+		ei := &entityImport{job: job, savedStatus: job.Status, savedAt: job.UpdatedAt}
+
+		if job.Status == EntityImportPublishing {
+			if _, err := entityRepo.PublishStagedEntities(job.CompanyId, job.Id); err != nil {
+				return finished, err
+			}
+
+			if ei.finish(imports, EntityImportCompleted, nil) == nil {
+				finished++
+			}
+			continue
+		}
+
+		// job which was saved meanwhile isn't stopped
+		job.ImportedRows = 0
+		if ei.finish(imports, EntityImportFailed, ErrEntityImportInterrupted) != nil {
+			continue
+		}
+
+		if _, err := entityRepo.RemoveStagedEntities(job.CompanyId, job.Id); err != nil {
+			return finished, err
+		}
+
+		finished++
+	}
+
+	return finished, nil
+}
+
+// RunEntityImportRecovery - finish stopped import jobs periodically
+func RunEntityImportRecovery(storage Storage, staleAfter time.Duration) {
+	for {
+		recovered, err := RecoverEntityImports(storage, staleAfter)
+		if err != nil {
+			glog.Error(err)
+		} else if recovered > 0 {
+			glog.Infof("%d stopped entity imports are finished", recovered)
+		}
+
+		time.Sleep(staleAfter)
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// EntityImportRepo - model for accessing entity import jobs in database
+type EntityImportRepo struct {
+	sess *mgo.Database
+	coll string
+}
+
+// NewEntityImportRepo - returns new instance of EntityImportRepo
+func NewEntityImportRepo(sess *mgo.Database) *EntityImportRepo {
+	return &EntityImportRepo{
+		sess: sess,
+		coll: "entity_imports",
+	}
+}
+
+// CreateEntityImport - store new import job
+func (ir *EntityImportRepo) CreateEntityImport(job *grpc_gateway_entity.EntityImport) error {
+	c := ir.sess.C(ir.coll)
+	return c.Insert(job)
+}
+
+// UpdateEntityImport - replace stored import job with its current state, if stored job still has status and
+// update time which it had when it was saved last time. Returns mgo.ErrNotFound if job was saved by another process
+func (ir *EntityImportRepo) UpdateEntityImport(job *grpc_gateway_entity.EntityImport, status string, updatedAt int64) error {
+	c := ir.sess.C(ir.coll)
+	return c.Update(bson.M{"id": job.Id, "status": status, "updatedat": updatedAt}, job)
+}
+
+// GetEntityImport - get import job of company by id
+func (ir *EntityImportRepo) GetEntityImport(id, companyID string) (*grpc_gateway_entity.EntityImport, error) {
+	c := ir.sess.C(ir.coll)
+	var job grpc_gateway_entity.EntityImport
+
+	err := c.Find(bson.M{"id": id, "companyid": companyID}).One(&job)
+	return &job, err
+}
+
+// GetStaleEntityImports - unfinished import jobs which weren't saved since updatedBefore
+func (ir *EntityImportRepo) GetStaleEntityImports(updatedBefore int64) ([]*grpc_gateway_entity.EntityImport, error) {
+	c := ir.sess.C(ir.coll)
+	jobs := []*grpc_gateway_entity.EntityImport{}
+
+	err := c.Find(bson.M{
+		"status":    bson.M{"$in": []string{EntityImportPending, EntityImportRunning, EntityImportPublishing}},
+		"updatedat": bson.M{"$lt": updatedBefore},
+	}).All(&jobs)
+	return jobs, err
+}
@@ -0,0 +1,289 @@
+package server_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"net/http"
+	"time"
+)
+
+// importTestCSV - person, company with errors and company, the second row has wrong kvk, no name
+// and bfi flag without bfi number
+const importTestCSV = "Soort;Voornaam;Achternaam;Naam;Geboortedatum;KvK;BFI;Stad;Land\n" +
+	"person;Jan;Jansen;;31-12-1980;;nee;Amsterdam;Nederland\n" +
+	"company;;;;;1234;ja;;\n" +
+	";;;;;;;;\n" +
+	"company;;;Jansen Holding;;12345678;;Utrecht;Nederland\n"
+
+// importTestMapping - mapping of columns of importTestCSV
+var importTestMapping = []*grpc_gateway_entity.EntityImportColumn{
+	{Column: "Soort", Field: "type"},
+	{Column: "Voornaam", Field: "given_name"},
+	{Column: "Achternaam", Field: "family_name"},
+	{Column: "Naam", Field: "common_name"},
+	{Column: "Geboortedatum", Field: "birthday"},
+	{Column: "KvK", Field: "kvk"},
+	{Column: "BFI", Field: "is_bfi"},
+	{Column: "Stad", Field: "residential_address.city"},
+	{Column: "Land", Field: "residential_address.country"},
+}
+
+// createImportTestUser - token of user of new company
+func createImportTestUser(c *C) string {
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, getTestDefaultAuthToken(), companyId, false)
+	c.Assert(err, IsNil)
+
+	return getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+}
+
+// startTestImport - start import of entities
+func startTestImport(c *C, token string, in *grpc_gateway_entity.EntityImportRequest) *grpc_gateway_entity.EntityImportResponse {
+	message := server.NewEntityImportResponse()
+	c.Assert(postTestRequest("http://127.0.0.1:8080/v1/entity_import", token, in, message), IsNil)
+	return message
+}
+
+// waitTestImport - poll import job until it's finished
+func waitTestImport(c *C, token, id string) *grpc_gateway_entity.EntityImport {
+	for i := 0; i < 50; i++ {
+		message := server.NewEntityImportResponse()
+		c.Assert(sendTestRequest("GET", "http://127.0.0.1:8080/v1/entity_import/"+id, token, message), IsNil)
+		c.Assert(message.Meta.Ok, Equals, true)
+
+		if message.Data.Status == server.EntityImportCompleted || message.Data.Status == server.EntityImportFailed {
+			return message.Data
+		}
+
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	c.Fatalf("import %s isn't finished", id)
+	return nil
+}
+
+// listTestEntities - entities of company of user
+func listTestEntities(c *C, token string) []*grpc_gateway_entity.Entity {
+	message := server.NewEntityListResponse()
+	c.Assert(sendTestRequest("GET", "http://127.0.0.1:8080/v1/entity?sort=common_name", token, message), IsNil)
+	c.Assert(message.Meta.Ok, Equals, true)
+	return message.Data
+}
+
+func (m *EntityTestSuite) TestImportAllRows(c *C) {
+	token := createImportTestUser(c)
+
+	started := startTestImport(c, token, &grpc_gateway_entity.EntityImportRequest{
+		FileName: "clients.csv",
+		Content:  []byte(importTestCSV),
+		Mapping:  importTestMapping,
+	})
+	c.Assert(started.Meta.Ok, Equals, true)
+	c.Assert(started.Meta.StatusCode, Equals, int32(http.StatusAccepted))
+	c.Assert(started.Data.Format, Equals, server.ImportFormatCSV)
+	c.Assert(started.Data.Mode, Equals, server.EntityImportAllRows)
+	c.Assert(started.Data.TotalRows, Equals, int64(3))
+
+	// one invalid row rejects the whole file
+	job := waitTestImport(c, token, started.Data.Id)
+	c.Assert(job.Status, Equals, server.EntityImportFailed)
+	c.Assert(job.Error, Equals, server.ErrEntityImportRejected.Error())
+	c.Assert(job.InvalidRows, Equals, int64(1))
+	c.Assert(job.ImportedRows, Equals, int64(0))
+	c.Assert(job.Errors, DeepEquals, []*grpc_gateway_entity.EntityImportError{
+		{Row: 3, Column: "Naam", Field: "common_name", Message: server.ErrEntityFieldRequired.Error()},
+		{Row: 3, Column: "KvK", Field: "kvk", Message: server.ErrEntityInvalidKvk.Error()},
+		{Row: 3, Column: "", Field: "bfi_number", Message: server.ErrEntityFieldRequired.Error()},
+	})
+	c.Assert(listTestEntities(c, token), HasLen, 0)
+
+	// job of another company isn't found
+	other := server.NewEntityImportResponse()
+	c.Assert(sendTestRequest("GET", "http://127.0.0.1:8080/v1/entity_import/"+job.Id, createImportTestUser(c), other), IsNil)
+	c.Assert(other.Meta.Ok, Equals, false)
+	c.Assert(other.Meta.StatusCode, Equals, int32(http.StatusNotFound))
+}
+
+func (m *EntityTestSuite) TestImportValidRows(c *C) {
+	token := createImportTestUser(c)
+
+	started := startTestImport(c, token, &grpc_gateway_entity.EntityImportRequest{
+		FileName: "clients.csv",
+		Content:  []byte(importTestCSV),
+		Mapping:  importTestMapping,
+		Mode:     server.EntityImportValidRows,
+	})
+	c.Assert(started.Meta.Ok, Equals, true)
+
+	job := waitTestImport(c, token, started.Data.Id)
+	c.Assert(job.Status, Equals, server.EntityImportCompleted)
+	c.Assert(job.Error, Equals, "")
+	c.Assert(job.InvalidRows, Equals, int64(1))
+	c.Assert(job.ImportedRows, Equals, int64(2))
+	c.Assert(job.Errors, HasLen, 3)
+
+	entities := listTestEntities(c, token)
+	c.Assert(entities, HasLen, 2)
+
+	// common name of person is composed from names, dates are stored in one format
+	person := entities[0]
+	c.Assert(person.CommonName, Equals, "Jan Jansen")
+	c.Assert(person.Type, Equals, server.EntityTypePerson)
+	c.Assert(person.Birthday, Equals, "1980-12-31")
+	c.Assert(person.ResidentialAddress.City, Equals, "Amsterdam")
+	c.Assert(person.ResidentialAddress.Country, Equals, "Nederland")
+	c.Assert(person.Latest, Equals, true)
+
+	company := entities[1]
+	c.Assert(company.CommonName, Equals, "Jansen Holding")
+	c.Assert(company.Kvk, Equals, "12345678")
+	c.Assert(company.CreatedBy, Equals, job.CreatedBy)
+}
+
+func (m *EntityTestSuite) TestImportXLSX(c *C) {
+	token := createImportTestUser(c)
+
+	// sheet has shared strings, inline string and birthday as serial number of day
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Clients" sheetId="1" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Type="sharedStrings" Target="sharedStrings.xml"/>
+			<Relationship Id="rId2" Type="worksheet" Target="worksheets/clients.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>type</t></si><si><t>family_name</t></si><si><t>birthday</t></si>
+			<si><t>person</t></si><si><r><t>de </t></r><r><t>Vries</t></r></si></sst>`,
+		"xl/worksheets/clients.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="s"><v>2</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>3</v></c><c r="B3" t="s"><v>4</v></c><c r="D3"><v>29587</v></c></row>
+			<row r="4"><c r="A4" t="inlineStr"><is><t>person</t></is></c><c r="D4" t="inlineStr"><is><t>1990-13-01</t></is></c></row>
+			</sheetData></worksheet>`,
+	}
+
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		c.Assert(err, IsNil)
+		_, err = w.Write([]byte(content))
+		c.Assert(err, IsNil)
+	}
+	c.Assert(archive.Close(), IsNil)
+
+	started := startTestImport(c, token, &grpc_gateway_entity.EntityImportRequest{
+		FileName: "clients.XLSX",
+		Content:  buf.Bytes(),
+		Mode:     server.EntityImportValidRows,
+	})
+	c.Assert(started.Meta.Ok, Equals, true)
+	c.Assert(started.Data.Format, Equals, server.ImportFormatXLSX)
+
+	job := waitTestImport(c, token, started.Data.Id)
+	c.Assert(job.Status, Equals, server.EntityImportCompleted)
+	c.Assert(job.ImportedRows, Equals, int64(1))
+	c.Assert(job.Errors, DeepEquals, []*grpc_gateway_entity.EntityImportError{
+		{Row: 4, Column: "family_name", Field: "family_name", Message: server.ErrEntityFieldRequired.Error()},
+		{Row: 4, Column: "birthday", Field: "birthday", Message: server.ErrEntityInvalidDate.Error()},
+	})
+
+	entities := listTestEntities(c, token)
+	c.Assert(entities, HasLen, 1)
+	c.Assert(entities[0].CommonName, Equals, "de Vries")
+	c.Assert(entities[0].Birthday, Equals, "1981-01-01")
+}
+
+func (m *EntityTestSuite) TestImportInvalidRequest(c *C) {
+	token := createImportTestUser(c)
+
+	for _, test := range []struct {
+		in    *grpc_gateway_entity.EntityImportRequest
+		error string
+	}{
+		{&grpc_gateway_entity.EntityImportRequest{FileName: "clients.pdf", Content: []byte(importTestCSV)}, server.ErrUnsupportedImportFormat.Error()},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: []byte(importTestCSV), Mode: "some"}, server.ErrUnknownImportMode.Error()},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: []byte("type,family_name\n")}, server.ErrEmptyImportFile.Error()},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: []byte(importTestCSV)}, `column "Soort": unknown entity field "soort"`},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: []byte(importTestCSV), Mapping: []*grpc_gateway_entity.EntityImportColumn{
+			{Column: "Stad", Field: "visiting_address.street"},
+		}}, `column "Stad": unknown entity field "visiting_address.street"`},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: []byte(importTestCSV), Mapping: []*grpc_gateway_entity.EntityImportColumn{
+			{Column: "Plaats", Field: "visiting_address.city"},
+		}}, `column "Plaats" isn't found in file`},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "xlsx", Content: []byte(importTestCSV)}, "file can't be read: zip: not a valid zip file"},
+		{&grpc_gateway_entity.EntityImportRequest{Format: "csv", Content: bytes.Repeat([]byte("a"), 3<<20+1)}, server.ErrImportFileTooLarge.Error()},
+	} {
+		message := startTestImport(c, token, test.in)
+		c.Assert(message.Meta.Ok, Equals, false)
+		c.Assert(message.Meta.StatusCode, Equals, int32(http.StatusBadRequest))
+		c.Assert(message.Meta.Error, Equals, test.error)
+	}
+}
+
+func (m *EntityTestSuite) TestImportRecovery(c *C) {
+	storage := server.NewMemoryStorage()
+	sess, err := storage.Open()
+	c.Assert(err, IsNil)
+
+	// process of running job was stopped after it staged entity, job of publishing process was stopped
+	// before it finished, job which is saved recently is still running
+	stale := time.Now().Add(-time.Hour).Unix()
+	for _, job := range []*grpc_gateway_entity.EntityImport{
+		{Id: "running", CompanyId: "c1", Status: server.EntityImportRunning, ImportedRows: 1, UpdatedAt: stale},
+		{Id: "publishing", CompanyId: "c1", Status: server.EntityImportPublishing, ImportedRows: 1, UpdatedAt: stale},
+		{Id: "active", CompanyId: "c1", Status: server.EntityImportRunning, UpdatedAt: time.Now().Unix()},
+		{Id: "completed", CompanyId: "c1", Status: server.EntityImportCompleted, UpdatedAt: stale},
+	} {
+		c.Assert(sess.EntityImports().CreateEntityImport(job), IsNil)
+	}
+	c.Assert(sess.Entities().StageEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, "running"), IsNil)
+	c.Assert(sess.Entities().StageEntity(&grpc_gateway_entity.Entity{Id: "e2", CompanyId: "c1"}, "publishing"), IsNil)
+	c.Assert(sess.Entities().StageEntity(&grpc_gateway_entity.Entity{Id: "e3", CompanyId: "c1"}, "active"), IsNil)
+
+	recovered, err := server.RecoverEntityImports(storage, time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(recovered, Equals, 2)
+
+	job, err := sess.EntityImports().GetEntityImport("running", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, server.EntityImportFailed)
+	c.Assert(job.Error, Equals, server.ErrEntityImportInterrupted.Error())
+	c.Assert(job.ImportedRows, Equals, int64(0))
+
+	has, err := sess.Entities().HasEntity("e1")
+	c.Assert(err, IsNil)
+	c.Assert(has, Equals, false)
+
+	// process of job which was slow and taken for stopped can't overwrite status which recovery saved
+	err = sess.EntityImports().UpdateEntityImport(&grpc_gateway_entity.EntityImport{
+		Id:        "running",
+		CompanyId: "c1",
+		Status:    server.EntityImportCompleted,
+		UpdatedAt: time.Now().Unix(),
+	}, server.EntityImportRunning, stale)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	job, err = sess.EntityImports().GetEntityImport("running", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, server.EntityImportFailed)
+
+	job, err = sess.EntityImports().GetEntityImport("publishing", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, server.EntityImportCompleted)
+
+	_, err = sess.Entities().GetLatestEntity("e2", "c1")
+	c.Assert(err, IsNil)
+
+	job, err = sess.EntityImports().GetEntityImport("active", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(job.Status, Equals, server.EntityImportRunning)
+
+	_, err = sess.Entities().GetLatestEntity("e3", "c1")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize - limit of unpacked part of xlsx file, it protects from archives which unpack into huge files
+const maxXLSXPartSize = 64 << 20
+
+// ErrXLSXPartTooLarge - error when part of xlsx file is larger than maxXLSXPartSize after unpacking
+var ErrXLSXPartTooLarge = errors.New("xlsx file is too large")
+
+// xlsxWorkbook - sheets of workbook in order of tabs
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships - parts of workbook by relation id
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText - text which is plain or consists of formatted runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t *xlsxText) String() string {
+	text := t.Text
+	for _, run := range t.Runs {
+		text += run.Text
+	}
+
+	return text
+}
+
+// xlsxSharedStrings - strings which are referenced by index from cells
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxWorksheet - rows of worksheet, empty rows and cells are omitted in file
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int64 `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXPart - unmarshal xml part of xlsx archive, missing part is returned as io.EOF
+func readXLSXPart(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return io.EOF
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(rc, maxXLSXPartSize+1))
+	if err != nil {
+		return err
+	}
+
+	if len(data) > maxXLSXPartSize {
+		return ErrXLSXPartTooLarge
+	}
+
+	return xml.Unmarshal(data, v)
+}
+
+// xlsxFirstSheet - name of part with the first worksheet of workbook
+func xlsxFirstSheet(files map[string]*zip.File) (string, error) {
+	workbook := xlsxWorkbook{}
+	if err := readXLSXPart(files, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	relationships := xlsxRelationships{}
+	if err := readXLSXPart(files, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", ErrEmptyImportFile
+	}
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID != workbook.Sheets[0].RelationID {
+			continue
+		}
+
+		// targets are relative to workbook part, but some writers use absolute paths
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+
+		return path.Join("xl", relationship.Target), nil
+	}
+
+	return "", io.EOF
+}
+
+// xlsxColumn - zero-based index of column of cell reference, e.g. 2 for C7
+func xlsxColumn(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+
+		column = column*26 + int(r-'A') + 1
+	}
+
+	return column - 1
+}
+
+// readXLSXTable - read the first worksheet of xlsx file. Numbers and dates are read as stored, dates are
+// serial numbers of days
+func readXLSXTable(content []byte) (*importTable, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheet, err := xlsxFirstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// workbook without text cells doesn't have shared strings
+	sharedStrings := xlsxSharedStrings{}
+	if err := readXLSXPart(files, "xl/sharedStrings.xml", &sharedStrings); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	worksheet := xlsxWorksheet{}
+	if err := readXLSXPart(files, sheet, &worksheet); err != nil {
+		return nil, err
+	}
+
+	rows := []*importRow{}
+	number := int64(0)
+	for _, row := range worksheet.Rows {
+		number++
+		if row.Number > 0 {
+			number = row.Number
+		}
+
+		cells := []string{}
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = xlsxColumn(cell.Ref)
+			}
+
+			if column < 0 || column < len(cells) {
+				continue
+			}
+
+			for len(cells) < column {
+				cells = append(cells, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(value)
+				if err != nil || index < 0 || index >= len(sharedStrings.Items) {
+					return nil, errors.New("xlsx cell " + cell.Ref + " references unknown string")
+				}
+				value = sharedStrings.Items[index].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			}
+
+			cells = append(cells, value)
+		}
+
+		rows = append(rows, &importRow{number: number, cells: cells})
+	}
+
+	return newImportTable(rows)
+}
@@ -2,108 +2,740 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
 	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"github.com/golang/glog"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
+	"time"
 )
 
 // ErrMissedRequiredField - error when cannot find required field
 var ErrMissedRequiredField = errors.New("cannot find required field")
 
+// ErrEntityConflict - error when entity is updated from revision which isn't latest anymore
+var ErrEntityConflict = errors.New("entity was changed by another update")
+
+// entityUpdateTTL - revision which stopped being latest for update or purge becomes latest again after this time,
+// if new revision wasn't stored or revisions weren't removed
+const entityUpdateTTL = time.Second * 30
+
+// ErrEntityInvariantsViolated - error when stored entities can't be indexed because they violate invariants of revisions
+var ErrEntityInvariantsViolated = errors.New("stored entities violate invariants of revisions")
+
+// Problems of stored entities which are reported by CheckInvariants
+const (
+	EntityDuplicateRev   = "duplicated revision"
+	EntityNoLatest       = "no latest revision"
+	EntityMultipleLatest = "multiple latest revisions"
+)
+
+// EntityViolation - entity which violates invariants of revisions. Count is number of duplicated documents
+// or number of latest revisions
+type EntityViolation struct {
+	EntityID string
+	Rev      int64
+	Problem  string
+	Count    int
+}
+
 // EntityRepo - model for accessing entitys in database
 type EntityRepo struct {
-	sess *mgo.Database
-	coll string
+	sess   *mgo.Database
+	coll   string
+	cipher *FieldCipher
 }
 
 // NewEntityRepo - returns new instance of EntityRepo which provide access to entity model
 func NewEntityRepo(sess *mgo.Database) *EntityRepo {
 	return &EntityRepo{
-		sess: sess,
-		coll: "entities",
+		sess:   sess,
+		coll:   "entities",
+		cipher: GetFieldCipher(),
 	}
 }
 
+// entitySecrets - personal data of entity which is sealed in stored documents
+type entitySecrets struct {
+	Birthday           string
+	Birthplace         string
+	Nationality        string
+	ResidentialAddress *grpc_gateway_common.Address
+	BfiNumber          string
+}
+
+// storedEntity - stored document of entity with fields for search. When encryption is configured
+// personal data is kept only in sealed fields
+type storedEntity struct {
+	grpc_gateway_entity.Entity `bson:",inline"`
+	Search                     entitySearchKeys `bson:",inline"`
+	Sealed                     *SealedFields    `bson:"sealed,omitempty"`
+
+	// ImportID - import which staged revision, staged revision isn't latest until import is published
+	ImportID string `bson:"importid,omitempty"`
+}
+
+// entityAAD - sealed fields are bound to revision of entity
+func entityAAD(entity *grpc_gateway_entity.Entity) string {
+	return fmt.Sprintf("entities:%s:%s:%d", entity.CompanyId, entity.Id, entity.Rev)
+}
+
+// document - stored document of entity, personal data is sealed if cipher is configured
+func (ur *EntityRepo) document(entity *grpc_gateway_entity.Entity) (*storedEntity, error) {
+	doc := &storedEntity{
+		Entity: *cloneEntity(entity),
+		Search: *newEntitySearchKeys(entity, ur.cipher),
+	}
+
+	if ur.cipher == nil {
+		return doc, nil
+	}
+
+	sealed, err := ur.cipher.Seal(&entitySecrets{
+		Birthday:           entity.Birthday,
+		Birthplace:         entity.Birthplace,
+		Nationality:        entity.Nationality,
+		ResidentialAddress: entity.ResidentialAddress,
+		BfiNumber:          entity.BfiNumber,
+	}, entityAAD(entity))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Sealed = sealed
+	doc.Birthday = ""
+	doc.Birthplace = ""
+	doc.Nationality = ""
+	doc.ResidentialAddress = nil
+	doc.BfiNumber = ""
+	return doc, nil
+}
+
+// open - entity of stored document with opened personal data
+func (ur *EntityRepo) open(doc *storedEntity) (*grpc_gateway_entity.Entity, error) {
+	entity := cloneEntity(&doc.Entity)
+	if doc.Sealed == nil {
+		return entity, nil
+	}
+
+	secrets := entitySecrets{}
+	if err := ur.cipher.Open(doc.Sealed, entityAAD(entity), &secrets); err != nil {
+		return nil, err
+	}
+
+	entity.Birthday = secrets.Birthday
+	entity.Birthplace = secrets.Birthplace
+	entity.Nationality = secrets.Nationality
+	entity.ResidentialAddress = secrets.ResidentialAddress
+	entity.BfiNumber = secrets.BfiNumber
+	return entity, nil
+}
+
+// openAll - entities of stored documents
+func (ur *EntityRepo) openAll(docs []*storedEntity) ([]*grpc_gateway_entity.Entity, error) {
+	entities := []*grpc_gateway_entity.Entity{}
+	for _, doc := range docs {
+		entity, err := ur.open(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// findEntities - find page of entities which match query
+func (ur *EntityRepo) findEntities(query bson.M, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	c := ur.sess.C(ur.coll)
+	entities := NewEntityListResponse()
+
+	docs := []*storedEntity{}
+	next, total, err := findPage(c, query, page, &docs)
+	if err != nil {
+		return entities, err
+	}
+
+	entities.Data, err = ur.openAll(docs)
+	entities.NextCursor = next
+	entities.Total = int64(total)
+	return entities, err
+}
+
 // CreateEntity - create new entity
 func (ur *EntityRepo) CreateEntity(entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error) {
 	c := ur.sess.C(ur.coll)
 
-	return entity, c.Insert(entity)
+	doc, err := ur.document(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity, c.Insert(doc)
 }
 
-// GetLatestEntity - get entity from database by id
+// GetLatestEntity - get entity from database by id, deleted entities aren't returned
 func (ur *EntityRepo) GetLatestEntity(id, companyID string) (*grpc_gateway_entity.Entity, error) {
+	entity, err := ur.findLatest(id, companyID)
+	if err == nil && entity.IsDeleted {
+		return &grpc_gateway_entity.Entity{}, mgo.ErrNotFound
+	}
+
+	return entity, err
+}
+
+// findLatest - get latest revision of entity, which can be deleted. Update takes latest flag of base revision
+// before it stores new revision, so base revision of running update is taken when entity doesn't have latest revision
+func (ur *EntityRepo) findLatest(id, companyID string) (*grpc_gateway_entity.Entity, error) {
+	c := ur.sess.C(ur.coll)
+	query := bson.M{"id": id, "companyid": companyID, "latest": true}
+
+	doc := storedEntity{}
+	err := c.Find(query).Sort("-rev").One(&doc)
+	if err == mgo.ErrNotFound && ur.recoverLatest(id, companyID) {
+		err = c.Find(query).Sort("-rev").One(&doc)
+	}
+	if err == mgo.ErrNotFound {
+		err = c.Find(bson.M{
+			"id":         id,
+			"companyid":  companyID,
+			"latest":     false,
+			"updatingat": bson.M{"$exists": true},
+		}).Sort("-rev").One(&doc)
+		doc.Latest = true
+	}
+	if err != nil {
+		return &grpc_gateway_entity.Entity{}, err
+	}
+
+	return ur.open(&doc)
+}
+
+// revisionState - fields of stored revision which are used for recovery of latest revision
+type revisionState struct {
+	Rev        int64 `bson:"rev"`
+	Latest     bool  `bson:"latest"`
+	UpdatingAt int64 `bson:"updatingat"`
+}
+
+// recoverLatest - make the newest revision latest again, if update or purge which took its latest flag didn't
+// finish in time. Flags of older revisions which were left by finished updates are cleared.
+// Returns true when latest revision was recovered
+func (ur *EntityRepo) recoverLatest(id, companyID string) bool {
 	c := ur.sess.C(ur.coll)
-	ent := grpc_gateway_entity.Entity{}
-	err := c.Find(bson.M{"id": id, "companyid": companyID, "latest": true}).One(&ent)
-	return &ent, err
+	staleBefore := time.Now().Add(-entityUpdateTTL).Unix()
+
+	newest := revisionState{}
+	err := c.Find(bson.M{"id": id, "companyid": companyID, "importid": bson.M{"$exists": false}}).
+		Sort("-rev").Select(bson.M{"rev": 1, "latest": 1, "updatingat": 1}).One(&newest)
+	if err != nil {
+		if err != mgo.ErrNotFound {
+			glog.Error(err)
+		}
+
+		return false
+	}
+
+	if newest.Latest {
+		_, err := c.UpdateAll(
+			bson.M{"id": id, "companyid": companyID, "latest": false, "rev": bson.M{"$lt": newest.Rev}, "updatingat": bson.M{"$lt": staleBefore}},
+			bson.M{"$unset": bson.M{"updatingat": ""}},
+		)
+		if err != nil {
+			glog.Error(err)
+		}
+
+		return false
+	}
+
+	if newest.UpdatingAt == 0 || newest.UpdatingAt >= staleBefore {
+		return false
+	}
+
+	// update which took the flag again or finished meanwhile makes recovery fail
+	err = c.Update(
+		bson.M{"id": id, "companyid": companyID, "rev": newest.Rev, "latest": false, "updatingat": newest.UpdatingAt},
+		bson.M{"$set": bson.M{"latest": true}, "$unset": bson.M{"updatingat": ""}},
+	)
+	if err != nil {
+		if err != mgo.ErrNotFound && !mgo.IsDup(err) {
+			glog.Error(err)
+		}
+
+		return false
+	}
+
+	glog.Warningf("Latest revision of entity %s is recovered", id)
+	return true
 }
 
-// GetEntityRevs - get entity revisions from database by id
-func (ur *EntityRepo) GetEntityRevs(id, companyID string) (*grpc_gateway_entity.EntityListResponse, error) {
-	var err error
+// RecoverLatestRevisions - recover latest revisions of entities which updates or purges didn't finish in time.
+// Returns number of recovered entities
+func (ur *EntityRepo) RecoverLatestRevisions() (int, error) {
+	c := ur.sess.C(ur.coll)
+
+	iter := c.Find(bson.M{
+		"latest":     false,
+		"updatingat": bson.M{"$lt": time.Now().Add(-entityUpdateTTL).Unix()},
+	}).Select(bson.M{"id": 1, "companyid": 1}).Iter()
+
+	recovered := 0
+	entity := grpc_gateway_entity.Entity{}
+	for iter.Next(&entity) {
+		if ur.recoverLatest(entity.Id, entity.CompanyId) {
+			recovered++
+		}
+	}
+
+	return recovered, iter.Close()
+}
+
+// GetEntityRevs - get page of entity revisions from database by id, nil page means all revisions
+// from the newest
+func (ur *EntityRepo) GetEntityRevs(id, companyID string, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	query := bson.M{"id": id, "companyid": companyID, "importid": bson.M{"$exists": false}}
+	if page != nil {
+		return ur.findEntities(query, page)
+	}
+
 	c := ur.sess.C(ur.coll)
 	entities := NewEntityListResponse()
-	err = c.Find(bson.M{"id": id, "companyid": companyID}).Sort("-rev").All(&entities.Data)
+
+	docs := []*storedEntity{}
+	err := c.Find(query).Sort("-rev").All(&docs)
+	if err != nil {
+		return entities, err
+	}
+
+	entities.Data, err = ur.openAll(docs)
+	entities.Total = int64(len(docs))
 	return entities, err
 }
 
-// GetEntities - get entities from database
-func (ur *EntityRepo) GetEntities(companyID string, params *grpc_gateway_entity.EntityListRequest) (*grpc_gateway_entity.EntityListResponse, error) {
+// GetCompanyRevisions - get all revisions of all entities of company, including deleted entities
+func (ur *EntityRepo) GetCompanyRevisions(companyID string) (*grpc_gateway_entity.EntityListResponse, error) {
 	c := ur.sess.C(ur.coll)
 	entities := NewEntityListResponse()
-	var err error
 
+	docs := []*storedEntity{}
+	err := c.Find(bson.M{"companyid": companyID, "importid": bson.M{"$exists": false}}).Sort("id", "rev").All(&docs)
+	if err != nil {
+		return entities, err
+	}
+
+	entities.Data, err = ur.openAll(docs)
+	return entities, err
+}
+
+// HasEntity - check if entity id is taken by entity of any company
+func (ur *EntityRepo) HasEntity(id string) (bool, error) {
+	c := ur.sess.C(ur.coll)
+	n, err := c.Find(bson.M{"id": id}).Count()
+	return n > 0, err
+}
+
+// GetEntities - get page of entities from database
+func (ur *EntityRepo) GetEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
 	mgoParams := bson.M{
-		"latest": true,
+		"latest":    true,
+		"isdeleted": bson.M{"$ne": true},
 	}
 
 	if params.Type != "" {
 		mgoParams["type"] = params.Type
 	}
 
-	if companyID == "" {
-		err = c.Find(mgoParams).All(&entities.Data)
-	} else {
+	if companyID != "" {
 		mgoParams["companyid"] = companyID
-		err = c.Find(mgoParams).All(&entities.Data)
 	}
-	return entities, err
+
+	return ur.findEntities(mgoParams, page)
+}
+
+// GetDeletedEntities - get page of deleted entities from database
+func (ur *EntityRepo) GetDeletedEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	mgoParams := bson.M{
+		"latest":    true,
+		"isdeleted": true,
+	}
+
+	if params.Type != "" {
+		mgoParams["type"] = params.Type
+	}
+
+	if companyID != "" {
+		mgoParams["companyid"] = companyID
+	}
+
+	return ur.findEntities(mgoParams, page)
+}
+
+// SearchEntities - get page of entities of company which match filter, company is required
+func (ur *EntityRepo) SearchEntities(companyID string, filter *grpc_gateway_entity.EntitySearchRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	if companyID == "" {
+		return NewEntityListResponse(), ErrMissedRequiredField
+	}
+
+	return ur.findEntities(newEntitySearch(filter, ur.cipher).query(companyID), page)
+}
+
+// BackfillSearchFields - store fields for search in entities which were stored without field.
+// Returns number of entities which need fields, they aren't changed on dry run
+func (ur *EntityRepo) BackfillSearchFields(field string, dryRun bool) (int, error) {
+	c := ur.sess.C(ur.coll)
+	query := bson.M{field: bson.M{"$exists": false}}
+
+	if dryRun {
+		return c.Find(query).Count()
+	}
+
+	changed := 0
+	doc := storedEntity{}
+	iter := c.Find(query).Iter()
+	for iter.Next(&doc) {
+		entity, err := ur.open(&doc)
+		if err == nil {
+			err = c.Update(bson.M{"id": entity.Id, "rev": entity.Rev}, bson.M{"$set": newEntitySearchKeys(entity, ur.cipher)})
+		}
+		if err != nil {
+			iter.Close()
+			return changed, err
+		}
+
+		changed++
+	}
+
+	return changed, iter.Close()
+}
+
+// Reseal - seal personal data of entities which are stored in plaintext or sealed by retired key.
+// Returns number of resealed revisions
+func (ur *EntityRepo) Reseal() (int, error) {
+	c := ur.sess.C(ur.coll)
+	if ur.cipher == nil {
+		return 0, nil
+	}
+
+	query := bson.M{"$or": []bson.M{
+		{"sealed": bson.M{"$exists": false}},
+		{"sealed.kid": bson.M{"$ne": ur.cipher.ActiveKeyID()}},
+	}}
+
+	changed := 0
+	doc := storedEntity{}
+	iter := c.Find(query).Iter()
+	for iter.Next(&doc) {
+		entity, err := ur.open(&doc)
+		if err != nil {
+			iter.Close()
+			return changed, err
+		}
+
+		resealed, err := ur.document(entity)
+		if err != nil {
+			iter.Close()
+			return changed, err
+		}
+
+		// revisions are immutable except flags of latest revision, so only sealed and search fields are replaced
+		err = c.Update(bson.M{"id": entity.Id, "rev": entity.Rev, "sealed": doc.Sealed}, bson.M{"$set": bson.M{
+			"sealed":             resealed.Sealed,
+			"birthday":           "",
+			"birthplace":         "",
+			"nationality":        "",
+			"residentialaddress": nil,
+			"bfinumber":          "",
+			"searchcountries":    resealed.Search.Countries,
+			"searchnationality":  resealed.Search.Nationality,
+			"bfinumberindex":     resealed.Search.BfiNumber,
+		}})
+		if err != nil && err != mgo.ErrNotFound {
+			iter.Close()
+			return changed, err
+		}
+		if err == nil {
+			changed++
+		}
+	}
+
+	return changed, iter.Close()
+}
+
+// CheckInvariants - find stored entities which have duplicated revisions or don't have exactly one latest revision.
+// Staged revisions of running imports aren't checked
+func (ur *EntityRepo) CheckInvariants() ([]*EntityViolation, error) {
+	c := ur.sess.C(ur.coll)
+	violations := []*EntityViolation{}
+
+	duplicates := []struct {
+		Key struct {
+			ID  string `bson:"id"`
+			Rev int64  `bson:"rev"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}{}
+	err := c.Pipe([]bson.M{
+		{"$match": bson.M{"importid": bson.M{"$exists": false}}},
+		{"$group": bson.M{"_id": bson.M{"id": "$id", "rev": "$rev"}, "count": bson.M{"$sum": 1}}},
+		{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+		{"$sort": bson.M{"_id.id": 1, "_id.rev": 1}},
+	}).AllowDiskUse().All(&duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, duplicate := range duplicates {
+		violations = append(violations, &EntityViolation{
+			EntityID: duplicate.Key.ID,
+			Rev:      duplicate.Key.Rev,
+			Problem:  EntityDuplicateRev,
+			Count:    duplicate.Count,
+		})
+	}
+
+	latest := []struct {
+		ID     string `bson:"_id"`
+		Latest int    `bson:"latest"`
+	}{}
+	err = c.Pipe([]bson.M{
+		{"$match": bson.M{"importid": bson.M{"$exists": false}}},
+		{"$group": bson.M{"_id": "$id", "latest": bson.M{"$sum": bson.M{"$cond": []interface{}{"$latest", 1, 0}}}}},
+		{"$match": bson.M{"latest": bson.M{"$ne": 1}}},
+		{"$sort": bson.M{"_id": 1}},
+	}).AllowDiskUse().All(&latest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range latest {
+		problem := EntityMultipleLatest
+		if entity.Latest == 0 {
+			problem = EntityNoLatest
+		}
+
+		violations = append(violations, &EntityViolation{
+			EntityID: entity.ID,
+			Problem:  problem,
+			Count:    entity.Latest,
+		})
+	}
+
+	return violations, nil
+}
+
+// DeleteEntity - store deleted revision of entity, deleted entity can be restored until it's purged
+func (ur *EntityRepo) DeleteEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error) {
+	entity, err := ur.GetLatestEntity(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRev := entity.Rev
+	return ur.addRevision(deletedRevision(entity, userID), baseRev, false)
+}
+
+// RestoreEntity - store revision of deleted entity which isn't deleted
+func (ur *EntityRepo) RestoreEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error) {
+	entity, err := ur.findLatest(id, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entity.IsDeleted {
+		return nil, mgo.ErrNotFound
+	}
+
+	baseRev := entity.Rev
+	return ur.addRevision(restoredRevision(entity, userID), baseRev, true)
+}
+
+// PurgeDeletedEntities - remove all revisions of entities which were deleted before deletedBefore.
+// Returns number of purged entities
+func (ur *EntityRepo) PurgeDeletedEntities(deletedBefore int64) (int, error) {
+	c := ur.sess.C(ur.coll)
+	purged := 0
+
+	for {
+		// purge takes latest flag like update, so entity can't be restored while its revisions are removed
+		deleted := grpc_gateway_entity.Entity{}
+		change := mgo.Change{
+			Update: bson.M{"$set": bson.M{"latest": false, "updatingat": time.Now().Unix()}},
+		}
+		_, err := c.Find(bson.M{"latest": true, "isdeleted": true, "deletedat": bson.M{"$lt": deletedBefore}}).Apply(change, &deleted)
+		if err == mgo.ErrNotFound {
+			return purged, nil
+		}
+		if err != nil {
+			return purged, err
+		}
+
+		if _, err := c.RemoveAll(bson.M{"id": deleted.Id, "companyid": deleted.CompanyId}); err != nil {
+			return purged, err
+		}
+
+		purged++
+	}
 }
 
-// CreateIndexes - create necessary indexes for fast executing
-func (ur *EntityRepo) CreateIndexes() {
+// RemoveEntities - remove all revisions of company entities, it's used for rollback of company bundle import.
+// Returns number of removed revisions
+func (ur *EntityRepo) RemoveEntities(companyID string, ids []string) (int, error) {
 	c := ur.sess.C(ur.coll)
-	c.EnsureIndex(mgo.Index{
-		Key:    []string{"id"},
-		Unique: true,
-	})
-	c.EnsureIndex(mgo.Index{
-		Key:    []string{"rev"},
-		Unique: true,
-	})
+
+	info, err := c.RemoveAll(bson.M{"companyid": companyID, "id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Removed, nil
 }
 
-// DeleteEntityByID - set entity as disabled from database by id
-func (ur *EntityRepo) DeleteEntityByID(id string) error {
+// StageEntity - store the first revision of imported entity. Staged revision isn't latest, so it isn't found
+// until import is published
+func (ur *EntityRepo) StageEntity(entity *grpc_gateway_entity.Entity, importID string) error {
 	c := ur.sess.C(ur.coll)
-	err := c.Update(bson.M{"id": id}, bson.M{"$set": bson.M{"isenabled": false}})
-	return err
+
+	entity.Latest = false
+	doc, err := ur.document(entity)
+	if err != nil {
+		return err
+	}
+
+	doc.ImportID = importID
+	return c.Insert(doc)
 }
 
-// UpdateEntity - update entity info by id
-func (ur *EntityRepo) UpdateEntity(entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error) {
+// PublishStagedEntities - make staged entities of import latest. Returns number of published entities
+func (ur *EntityRepo) PublishStagedEntities(companyID, importID string) (int, error) {
 	c := ur.sess.C(ur.coll)
 
-	oldEntity, err := ur.GetLatestEntity(entity.Id, entity.CompanyId)
+	info, err := c.UpdateAll(
+		bson.M{"companyid": companyID, "importid": importID},
+		bson.M{"$set": bson.M{"latest": true}, "$unset": bson.M{"importid": ""}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Updated, nil
+}
+
+// RemoveStagedEntities - remove staged entities of import which isn't published. Returns number of removed entities
+func (ur *EntityRepo) RemoveStagedEntities(companyID, importID string) (int, error) {
+	c := ur.sess.C(ur.coll)
+
+	info, err := c.RemoveAll(bson.M{"companyid": companyID, "importid": importID})
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Removed, nil
+}
+
+// UpdateEntity - store new revision of entity which is based on revision baseRev. Returns ErrEntityConflict
+// if baseRev isn't latest revision anymore
+func (ur *EntityRepo) UpdateEntity(entity *grpc_gateway_entity.Entity, baseRev int64) (*grpc_gateway_entity.Entity, error) {
+	entity.IsDeleted = false
+	entity.DeletedAt = 0
+	entity.DeletedBy = ""
+
+	return ur.addRevision(entity, baseRev, false)
+}
+
+// addRevision - store entity as revision next to baseRev, which is deleted if baseDeleted is set. Update takes
+// latest flag of base revision by conditional update before new revision is stored, so only one update of base
+// revision succeeds, other updates are conflicts and entity never has two latest revisions
+func (ur *EntityRepo) addRevision(entity *grpc_gateway_entity.Entity, baseRev int64, baseDeleted bool) (*grpc_gateway_entity.Entity, error) {
+	c := ur.sess.C(ur.coll)
+
+	latest, err := ur.findLatest(entity.Id, entity.CompanyId)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest.IsDeleted != baseDeleted {
+		return nil, mgo.ErrNotFound
+	}
+
+	if latest.Rev != baseRev {
+		return nil, ErrEntityConflict
+	}
+
+	entity.Rev = baseRev + 1
+	entity.Latest = true
+	doc, err := ur.document(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := interface{}(bson.M{"$ne": true})
+	if baseDeleted {
+		deleted = true
+	}
+
+	updatingAt := time.Now().Unix()
+	err = c.Update(
+		bson.M{"id": entity.Id, "companyid": entity.CompanyId, "rev": baseRev, "latest": true, "isdeleted": deleted},
+		bson.M{"$set": bson.M{"latest": false, "updatingat": updatingAt}},
+	)
+	if err == mgo.ErrNotFound {
+		return nil, ErrEntityConflict
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	entity.Rev = oldEntity.Rev + 1
-	if err := c.Insert(entity); err != nil {
+	taken := bson.M{"id": entity.Id, "companyid": entity.CompanyId, "rev": baseRev, "latest": false, "updatingat": updatingAt}
+	if err := c.Insert(doc); err != nil {
+		// base revision becomes latest again
+		if err := c.Update(taken, bson.M{"$set": bson.M{"latest": true}, "$unset": bson.M{"updatingat": ""}}); err != nil && err != mgo.ErrNotFound {
+			glog.Error(err)
+		}
+
+		if mgo.IsDup(err) {
+			return nil, ErrEntityConflict
+		}
+
 		return nil, err
 	}
 
-	return entity, c.Update(bson.M{"id": oldEntity.Id, "createdat": oldEntity.CreatedAt}, bson.M{"$set": bson.M{"latest": false}})
+	// update was stopped longer than entityUpdateTTL, base revision was recovered or purged meanwhile
+	if err := c.Update(taken, bson.M{"$unset": bson.M{"updatingat": ""}}); err != nil {
+		if err != mgo.ErrNotFound {
+			glog.Error(err)
+			return entity, nil
+		}
+
+		if err := c.Remove(bson.M{"id": entity.Id, "companyid": entity.CompanyId, "rev": entity.Rev}); err != nil {
+			glog.Error(err)
+		}
+
+		return nil, ErrEntityConflict
+	}
+
+	return entity, nil
+}
+
+// deletedRevision - revision which marks entity as deleted
+func deletedRevision(entity *grpc_gateway_entity.Entity, userID string) *grpc_gateway_entity.Entity {
+	now := time.Now().Unix()
+
+	entity.IsDeleted = true
+	entity.DeletedAt = now
+	entity.DeletedBy = userID
+	entity.CreatedAt = now
+	entity.CreatedBy = userID
+	return entity
+}
+
+// restoredRevision - revision which returns deleted entity back
+func restoredRevision(entity *grpc_gateway_entity.Entity, userID string) *grpc_gateway_entity.Entity {
+	entity.IsDeleted = false
+	entity.DeletedAt = 0
+	entity.DeletedBy = ""
+	entity.CreatedAt = time.Now().Unix()
+	entity.CreatedBy = userID
+	return entity
 }
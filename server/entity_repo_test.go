@@ -0,0 +1,136 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+type EntityRepoTestSuite struct {
+	sess *mgo.Database
+	repo *server.EntityRepo
+}
+
+var _ = Suite(&EntityRepoTestSuite{})
+
+func (et *EntityRepoTestSuite) SetUpTest(c *C) {
+	et.sess = testMongoDatabase(c)
+	et.repo = server.NewEntityRepo(et.sess)
+	migrateTestDatabase(c, et.sess)
+}
+
+func (et *EntityRepoTestSuite) TearDownTest(c *C) {
+	if et.sess != nil {
+		et.sess.DropDatabase()
+		et.sess.Session.Close()
+		et.sess = nil
+	}
+}
+
+func (et *EntityRepoTestSuite) TestConcurrentUpdates(c *C) {
+	checkConcurrentEntityUpdates(c, et.repo)
+
+	violations, err := et.repo.CheckInvariants()
+	c.Assert(err, IsNil)
+	c.Assert(violations, HasLen, 0)
+}
+
+func (et *EntityRepoTestSuite) TestRecoverLatest(c *C) {
+	_, err := et.repo.CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", Latest: true})
+	c.Assert(err, IsNil)
+
+	// update took latest flag and didn't store new revision yet
+	err = et.sess.C("entities").Update(bson.M{"id": "e1", "rev": 0}, bson.M{"$set": bson.M{"latest": false, "updatingat": time.Now().Unix()}})
+	c.Assert(err, IsNil)
+
+	latest, err := et.repo.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Rev, Equals, int64(0))
+
+	_, err = et.repo.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, 0)
+	c.Assert(err, Equals, server.ErrEntityConflict)
+
+	recovered, err := et.repo.RecoverLatestRevisions()
+	c.Assert(err, IsNil)
+	c.Assert(recovered, Equals, 0)
+
+	// update was stopped, revision is recovered when update is expired
+	err = et.sess.C("entities").Update(bson.M{"id": "e1", "rev": 0}, bson.M{"$set": bson.M{"updatingat": time.Now().Add(-time.Hour).Unix()}})
+	c.Assert(err, IsNil)
+
+	recovered, err = et.repo.RecoverLatestRevisions()
+	c.Assert(err, IsNil)
+	c.Assert(recovered, Equals, 1)
+
+	list, err := et.repo.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+	c.Assert(list.Data[0].Rev, Equals, int64(0))
+
+	updated, err := et.repo.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(updated.Rev, Equals, int64(1))
+
+	// update stored new revision, but was stopped before it released base revision
+	err = et.sess.C("entities").Update(bson.M{"id": "e1", "rev": 0}, bson.M{"$set": bson.M{"updatingat": time.Now().Add(-time.Hour).Unix()}})
+	c.Assert(err, IsNil)
+
+	recovered, err = et.repo.RecoverLatestRevisions()
+	c.Assert(err, IsNil)
+	c.Assert(recovered, Equals, 0)
+
+	n, err := et.sess.C("entities").Find(bson.M{"id": "e1", "updatingat": bson.M{"$exists": true}}).Count()
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 0)
+
+	// purge which took latest flag didn't remove revisions
+	err = et.sess.C("entities").Update(bson.M{"id": "e1", "rev": 1}, bson.M{"$set": bson.M{"latest": false, "updatingat": time.Now().Unix()}})
+	c.Assert(err, IsNil)
+
+	list, err = et.repo.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 0)
+
+	_, err = et.repo.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, 1)
+	c.Assert(err, Equals, server.ErrEntityConflict)
+
+	// revision is recovered on read when purge is expired
+	err = et.sess.C("entities").Update(bson.M{"id": "e1", "rev": 1}, bson.M{"$set": bson.M{"updatingat": time.Now().Add(-time.Hour).Unix()}})
+	c.Assert(err, IsNil)
+
+	latest, err = et.repo.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Rev, Equals, int64(1))
+	c.Assert(latest.Latest, Equals, true)
+
+	updated, err = et.repo.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, 1)
+	c.Assert(err, IsNil)
+	c.Assert(updated.Rev, Equals, int64(2))
+
+	violations, err := et.repo.CheckInvariants()
+	c.Assert(err, IsNil)
+	c.Assert(violations, HasLen, 0)
+}
+
+func (et *EntityRepoTestSuite) TestTrash(c *C) {
+	checkEntityTrash(c, et.repo)
+}
+
+func (et *EntityRepoTestSuite) TestPages(c *C) {
+	checkEntityPages(c, et.repo)
+}
+
+func (et *EntityRepoTestSuite) TestSearch(c *C) {
+	checkEntitySearch(c, et.repo)
+}
+
+func (et *EntityRepoTestSuite) TestRemove(c *C) {
+	checkRemoveEntities(c, et.repo)
+}
+
+func (et *EntityRepoTestSuite) TestStaged(c *C) {
+	checkStagedEntities(c, et.repo)
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/mgo.v2/bson"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// foldedLetters - letters which don't decompose into base letter and diacritic
+var foldedLetters = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'œ': "oe",
+	'ø': "o",
+	'ł': "l",
+	'đ': "d",
+	'ð': "d",
+	'þ': "th",
+	'ı': "i",
+}
+
+// normalizeSearchText - lower case text without diacritics and with single spaces, it's used for matching
+// which ignores case and diacritics
+func normalizeSearchText(text string) string {
+	normalized := make([]rune, 0, len(text))
+	space := false
+
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsSpace(r):
+			space = len(normalized) > 0
+			continue
+		}
+
+		if space {
+			normalized = append(normalized, ' ')
+			space = false
+		}
+
+		if folded, ok := foldedLetters[r]; ok {
+			normalized = append(normalized, []rune(folded)...)
+		} else {
+			normalized = append(normalized, r)
+		}
+	}
+
+	return string(normalized)
+}
+
+// entitySearchName - normalized names of entity which are matched by search query
+func entitySearchName(entity *grpc_gateway_entity.Entity) string {
+	fullName := strings.Join([]string{entity.GivenName, entity.MiddleName, entity.FamilyName}, " ")
+
+	names := []string{}
+	for _, name := range []string{entity.CommonName, entity.RegisteredName, entity.TradeName, fullName} {
+		if name = normalizeSearchText(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	// names are separated by line break, so query can't match end of one name and start of another
+	return strings.Join(names, "\n")
+}
+
+// entitySearchCountries - normalized countries of all addresses of entity
+func entitySearchCountries(entity *grpc_gateway_entity.Entity) []string {
+	countries := []string{}
+	for _, address := range []*grpc_gateway_common.Address{entity.ResidentialAddress, entity.VisitingAddress, entity.RegisteredAddress} {
+		if address == nil {
+			continue
+		}
+
+		if country := normalizeSearchText(address.Country); country != "" {
+			countries = append(countries, country)
+		}
+	}
+
+	return countries
+}
+
+// entitySearchKeys - fields which are stored with entity for search. Countries, nationality and bfi number
+// are personal data, so they are stored as blind indexes
+type entitySearchKeys struct {
+	Name        string   `bson:"searchname"`
+	Countries   []string `bson:"searchcountries"`
+	Nationality string   `bson:"searchnationality"`
+	LegalForm   string   `bson:"searchlegalform"`
+	BfiNumber   string   `bson:"bfinumberindex"`
+}
+
+// newEntitySearchKeys - search fields of entity, blind indexes are computed by cipher
+func newEntitySearchKeys(entity *grpc_gateway_entity.Entity, fc *FieldCipher) *entitySearchKeys {
+	keys := &entitySearchKeys{
+		Name:        entitySearchName(entity),
+		Countries:   []string{},
+		Nationality: fc.BlindIndex("nationality", normalizeSearchText(entity.Nationality)),
+		LegalForm:   normalizeSearchText(entity.LegalForm),
+		BfiNumber:   fc.BlindIndex("bfinumber", strings.TrimSpace(entity.BfiNumber)),
+	}
+
+	for _, country := range entitySearchCountries(entity) {
+		keys.Countries = append(keys.Countries, fc.BlindIndex("country", country))
+	}
+
+	return keys
+}
+
+// entitySearch - normalized filter of entities
+type entitySearch struct {
+	name        string
+	typ         string
+	kvk         string
+	rsin        string
+	bfiNumber   string
+	nationality string
+	legalForm   string
+	country     string
+	createdFrom int64
+	createdTo   int64
+
+	cipher *FieldCipher
+}
+
+// newEntitySearch - normalize filter of request, encrypted fields are looked up by blind indexes of cipher
+func newEntitySearch(in *grpc_gateway_entity.EntitySearchRequest, fc *FieldCipher) *entitySearch {
+	return &entitySearch{
+		name:        normalizeSearchText(in.Query),
+		typ:         in.Type,
+		kvk:         strings.TrimSpace(in.Kvk),
+		rsin:        strings.TrimSpace(in.Rsin),
+		bfiNumber:   fc.BlindIndex("bfinumber", strings.TrimSpace(in.BfiNumber)),
+		nationality: fc.BlindIndex("nationality", normalizeSearchText(in.Nationality)),
+		legalForm:   normalizeSearchText(in.LegalForm),
+		country:     fc.BlindIndex("country", normalizeSearchText(in.Country)),
+		createdFrom: in.CreatedFrom,
+		createdTo:   in.CreatedTo,
+		cipher:      fc,
+	}
+}
+
+// query - mongo query of latest revisions of company entities which match filter
+func (es *entitySearch) query(companyID string) bson.M {
+	query := bson.M{
+		"companyid": companyID,
+		"latest":    true,
+		"isdeleted": bson.M{"$ne": true},
+	}
+
+	if es.name != "" {
+		query["searchname"] = bson.RegEx{Pattern: regexp.QuoteMeta(es.name)}
+	}
+
+	for field, value := range map[string]string{
+		"type":              es.typ,
+		"kvk":               es.kvk,
+		"rsin":              es.rsin,
+		"bfinumberindex":    es.bfiNumber,
+		"searchnationality": es.nationality,
+		"searchlegalform":   es.legalForm,
+		"searchcountries":   es.country,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+
+	createdAt := bson.M{}
+	if es.createdFrom != 0 {
+		createdAt["$gte"] = es.createdFrom
+	}
+	if es.createdTo != 0 {
+		createdAt["$lte"] = es.createdTo
+	}
+	if len(createdAt) > 0 {
+		query["createdat"] = createdAt
+	}
+
+	return query
+}
+
+// matches - check if entity matches filter, it's used by memory storage
+func (es *entitySearch) matches(entity *grpc_gateway_entity.Entity) bool {
+	keys := newEntitySearchKeys(entity, es.cipher)
+	if es.name != "" && !strings.Contains(keys.Name, es.name) {
+		return false
+	}
+
+	for _, field := range [][2]string{
+		{es.typ, entity.Type},
+		{es.kvk, entity.Kvk},
+		{es.rsin, entity.Rsin},
+		{es.bfiNumber, keys.BfiNumber},
+		{es.nationality, keys.Nationality},
+		{es.legalForm, keys.LegalForm},
+	} {
+		if field[0] != "" && field[0] != field[1] {
+			return false
+		}
+	}
+
+	if es.country != "" {
+		found := false
+		for _, country := range keys.Countries {
+			found = found || country == es.country
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return (es.createdFrom == 0 || entity.CreatedAt >= es.createdFrom) && (es.createdTo == 0 || entity.CreatedAt <= es.createdTo)
+}
@@ -9,8 +9,10 @@ import (
 	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
 	//"github.com/golang/glog"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	. "gopkg.in/check.v1"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -25,6 +27,7 @@ func (s *EntityTestSuite) SetUpSuite(c *C) {
 	flag.Parse()
 
 	cfg := &server.Config{
+		Storage:              testStorage(),
 		EmailConfirmationTTL: time.Second * 5,
 		SMSConfirmationTTL:   time.Second * 2,
 	}
@@ -255,7 +258,7 @@ func (m *EntityTestSuite) TestUpdateByAdmin(c *C) {
 	resp, err := client.Do(req)
 	defer resp.Body.Close()
 
-	message := server.NewCommonResponse()
+	message := server.NewEntityResponse()
 	err = jsonpb.Unmarshal(resp.Body, message)
 	c.Assert(err, IsNil)
 
@@ -328,7 +331,7 @@ func (m *EntityTestSuite) TestGetAllRevsByAdmin(c *C) {
 	resp, err := client.Do(req)
 	defer resp.Body.Close()
 
-	message := server.NewCommonResponse()
+	message := server.NewEntityResponse()
 	err = jsonpb.Unmarshal(resp.Body, message)
 	c.Assert(err, IsNil)
 
@@ -364,7 +367,7 @@ func (m *EntityTestSuite) TestGetAllRevsByNonAdmin(c *C) {
 	resp, err := client.Do(req)
 	defer resp.Body.Close()
 
-	message := server.NewCommonResponse()
+	message := server.NewEntityResponse()
 	err = jsonpb.Unmarshal(resp.Body, message)
 	c.Assert(err, IsNil)
 
@@ -399,6 +402,23 @@ func (m *EntityTestSuite) TestGetAllRevsByNonAdmin(c *C) {
 
 	c.Assert(orderRevs, Equals, 2)
 
+	// revisions are listed page by page from the newest
+	list := server.NewEntityListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/entity_revs/%v?limit=1", entity.Id), createdUserToken1, list)
+	c.Assert(err, IsNil)
+	c.Assert(list.Meta.Ok, Equals, true)
+	c.Assert(list.Data, HasLen, 1)
+	c.Assert(list.Data[0].Rev, Equals, int64(1))
+	c.Assert(list.Total, Equals, int64(2))
+
+	cursor := list.NextCursor
+	list = server.NewEntityListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/entity_revs/%v?limit=1&cursor=%v", entity.Id, cursor), createdUserToken1, list)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+	c.Assert(list.Data[0].Rev, Equals, int64(0))
+	c.Assert(list.NextCursor, Equals, "")
+
 	// get updated entity by non-admin user and non-company user
 	email = fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
 	_, err = createTestUser(email, token, "12341234", false)
@@ -414,9 +434,265 @@ func (m *EntityTestSuite) TestGetAllRevsByNonAdmin(c *C) {
 	defer resp.Body.Close()
 	c.Assert(err, IsNil)
 
-	list := server.NewEntityListResponse()
+	list = server.NewEntityListResponse()
 	err = jsonpb.Unmarshal(resp.Body, list)
 	c.Assert(err, IsNil)
 	c.Assert(list.Meta.StatusCode, Equals, HttpStatusOK)
 	c.Assert(len(list.Data), Equals, 0)
 }
+
+// update entity from stale revision
+func (m *EntityTestSuite) TestUpdateConflict(c *C) {
+	token := getTestDefaultAuthToken()
+
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, token, companyId, false)
+	c.Assert(err, IsNil)
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	entity, err := createTestEntity(companyId, createdUserToken)
+	c.Assert(err, IsNil)
+
+	update := func(rev int64) *grpc_gateway_entity.EntityResponse {
+		entity.Rev = rev
+		entityTxt, _ := json.Marshal(entity)
+		req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:8080/v1/entity/%v", entity.Id), bytes.NewReader(entityTxt))
+		c.Assert(err, IsNil)
+
+		req.Header.Add("Authorization", createdUserToken)
+
+		resp, err := server.GetHTTPClient().Do(req)
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+
+		message := server.NewEntityResponse()
+		c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+		return message
+	}
+
+	message := update(0)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.Rev, Equals, int64(1))
+
+	// another editor changed revision 0 too
+	message = update(0)
+	c.Assert(message.Meta.StatusCode, Equals, HttpStatusConflict)
+	c.Assert(message.Meta.Ok, Equals, false)
+	c.Assert(message.Meta.Error, Equals, server.ErrEntityConflict.Error())
+
+	message = update(1)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.Rev, Equals, int64(2))
+}
+
+// delete entity, find it in trash and restore it
+func (m *EntityTestSuite) TestDeleteAndRestore(c *C) {
+	token := getTestDefaultAuthToken()
+
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, token, companyId, false)
+	c.Assert(err, IsNil)
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	entity, err := createTestEntity(companyId, createdUserToken)
+	c.Assert(err, IsNil)
+
+	send := func(method, url, token string, message proto.Message) {
+		req, err := http.NewRequest(method, "http://127.0.0.1:8080"+url, strings.NewReader("{}"))
+		c.Assert(err, IsNil)
+
+		req.Header.Add("Authorization", token)
+
+		resp, err := server.GetHTTPClient().Do(req)
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+
+		c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+	}
+
+	message := server.NewEntityResponse()
+	send("DELETE", "/v1/entity/"+entity.Id, createdUserToken, message)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.IsDeleted, Equals, true)
+	c.Assert(message.Data.Rev, Equals, int64(1))
+
+	message = server.NewEntityResponse()
+	send("GET", "/v1/entity/"+entity.Id, createdUserToken, message)
+	c.Assert(message.Meta.StatusCode, Equals, HttpStatusNotFound)
+
+	list := server.NewEntityListResponse()
+	send("GET", "/v1/entity", createdUserToken, list)
+	c.Assert(list.Meta.Ok, Equals, true)
+	c.Assert(list.Data, HasLen, 0)
+
+	list = server.NewEntityListResponse()
+	send("GET", "/v1/entity_trash", createdUserToken, list)
+	c.Assert(list.Meta.Ok, Equals, true)
+	c.Assert(list.Data, HasLen, 1)
+	c.Assert(list.Data[0].Id, Equals, entity.Id)
+
+	message = server.NewEntityResponse()
+	send("POST", "/v1/entity/"+entity.Id+"/restore", createdUserToken, message)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.IsDeleted, Equals, false)
+	c.Assert(message.Data.Rev, Equals, int64(2))
+
+	message = server.NewEntityResponse()
+	send("GET", "/v1/entity/"+entity.Id, createdUserToken, message)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.CommonName, Equals, entity.CommonName)
+
+	// only platform admins purge deleted entities, recently deleted entities are kept
+	purge := &grpc_gateway_entity.PurgeEntitiesResponse{}
+	send("DELETE", "/v1/entity_trash", createdUserToken, purge)
+	c.Assert(purge.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	purge = &grpc_gateway_entity.PurgeEntitiesResponse{}
+	send("DELETE", "/v1/entity_trash", token, purge)
+	c.Assert(purge.Meta.Ok, Equals, true)
+	c.Assert(purge.Purged, Equals, int64(0))
+}
+
+// entity can't be created as deleted, deletion is only stored by DELETE request
+func (m *EntityTestSuite) TestCreateDeleted(c *C) {
+	token := getTestDefaultAuthToken()
+
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, token, companyId, false)
+	c.Assert(err, IsNil)
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	entity := grpc_gateway_entity.Entity{
+		CommonName: fmt.Sprintf("entityName_%v", time.Now().UnixNano()),
+		IsDeleted:  true,
+		DeletedAt:  time.Now().Unix(),
+		DeletedBy:  "someone",
+	}
+
+	entityTxt, _ := json.Marshal(entity)
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8080/v1/entity", bytes.NewReader(entityTxt))
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", createdUserToken)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	message := server.NewEntityResponse()
+	c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.IsDeleted, Equals, false)
+	c.Assert(message.Data.DeletedAt, Equals, int64(0))
+	c.Assert(message.Data.DeletedBy, Equals, "")
+
+	req, err = http.NewRequest("GET", "http://127.0.0.1:8080/v1/entity/"+message.Data.Id, nil)
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", createdUserToken)
+
+	resp, err = server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	message = server.NewEntityResponse()
+	c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+	c.Assert(message.Meta.Ok, Equals, true)
+	c.Assert(message.Data.IsDeleted, Equals, false)
+}
+
+// read entities page by page
+func (m *EntityTestSuite) TestListPages(c *C) {
+	token := getTestDefaultAuthToken()
+
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, token, companyId, false)
+	c.Assert(err, IsNil)
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	for i := 0; i < 3; i++ {
+		_, err := createTestEntity(companyId, createdUserToken)
+		c.Assert(err, IsNil)
+	}
+
+	list := func(query string) *grpc_gateway_entity.EntityListResponse {
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8080/v1/entity?"+query, nil)
+		c.Assert(err, IsNil)
+
+		req.Header.Add("Authorization", createdUserToken)
+
+		resp, err := server.GetHTTPClient().Do(req)
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+
+		message := server.NewEntityListResponse()
+		c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+		return message
+	}
+
+	first := list("limit=2&sort=-created_at")
+	c.Assert(first.Meta.Ok, Equals, true)
+	c.Assert(first.Data, HasLen, 2)
+	c.Assert(first.Total, Equals, int64(3))
+	c.Assert(first.NextCursor, Not(Equals), "")
+
+	second := list("limit=2&sort=-created_at&cursor=" + first.NextCursor)
+	c.Assert(second.Meta.Ok, Equals, true)
+	c.Assert(second.Data, HasLen, 1)
+	c.Assert(second.NextCursor, Equals, "")
+	c.Assert(second.Data[0].Id, Not(Equals), first.Data[0].Id)
+	c.Assert(second.Data[0].Id, Not(Equals), first.Data[1].Id)
+
+	invalid := list("cursor=invalid")
+	c.Assert(invalid.Meta.Ok, Equals, false)
+	c.Assert(invalid.Meta.StatusCode, Equals, int32(http.StatusBadRequest))
+	c.Assert(invalid.Meta.Error, Equals, server.ErrInvalidCursor.Error())
+}
+
+func (m *EntityTestSuite) TestSearch(c *C) {
+	token := getTestDefaultAuthToken()
+
+	tokens := []string{}
+	for i := 0; i < 2; i++ {
+		companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+		email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+		_, err := createTestUser(email, token, companyId, false)
+		c.Assert(err, IsNil)
+		tokens = append(tokens, getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email)))
+	}
+
+	companyId := fmt.Sprintf("company_%v", time.Now().UnixNano())
+	entity, err := createTestEntity(companyId, tokens[0])
+	c.Assert(err, IsNil)
+
+	search := func(userToken, query string) *grpc_gateway_entity.EntityListResponse {
+		req, err := http.NewRequest("GET", "http://127.0.0.1:8080/v1/entity_search?"+query, nil)
+		c.Assert(err, IsNil)
+
+		req.Header.Add("Authorization", userToken)
+
+		resp, err := server.GetHTTPClient().Do(req)
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+
+		message := server.NewEntityListResponse()
+		c.Assert(jsonpb.Unmarshal(resp.Body, message), IsNil)
+		return message
+	}
+
+	found := search(tokens[0], "query="+strings.ToUpper(entity.CommonName))
+	c.Assert(found.Meta.Ok, Equals, true)
+	c.Assert(found.Data, HasLen, 1)
+	c.Assert(found.Data[0].Id, Equals, entity.Id)
+
+	// entities of another company aren't found
+	other := search(tokens[1], "query="+entity.CommonName)
+	c.Assert(other.Meta.Ok, Equals, true)
+	c.Assert(other.Data, HasLen, 0)
+
+	invalid := search(tokens[0], "sort=kvk")
+	c.Assert(invalid.Meta.Ok, Equals, false)
+	c.Assert(invalid.Meta.StatusCode, Equals, int32(http.StatusBadRequest))
+}
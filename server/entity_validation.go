@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"regexp"
+	"time"
+)
+
+// Types of entities
+const (
+	EntityTypePerson  = "person"
+	EntityTypeCompany = "company"
+)
+
+// EntityDateLayout - format of dates of entities
+const EntityDateLayout = "2006-01-02"
+
+// ErrEntityFieldRequired - error when required field of entity is empty
+var ErrEntityFieldRequired = errors.New("field is required")
+
+// ErrEntityUnknownType - error when type of entity isn't person or company
+var ErrEntityUnknownType = errors.New("type should be person or company")
+
+// ErrEntityInvalidDate - error when date field of entity isn't in format YYYY-MM-DD
+var ErrEntityInvalidDate = errors.New("date should be in format YYYY-MM-DD")
+
+// ErrEntityInvalidKvk - error when kvk number isn't 8 digits
+var ErrEntityInvalidKvk = errors.New("kvk number should have 8 digits")
+
+// ErrEntityInvalidRsin - error when rsin isn't 9 digits
+var ErrEntityInvalidRsin = errors.New("rsin should have 9 digits")
+
+// ErrEntityInvalidFlag - error when flag isn't yes or no
+var ErrEntityInvalidFlag = errors.New("value should be yes or no")
+
+var (
+	kvkPattern  = regexp.MustCompile(`^[0-9]{8}$`)
+	rsinPattern = regexp.MustCompile(`^[0-9]{9}$`)
+)
+
+// EntityFieldError - rule which is violated by field of entity, field is proto name
+type EntityFieldError struct {
+	Field string
+	Err   error
+}
+
+// ValidateEntity - check rules of entity. Person should have family name, company should have common or
+// registered name, dates, kvk and rsin should have their formats and bfi entity should have bfi number
+func ValidateEntity(entity *grpc_gateway_entity.Entity) []*EntityFieldError {
+	errs := []*EntityFieldError{}
+	check := func(field string, valid bool, err error) {
+		if !valid {
+			errs = append(errs, &EntityFieldError{Field: field, Err: err})
+		}
+	}
+
+	switch entity.Type {
+	case "":
+		check("type", false, ErrEntityFieldRequired)
+	case EntityTypePerson:
+		check("family_name", entity.FamilyName != "", ErrEntityFieldRequired)
+	case EntityTypeCompany:
+		check("common_name", entity.CommonName != "" || entity.RegisteredName != "", ErrEntityFieldRequired)
+	default:
+		check("type", false, ErrEntityUnknownType)
+	}
+
+	for _, date := range [][2]string{
+		{"birthday", entity.Birthday},
+		{"date_of_registration", entity.DateOfRegistration},
+		{"date_of_establishment", entity.DateOfEstablishment},
+	} {
+		_, err := time.Parse(EntityDateLayout, date[1])
+		check(date[0], date[1] == "" || err == nil, ErrEntityInvalidDate)
+	}
+
+	check("kvk", entity.Kvk == "" || kvkPattern.MatchString(entity.Kvk), ErrEntityInvalidKvk)
+	check("rsin", entity.Rsin == "" || rsinPattern.MatchString(entity.Rsin), ErrEntityInvalidRsin)
+	check("bfi_number", !entity.IsBfi || entity.BfiNumber != "", ErrEntityFieldRequired)
+
+	return errs
+}
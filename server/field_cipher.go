@@ -0,0 +1,245 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+)
+
+// ErrUnknownEncryptionKey - error when fields are sealed by master key which isn't configured
+var ErrUnknownEncryptionKey = errors.New("fields are sealed with unknown encryption key")
+
+// ErrEncryptionKeysMissing - error when sealed fields are read by server without encryption keys
+var ErrEncryptionKeysMissing = errors.New("encryption keys aren't configured, sealed fields can't be read")
+
+// ErrSealedFieldsCorrupted - error when sealed fields can't be decrypted with their key
+var ErrSealedFieldsCorrupted = errors.New("sealed fields are corrupted or belong to another document")
+
+// encryptionKeySize - size of master keys and data keys, AES-256 is used
+const encryptionKeySize = 32
+
+// EncryptionKey - master key which wraps data keys of sealed fields
+type EncryptionKey struct {
+	ID     string
+	Secret []byte
+}
+
+// SealedFields - encrypted fields of stored document. Fields are encrypted by own data key of document,
+// data key is stored wrapped by master key KeyID
+type SealedFields struct {
+	KeyID   string `bson:"kid"`
+	DataKey []byte `bson:"datakey"`
+	Data    []byte `bson:"data"`
+}
+
+// FieldCipher - envelope encryption of personal data which mongo repositories store. Nil cipher means that
+// encryption isn't configured and fields are stored in plaintext
+type FieldCipher struct {
+	active   *EncryptionKey
+	keys     map[string]*EncryptionKey
+	blindKey []byte
+}
+
+var fieldCipherInstance *FieldCipher
+
+// GetFieldCipher - return cipher which is used by repositories
+func GetFieldCipher() *FieldCipher {
+	return fieldCipherInstance
+}
+
+// SetFieldCipher - set cipher which is used by repositories created after this call
+func SetFieldCipher(fc *FieldCipher) {
+	fieldCipherInstance = fc
+}
+
+// ParseEncryptionKeys - parse keys from string in format "kid:base64-secret,...", secret is 32 bytes
+func ParseEncryptionKeys(spec string) ([]*EncryptionKey, error) {
+	keys := []*EncryptionKey{}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("wrong format of encryption key %q", parts[0])
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("wrong secret of encryption key %q: %v", parts[0], err)
+		}
+
+		keys = append(keys, &EncryptionKey{ID: parts[0], Secret: secret})
+	}
+
+	return keys, nil
+}
+
+// NewFieldCipher - create cipher which seals fields with active key and opens fields sealed with any of keys.
+// If activeKeyID is empty the first key is active. Without keys nil cipher is returned and fields aren't encrypted.
+// Blind indexes are computed with blindIndexKey, it doesn't change with rotation of master keys
+func NewFieldCipher(keys []*EncryptionKey, activeKeyID string, blindIndexKey []byte) (*FieldCipher, error) {
+	if len(keys) == 0 {
+		glog.Warning("encryption keys aren't configured, personal data is stored in plaintext")
+		return nil, nil
+	}
+
+	if len(blindIndexKey) < encryptionKeySize {
+		return nil, fmt.Errorf("blind index key should have at least %d bytes", encryptionKeySize)
+	}
+
+	fc := &FieldCipher{
+		keys:     make(map[string]*EncryptionKey),
+		blindKey: blindIndexKey,
+	}
+
+	for _, key := range keys {
+		if len(key.Secret) != encryptionKeySize {
+			return nil, fmt.Errorf("encryption key %q should have %d bytes", key.ID, encryptionKeySize)
+		}
+
+		if _, ok := fc.keys[key.ID]; ok {
+			return nil, fmt.Errorf("encryption key %q is duplicated", key.ID)
+		}
+
+		fc.keys[key.ID] = key
+	}
+
+	if activeKeyID == "" {
+		activeKeyID = keys[0].ID
+	}
+
+	active, ok := fc.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active encryption key %q isn't configured", activeKeyID)
+	}
+
+	fc.active = active
+	return fc, nil
+}
+
+// ActiveKeyID - id of master key which seals new documents
+func (fc *FieldCipher) ActiveKeyID() string {
+	return fc.active.ID
+}
+
+// Seal - encrypt fields, which is struct or map, with new data key. aad binds sealed fields to document,
+// so they can't be copied to another one
+func (fc *FieldCipher) Seal(fields interface{}, aad string) (*SealedFields, error) {
+	plaintext, err := bson.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := gcmSeal(fc.active.Secret, dataKey, "datakey:"+fc.active.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := gcmSeal(dataKey, plaintext, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedFields{KeyID: fc.active.ID, DataKey: wrapped, Data: data}, nil
+}
+
+// Open - decrypt sealed fields of document with aad into fields, which is pointer to struct or map
+func (fc *FieldCipher) Open(sealed *SealedFields, aad string, fields interface{}) error {
+	if fc == nil {
+		return ErrEncryptionKeysMissing
+	}
+
+	key, ok := fc.keys[sealed.KeyID]
+	if !ok {
+		return ErrUnknownEncryptionKey
+	}
+
+	dataKey, err := gcmOpen(key.Secret, sealed.DataKey, "datakey:"+key.ID)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := gcmOpen(dataKey, sealed.Data, aad)
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(plaintext, fields)
+}
+
+// NeedsReseal - check if document should be sealed again, because it's in plaintext or sealed by retired key
+func (fc *FieldCipher) NeedsReseal(sealed *SealedFields) bool {
+	return fc != nil && (sealed == nil || sealed.KeyID != fc.active.ID)
+}
+
+// BlindIndex - keyed hash of value, which allows equality lookups of encrypted field without decryption.
+// Without encryption value is stored as is
+func (fc *FieldCipher) BlindIndex(field, value string) string {
+	if fc == nil || value == "" {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, fc.blindKey)
+	mac.Write([]byte(field + "\x00" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// gcmSeal - encrypt plaintext with AES-GCM, random nonce is prepended to ciphertext
+func gcmSeal(key, plaintext []byte, aad string) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, []byte(aad)), nil
+}
+
+// gcmOpen - decrypt ciphertext of gcmSeal
+func gcmOpen(key, ciphertext []byte, aad string) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrSealedFieldsCorrupted
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], []byte(aad))
+	if err != nil {
+		return nil, ErrSealedFieldsCorrupted
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
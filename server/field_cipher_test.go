@@ -0,0 +1,265 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type FieldCipherTestSuite struct {
+	sess *mgo.Database
+}
+
+var _ = Suite(&FieldCipherTestSuite{})
+
+func (ft *FieldCipherTestSuite) TearDownTest(c *C) {
+	server.SetFieldCipher(nil)
+
+	if ft.sess != nil {
+		ft.sess.DropDatabase()
+		ft.sess.Session.Close()
+		ft.sess = nil
+	}
+}
+
+// testEncryptionKey - master key of 32 bytes filled with b
+func testEncryptionKey(id string, b byte) *server.EncryptionKey {
+	return &server.EncryptionKey{ID: id, Secret: bytes.Repeat([]byte{b}, 32)}
+}
+
+// testFieldCipher - cipher with keys, the first key is active
+func testFieldCipher(c *C, keys ...*server.EncryptionKey) *server.FieldCipher {
+	fc, err := server.NewFieldCipher(keys, "", bytes.Repeat([]byte{'b'}, 32))
+	c.Assert(err, IsNil)
+	return fc
+}
+
+func (ft *FieldCipherTestSuite) TestParseEncryptionKeys(c *C) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'k'}, 32))
+
+	keys, err := server.ParseEncryptionKeys("new:" + secret + ", old:" + secret)
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 2)
+	c.Assert(keys[0].ID, Equals, "new")
+	c.Assert(keys[1].ID, Equals, "old")
+	c.Assert(keys[1].Secret, HasLen, 32)
+
+	keys, err = server.ParseEncryptionKeys("")
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 0)
+
+	_, err = server.ParseEncryptionKeys("broken")
+	c.Assert(err, NotNil)
+
+	_, err = server.ParseEncryptionKeys("key:???")
+	c.Assert(err, NotNil)
+}
+
+func (ft *FieldCipherTestSuite) TestNewFieldCipher(c *C) {
+	blindKey := bytes.Repeat([]byte{'b'}, 32)
+
+	// without keys fields aren't encrypted
+	fc, err := server.NewFieldCipher(nil, "", nil)
+	c.Assert(err, IsNil)
+	c.Assert(fc, IsNil)
+
+	_, err = server.NewFieldCipher([]*server.EncryptionKey{{ID: "short", Secret: []byte("short")}}, "", blindKey)
+	c.Assert(err, NotNil)
+
+	_, err = server.NewFieldCipher([]*server.EncryptionKey{testEncryptionKey("k", 1), testEncryptionKey("k", 2)}, "", blindKey)
+	c.Assert(err, NotNil)
+
+	_, err = server.NewFieldCipher([]*server.EncryptionKey{testEncryptionKey("k", 1)}, "unknown", blindKey)
+	c.Assert(err, NotNil)
+
+	_, err = server.NewFieldCipher([]*server.EncryptionKey{testEncryptionKey("k", 1)}, "", []byte("short"))
+	c.Assert(err, NotNil)
+
+	fc, err = server.NewFieldCipher([]*server.EncryptionKey{testEncryptionKey("old", 1), testEncryptionKey("new", 2)}, "new", blindKey)
+	c.Assert(err, IsNil)
+	c.Assert(fc.ActiveKeyID(), Equals, "new")
+}
+
+func (ft *FieldCipherTestSuite) TestSealAndOpen(c *C) {
+	fc := testFieldCipher(c, testEncryptionKey("k1", 1))
+
+	sealed, err := fc.Seal(bson.M{"phone": "+31612345678"}, "users:u1")
+	c.Assert(err, IsNil)
+	c.Assert(sealed.KeyID, Equals, "k1")
+	c.Assert(bytes.Contains(sealed.Data, []byte("+31612345678")), Equals, false)
+
+	fields := bson.M{}
+	c.Assert(fc.Open(sealed, "users:u1", fields), IsNil)
+	c.Assert(fields["phone"], Equals, "+31612345678")
+
+	// every document has own data key
+	another, err := fc.Seal(bson.M{"phone": "+31612345678"}, "users:u1")
+	c.Assert(err, IsNil)
+	c.Assert(another.DataKey, Not(DeepEquals), sealed.DataKey)
+
+	// sealed fields can't be moved to another document or changed
+	c.Assert(fc.Open(sealed, "users:u2", bson.M{}), Equals, server.ErrSealedFieldsCorrupted)
+
+	sealed.Data[len(sealed.Data)-1] ^= 1
+	c.Assert(fc.Open(sealed, "users:u1", bson.M{}), Equals, server.ErrSealedFieldsCorrupted)
+
+	var missing *server.FieldCipher
+	c.Assert(missing.Open(another, "users:u1", bson.M{}), Equals, server.ErrEncryptionKeysMissing)
+}
+
+func (ft *FieldCipherTestSuite) TestKeyRotation(c *C) {
+	old := testFieldCipher(c, testEncryptionKey("old", 1))
+	sealed, err := old.Seal(bson.M{"phone": "1"}, "users:u1")
+	c.Assert(err, IsNil)
+
+	// retired key opens sealed fields until they are sealed again
+	rotated := testFieldCipher(c, testEncryptionKey("new", 2), testEncryptionKey("old", 1))
+	fields := bson.M{}
+	c.Assert(rotated.Open(sealed, "users:u1", fields), IsNil)
+	c.Assert(fields["phone"], Equals, "1")
+	c.Assert(rotated.NeedsReseal(sealed), Equals, true)
+	c.Assert(rotated.NeedsReseal(nil), Equals, true)
+
+	resealed, err := rotated.Seal(fields, "users:u1")
+	c.Assert(err, IsNil)
+	c.Assert(rotated.NeedsReseal(resealed), Equals, false)
+
+	withoutOld := testFieldCipher(c, testEncryptionKey("new", 2))
+	c.Assert(withoutOld.Open(sealed, "users:u1", bson.M{}), Equals, server.ErrUnknownEncryptionKey)
+	c.Assert(withoutOld.Open(resealed, "users:u1", bson.M{}), IsNil)
+
+	// blind indexes don't depend on master keys
+	c.Assert(rotated.BlindIndex("bfinumber", "123"), Equals, old.BlindIndex("bfinumber", "123"))
+}
+
+func (ft *FieldCipherTestSuite) TestBlindIndex(c *C) {
+	fc := testFieldCipher(c, testEncryptionKey("k1", 1))
+
+	index := fc.BlindIndex("bfinumber", "123")
+	c.Assert(index, Not(Equals), "123")
+	c.Assert(fc.BlindIndex("bfinumber", "123"), Equals, index)
+	c.Assert(fc.BlindIndex("bfinumber", "124"), Not(Equals), index)
+	c.Assert(fc.BlindIndex("nationality", "123"), Not(Equals), index)
+	c.Assert(fc.BlindIndex("bfinumber", ""), Equals, "")
+
+	var missing *server.FieldCipher
+	c.Assert(missing.BlindIndex("bfinumber", "123"), Equals, "123")
+}
+
+func (ft *FieldCipherTestSuite) TestEntityEncryption(c *C) {
+	ft.sess = testMongoDatabase(c)
+	server.SetFieldCipher(testFieldCipher(c, testEncryptionKey("old", 1)))
+
+	repo := server.NewEntityRepo(ft.sess)
+	migrateTestDatabase(c, ft.sess)
+
+	_, err := repo.CreateEntity(&grpc_gateway_entity.Entity{
+		Id:                 "e1",
+		CompanyId:          "c1",
+		CommonName:         "Jan Jansen",
+		Birthday:           "1970-01-01",
+		Birthplace:         "Utrecht",
+		Nationality:        "Nederlandse",
+		BfiNumber:          "BFI-42",
+		ResidentialAddress: &grpc_gateway_common.Address{Country: "Nederland"},
+		Latest:             true,
+	})
+	c.Assert(err, IsNil)
+
+	// personal data isn't stored in plaintext
+	raw := bson.Raw{}
+	c.Assert(ft.sess.C("entities").Find(bson.M{"id": "e1"}).One(&raw), IsNil)
+	for _, value := range []string{"1970-01-01", "Utrecht", "Nederlandse", "BFI-42", "Nederland"} {
+		c.Assert(bytes.Contains(raw.Data, []byte(value)), Equals, false)
+	}
+
+	entity, err := repo.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(entity.Birthplace, Equals, "Utrecht")
+	c.Assert(entity.ResidentialAddress.Country, Equals, "Nederland")
+
+	// equality lookups work by blind indexes
+	for _, filter := range []*grpc_gateway_entity.EntitySearchRequest{
+		{BfiNumber: "BFI-42"},
+		{Nationality: "nederlandse"},
+		{Country: "NEDERLAND"},
+	} {
+		list, err := repo.SearchEntities("c1", filter, nil)
+		c.Assert(err, IsNil)
+		c.Assert(list.Data, HasLen, 1)
+	}
+
+	entity.Birthplace = "Amsterdam"
+	_, err = repo.UpdateEntity(entity, entity.Rev)
+	c.Assert(err, IsNil)
+
+	// after rotation both revisions are sealed by new key
+	server.SetFieldCipher(testFieldCipher(c, testEncryptionKey("new", 2), testEncryptionKey("old", 1)))
+	repo = server.NewEntityRepo(ft.sess)
+
+	changed, err := repo.Reseal()
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, 2)
+
+	changed, err = repo.Reseal()
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, 0)
+
+	server.SetFieldCipher(testFieldCipher(c, testEncryptionKey("new", 2)))
+	repo = server.NewEntityRepo(ft.sess)
+
+	revs, err := repo.GetEntityRevs("e1", "c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 2)
+	c.Assert(revs.Data[0].Birthplace, Equals, "Amsterdam")
+	c.Assert(revs.Data[1].Birthplace, Equals, "Utrecht")
+	c.Assert(revs.Data[1].BfiNumber, Equals, "BFI-42")
+}
+
+func (ft *FieldCipherTestSuite) TestUserEncryption(c *C) {
+	ft.sess = testMongoDatabase(c)
+
+	// user is stored before encryption is configured
+	repo := server.NewUserRepo(ft.sess)
+	user := &grpc_gateway_user.User{Email: "phone@test.com", Name: "phone", CompanyId: "c1"}
+	c.Assert(repo.CreateUser(user), IsNil)
+	c.Assert(repo.EnableUserAndSetPasswordPhone(user.Id, "12345", "+31611111111"), IsNil)
+
+	server.SetFieldCipher(testFieldCipher(c, testEncryptionKey("k1", 1)))
+	repo = server.NewUserRepo(ft.sess)
+
+	// plaintext user is readable and is sealed by rotation
+	stored, err := repo.GetUserByID(user.Id)
+	c.Assert(err, IsNil)
+	c.Assert(stored.Phone, Equals, "+31611111111")
+
+	changed, err := repo.Reseal()
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, 1)
+
+	raw := bson.Raw{}
+	c.Assert(ft.sess.C("users").Find(bson.M{"id": user.Id}).One(&raw), IsNil)
+	c.Assert(bytes.Contains(raw.Data, []byte("+31611111111")), Equals, false)
+
+	stored, err = repo.LoginUser("phone@test.com", "12345")
+	c.Assert(err, IsNil)
+	c.Assert(stored.Phone, Equals, "+31611111111")
+
+	updated, err := repo.UpdateUserByID(stored, &grpc_gateway_user.User{Id: user.Id, Email: stored.Email, Phone: "+31622222222"}, stored.Role, stored.CompanyId)
+	c.Assert(err, IsNil)
+	c.Assert(updated.Phone, Equals, "+31622222222")
+
+	c.Assert(ft.sess.C("users").Find(bson.M{"id": user.Id}).One(&raw), IsNil)
+	c.Assert(bytes.Contains(raw.Data, []byte("+31622222222")), Equals, false)
+
+	users, err := repo.GetUsersByCompanyID("c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(users.Data, HasLen, 1)
+	c.Assert(users.Data[0].Phone, Equals, "+31622222222")
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// ErrInvalidSSOState - error when sso callback has unknown, used or expired state
+var ErrInvalidSSOState = errors.New("sso login is expired or was already completed")
+
+// SSOState - pending sso login, which binds callback of identity provider to company and nonce of id token
+type SSOState struct {
+	State     string
+	CompanyID string
+	Nonce     string
+	ExpiresAt int64
+}
+
+// IdentityProviderRepo - model for accessing identity providers of companies and pending sso logins in database
+type IdentityProviderRepo struct {
+	sess       *mgo.Database
+	coll       string
+	statesColl string
+}
+
+// NewIdentityProviderRepo - returns new instance of IdentityProviderRepo
+func NewIdentityProviderRepo(sess *mgo.Database) *IdentityProviderRepo {
+	return &IdentityProviderRepo{
+		sess:       sess,
+		coll:       "identity_providers",
+		statesColl: "sso_states",
+	}
+}
+
+// SetIdentityProvider - create or replace identity provider of company
+func (ir *IdentityProviderRepo) SetIdentityProvider(idp *grpc_gateway_company.IdentityProvider) error {
+	c := ir.sess.C(ir.coll)
+	_, err := c.Upsert(bson.M{"companyid": idp.CompanyId}, idp)
+	return err
+}
+
+// GetIdentityProvider - get identity provider of company
+func (ir *IdentityProviderRepo) GetIdentityProvider(companyID string) (*grpc_gateway_company.IdentityProvider, error) {
+	c := ir.sess.C(ir.coll)
+	var idp grpc_gateway_company.IdentityProvider
+
+	err := c.Find(bson.M{"companyid": companyID}).One(&idp)
+	return &idp, err
+}
+
+// RemoveIdentityProvider - remove identity provider of company, missing provider isn't an error
+func (ir *IdentityProviderRepo) RemoveIdentityProvider(companyID string) error {
+	c := ir.sess.C(ir.coll)
+	_, err := c.RemoveAll(bson.M{"companyid": companyID})
+	return err
+}
+
+// CreateSSOState - save pending sso login
+func (ir *IdentityProviderRepo) CreateSSOState(state, companyID, nonce string, ttl time.Duration) error {
+	c := ir.sess.C(ir.statesColl)
+	return c.Insert(&SSOState{
+		State:     state,
+		CompanyID: companyID,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+}
+
+// ConsumeSSOState - get and remove pending sso login, so every state can be used only once
+func (ir *IdentityProviderRepo) ConsumeSSOState(state string) (*SSOState, error) {
+	c := ir.sess.C(ir.statesColl)
+	var ssoState SSOState
+
+	_, err := c.Find(bson.M{"state": state}).Apply(mgo.Change{Remove: true}, &ssoState)
+	if err != nil || ssoState.ExpiresAt <= time.Now().Unix() {
+		return nil, ErrInvalidSSOState
+	}
+
+	return &ssoState, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+	"net/http"
+)
+
+// ErrImpersonationNotAllowed - error when admin tries to impersonate user who can't be impersonated
+var ErrImpersonationNotAllowed = errors.New("this user can't be impersonated")
+
+func (s *userServer) Impersonate(ctx context.Context, in *grpc_gateway_user.ImpersonateRequest) (*grpc_gateway_user.LoginResponse, error) {
+	message := NewLoginResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	if in.Reason == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrMissedRequiredField.Error()
+		return message, nil
+	}
+
+	adminID := ctx.Value("user_id").(string)
+	user, ok := getManagedUser(ctx, sess, in.UserId, message.Meta)
+	if !ok {
+		return message, nil
+	}
+
+	// only regular users of companies can be impersonated
+	if IsPlatformAdmin(user) || user.IsServiceAccount || !user.IsEnabled {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = ErrImpersonationNotAllowed.Error()
+		return message, nil
+	}
+
+	address := ClientAddress(ctx)
+	session, err := sess.Sessions().CreateImpersonationSession(user.Id, adminID, clientUserAgent(ctx), address, s.config.ImpersonationTTL)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	glog.Infof("IMPERSONATION: admin %v started session %v as user %v: %v", adminID, session.Id, user.Id, in.Reason)
+	err = sess.Audit().Record(&AuditRecord{
+		Action:         AuditImpersonationStarted,
+		UserID:         user.Id,
+		CompanyID:      user.CompanyId,
+		ImpersonatorID: adminID,
+		Address:        address,
+		Details:        in.Reason,
+	})
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err := s.issueAccessToken(message, user, session); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
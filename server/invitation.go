@@ -0,0 +1,169 @@
+package server
+
+import (
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2"
+	"net/http"
+	"time"
+)
+
+// NewInvitationListResponse - create new instance of invitation list response
+func NewInvitationListResponse() *grpc_gateway_user.InvitationListResponse {
+	message := &grpc_gateway_user.InvitationListResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	message.Data = []*grpc_gateway_user.Invitation{}
+	return message
+}
+
+// sendInvitation - regenerate email code of invited user and send new invitation
+func sendInvitation(repo UserStorage, userID string) error {
+	user, err := repo.RegenerateEmailCode(userID)
+	if err != nil {
+		return err
+	}
+
+	GetEmailSenderInstance().SendEmailConfirmation(user.Name, user.Email, user.EmailCode)
+	return nil
+}
+
+// RenewInvitation - send new invitation to user whose invitation link is expired. Only holder of expired link
+// can renew it, so invitations can't be sent to arbitrary addresses
+func (s *userServer) RenewInvitation(ctx context.Context, in *grpc_gateway_user.InvitationRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.Users()
+
+	user, err := repo.GetUserByEmailCode(&grpc_gateway_user.User{EmailCode: in.EmailCode})
+	if err != nil || in.EmailCode == "" {
+		message.Meta.StatusCode = http.StatusNotFound
+		message.Meta.Ok = false
+		message.Meta.Error = ErrNotFound.Error()
+		return message, nil
+	}
+
+	if user.IsConfirmed {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrInvitationAccepted.Error()
+		return message, nil
+	}
+
+	// valid link should be used as is, link without sending time is treated as expired
+	if user.EmailSentAt != nil && !time.Unix(user.EmailSentAt.Seconds, 0).Add(s.config.EmailConfirmationTTL).Before(time.Now()) {
+		message.Meta.Ok = false
+		message.Meta.Error = "email code isn't expired yet"
+		return message, nil
+	}
+
+	if err := sendInvitation(repo, user.Id); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) ResendInvitation(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.Users()
+
+	user, err := repo.GetPendingUserByID(in.Id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err = CheckPermission(ctx, PermissionUserManage, user.CompanyId); err != nil {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err := sendInvitation(repo, user.Id); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) ListPendingInvitations(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_user.InvitationListResponse, error) {
+	message := NewInvitationListResponse()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, InvitationSortFields, "email")
+	if err != nil {
+		message.Meta.StatusCode = http.StatusBadRequest
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	users, err := sess.Users().GetPendingUsersByCompanyID(in.Id, page)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	now := time.Now()
+	for _, user := range users.Data {
+		invitation := &grpc_gateway_user.Invitation{
+			UserId:    user.Id,
+			Email:     user.Email,
+			Name:      user.Name,
+			CompanyId: user.CompanyId,
+			Role:      UserRole(user),
+		}
+
+		if user.EmailSentAt != nil {
+			sentAt := time.Unix(user.EmailSentAt.Seconds, 0)
+			invitation.SentAt = sentAt.Unix()
+			invitation.ExpiresAt = sentAt.Add(s.config.EmailConfirmationTTL).Unix()
+		}
+		invitation.IsExpired = invitation.ExpiresAt <= now.Unix()
+
+		message.Data = append(message.Data, invitation)
+	}
+
+	message.NextCursor = users.NextCursor
+	message.Total = users.Total
+
+	message.Meta.Ok = true
+	return message, nil
+}
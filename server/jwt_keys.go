@@ -0,0 +1,152 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/glog"
+	"strings"
+	"time"
+)
+
+// ErrUnknownJWTKey - error when token was signed with key which isn't in key set
+var ErrUnknownJWTKey = errors.New("token signed with unknown key")
+
+// ErrJWTKeyRetired - error when token was signed with key which is retired and passed its cutoff
+var ErrJWTKeyRetired = errors.New("token signed with retired key")
+
+// JWTKey - key for signing and verification of access tokens
+type JWTKey struct {
+	ID     string
+	Secret []byte
+
+	// ValidUntil - cutoff for retired keys. Zero value means that key doesn't have cutoff
+	ValidUntil time.Time
+}
+
+// JWTKeySet - set of keys: one active key for signing and all known keys for verification
+type JWTKeySet struct {
+	active *JWTKey
+	keys   map[string]*JWTKey
+}
+
+var jwtKeySetInstance *JWTKeySet
+
+// GetJWTKeySet - return key set which is used by server
+func GetJWTKeySet() *JWTKeySet {
+	return jwtKeySetInstance
+}
+
+// ParseJWTKeys - parse keys from string in format "kid:base64-secret[:valid-until],...",
+// where valid-until is optional cutoff in RFC3339 format for retired keys
+func ParseJWTKeys(spec string) ([]*JWTKey, error) {
+	keys := []*JWTKey{}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("wrong format of jwt key %q", parts[0])
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("wrong secret of jwt key %q: %v", parts[0], err)
+		}
+
+		key := &JWTKey{
+			ID:     parts[0],
+			Secret: secret,
+		}
+
+		if len(parts) == 3 {
+			key.ValidUntil, err = time.Parse(time.RFC3339, parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("wrong cutoff of jwt key %q: %v", parts[0], err)
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// NewJWTKeySet - create key set. If activeKeyID is empty the first key is used for signing.
+// If there are no keys at all, random key is generated, so tokens don't survive restart
+func NewJWTKeySet(keys []*JWTKey, activeKeyID string) (*JWTKeySet, error) {
+	if len(keys) == 0 {
+		secret := make([]byte, 64)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+
+		glog.Warning("jwt keys aren't configured, random key is used for signing")
+		keys = []*JWTKey{{ID: "generated", Secret: secret}}
+	}
+
+	ks := &JWTKeySet{
+		keys: make(map[string]*JWTKey),
+	}
+
+	for _, key := range keys {
+		if len(key.Secret) == 0 {
+			return nil, fmt.Errorf("jwt key %q has empty secret", key.ID)
+		}
+
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwt key %q is duplicated", key.ID)
+		}
+
+		ks.keys[key.ID] = key
+	}
+
+	if activeKeyID == "" {
+		activeKeyID = keys[0].ID
+	}
+
+	active, ok := ks.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q isn't configured", activeKeyID)
+	}
+
+	if !active.ValidUntil.IsZero() {
+		return nil, fmt.Errorf("active jwt key %q is retired", activeKeyID)
+	}
+
+	ks.active = active
+	return ks, nil
+}
+
+// SignToken - stamp token with id of active key and sign it
+func (ks *JWTKeySet) SignToken(token *jwt.Token) (string, error) {
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.Secret)
+}
+
+// ParseToken - parse token and verify it with key from its kid header
+func (ks *JWTKeySet) ParseToken(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, ErrUnknownJWTKey
+		}
+
+		if !key.ValidUntil.IsZero() && key.ValidUntil.Before(time.Now()) {
+			return nil, ErrJWTKeyRetired
+		}
+
+		return key.Secret, nil
+	})
+}
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"encoding/base64"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	"github.com/dgrijalva/jwt-go"
+	. "gopkg.in/check.v1"
+	"time"
+)
+
+type JWTKeysTestSuite struct{}
+
+var _ = Suite(&JWTKeysTestSuite{})
+
+func newTestToken() *jwt.Token {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["user_id"] = "test"
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	return token
+}
+
+func (jt *JWTKeysTestSuite) TestParseJWTKeys(c *C) {
+	secret := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	keys, err := server.ParseJWTKeys("new:" + secret + ", old:" + secret + ":2017-07-01T00:00:00Z")
+	c.Assert(err, IsNil)
+	c.Assert(len(keys), Equals, 2)
+	c.Assert(keys[0].ID, Equals, "new")
+	c.Assert(string(keys[0].Secret), Equals, "secret")
+	c.Assert(keys[0].ValidUntil.IsZero(), Equals, true)
+	c.Assert(keys[1].ID, Equals, "old")
+	c.Assert(keys[1].ValidUntil.Year(), Equals, 2017)
+
+	keys, err = server.ParseJWTKeys("")
+	c.Assert(err, IsNil)
+	c.Assert(len(keys), Equals, 0)
+
+	_, err = server.ParseJWTKeys("broken")
+	c.Assert(err, NotNil)
+
+	_, err = server.ParseJWTKeys("old:" + secret + ":yesterday")
+	c.Assert(err, NotNil)
+}
+
+func (jt *JWTKeysTestSuite) TestNewJWTKeySet(c *C) {
+	// active key can't be retired
+	_, err := server.NewJWTKeySet([]*server.JWTKey{
+		{ID: "old", Secret: []byte("old"), ValidUntil: time.Now().Add(time.Hour)},
+	}, "old")
+	c.Assert(err, NotNil)
+
+	// active key should be configured
+	_, err = server.NewJWTKeySet([]*server.JWTKey{{ID: "new", Secret: []byte("new")}}, "unknown")
+	c.Assert(err, NotNil)
+
+	// without keys random key is generated
+	ks, err := server.NewJWTKeySet(nil, "")
+	c.Assert(err, IsNil)
+
+	tokenString, err := ks.SignToken(newTestToken())
+	c.Assert(err, IsNil)
+
+	token, err := ks.ParseToken(tokenString)
+	c.Assert(err, IsNil)
+	c.Assert(token.Valid, Equals, true)
+}
+
+func (jt *JWTKeysTestSuite) TestKeyRotation(c *C) {
+	oldKey := &server.JWTKey{ID: "old", Secret: []byte("old secret")}
+	newKey := &server.JWTKey{ID: "new", Secret: []byte("new secret")}
+
+	oldKeySet, err := server.NewJWTKeySet([]*server.JWTKey{oldKey}, "")
+	c.Assert(err, IsNil)
+
+	oldTokenString, err := oldKeySet.SignToken(newTestToken())
+	c.Assert(err, IsNil)
+
+	// rotate keys: old key stays valid for verification until cutoff
+	retiredKey := &server.JWTKey{ID: "old", Secret: oldKey.Secret, ValidUntil: time.Now().Add(time.Hour)}
+	ks, err := server.NewJWTKeySet([]*server.JWTKey{newKey, retiredKey}, "new")
+	c.Assert(err, IsNil)
+
+	newTokenString, err := ks.SignToken(newTestToken())
+	c.Assert(err, IsNil)
+
+	token, err := ks.ParseToken(newTokenString)
+	c.Assert(err, IsNil)
+	c.Assert(token.Header["kid"], Equals, "new")
+
+	token, err = ks.ParseToken(oldTokenString)
+	c.Assert(err, IsNil)
+	c.Assert(token.Header["kid"], Equals, "old")
+
+	// after cutoff tokens signed by old key are rejected
+	retiredKey.ValidUntil = time.Now().Add(-time.Second)
+	_, err = ks.ParseToken(oldTokenString)
+	c.Assert(err, NotNil)
+
+	// tokens signed by unknown keys are rejected
+	otherKeySet, err := server.NewJWTKeySet([]*server.JWTKey{{ID: "other", Secret: []byte("other")}}, "")
+	c.Assert(err, IsNil)
+
+	_, err = otherKeySet.ParseToken(newTokenString)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+	"time"
+)
+
+// ErrAccountLocked - error when account or client address is temporary locked after too many failed attempts
+var ErrAccountLocked = errors.New("too many failed attempts, try again later")
+
+// LoginAttempt - counter of failed attempts for account or client address
+type LoginAttempt struct {
+	Key         string
+	Failures    int
+	LockedUntil time.Time
+	UpdatedAt   time.Time
+}
+
+// LoginAttemptRepo - model for accessing counters of failed login attempts in database
+type LoginAttemptRepo struct {
+	sess *mgo.Database
+	coll string
+}
+
+// NewLoginAttemptRepo - returns new instance of LoginAttemptRepo
+func NewLoginAttemptRepo(sess *mgo.Database) *LoginAttemptRepo {
+	return &LoginAttemptRepo{
+		sess: sess,
+		coll: "login_attempts",
+	}
+}
+
+// AccountAttemptKey - key of counter for account
+func AccountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// AddressAttemptKey - key of counter for client address
+func AddressAttemptKey(addr string) string {
+	return "ip:" + addr
+}
+
+// lockoutDuration - lockout grows exponentially with each failure after threshold and is capped by max
+func lockoutDuration(failures, threshold int, base, max time.Duration) time.Duration {
+	lockout := base
+	for i := threshold; i < failures && lockout < max; i++ {
+		lockout *= 2
+	}
+
+	if lockout > max {
+		lockout = max
+	}
+
+	return lockout
+}
+
+// GetLockout - return longest remaining lockout of keys, zero if none of them is locked
+func (lr *LoginAttemptRepo) GetLockout(keys ...string) (time.Duration, error) {
+	c := lr.sess.C(lr.coll)
+	var attempts []LoginAttempt
+
+	now := time.Now()
+	err := c.Find(bson.M{"key": bson.M{"$in": keys}, "lockeduntil": bson.M{"$gt": now}}).All(&attempts)
+	if err != nil {
+		return 0, err
+	}
+
+	var lockout time.Duration
+	for _, attempt := range attempts {
+		if left := attempt.LockedUntil.Sub(now); left > lockout {
+			lockout = left
+		}
+	}
+
+	return lockout, nil
+}
+
+// RegisterFailure - increment counter of key and lock it when threshold is reached. Counters which weren't
+// updated during window are started from scratch. Returns lockout, zero if key isn't locked
+func (lr *LoginAttemptRepo) RegisterFailure(key string, threshold int, base, max, window time.Duration) (time.Duration, error) {
+	c := lr.sess.C(lr.coll)
+	now := time.Now()
+
+	// forget stale failures
+	c.RemoveAll(bson.M{"key": key, "updatedat": bson.M{"$lt": now.Add(-window)}, "lockeduntil": bson.M{"$lt": now}})
+
+	var attempt LoginAttempt
+	change := mgo.Change{
+		Update:    bson.M{"$inc": bson.M{"failures": 1}, "$set": bson.M{"updatedat": now}},
+		Upsert:    true,
+		ReturnNew: true,
+	}
+
+	if _, err := c.Find(bson.M{"key": key}).Apply(change, &attempt); err != nil {
+		return 0, err
+	}
+
+	if attempt.Failures < threshold {
+		return 0, nil
+	}
+
+	lockout := lockoutDuration(attempt.Failures, threshold, base, max)
+	return lockout, c.Update(bson.M{"key": key}, bson.M{"$set": bson.M{"lockeduntil": now.Add(lockout)}})
+}
+
+// Reset - remove counters and lockouts of keys
+func (lr *LoginAttemptRepo) Reset(keys ...string) error {
+	c := lr.sess.C(lr.coll)
+	_, err := c.RemoveAll(bson.M{"key": bson.M{"$in": keys}})
+	return err
+}
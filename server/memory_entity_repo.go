@@ -0,0 +1,386 @@
+package server
+
+import (
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/mgo.v2"
+	"sort"
+	"sync"
+)
+
+// memoryEntityRepo - all revisions of entities in memory
+type memoryEntityRepo struct {
+	mu       sync.Mutex
+	entities []*grpc_gateway_entity.Entity
+
+	// staged - import id of staged entities by entity id, entity model doesn't have this field
+	staged map[string]string
+}
+
+func cloneEntity(entity *grpc_gateway_entity.Entity) *grpc_gateway_entity.Entity {
+	return proto.Clone(entity).(*grpc_gateway_entity.Entity)
+}
+
+// insert - add revision of entity, every revision can be stored only once and only one revision
+// can be latest. Should be called under lock
+func (mr *memoryEntityRepo) insert(entity *grpc_gateway_entity.Entity) error {
+	for _, stored := range mr.entities {
+		if stored.Id == entity.Id && (stored.Rev == entity.Rev || (stored.Latest && entity.Latest)) {
+			return ErrDuplicateKey
+		}
+	}
+
+	mr.entities = append(mr.entities, cloneEntity(entity))
+	return nil
+}
+
+// latest - latest revision of entity, which can be deleted. Should be called under lock
+func (mr *memoryEntityRepo) latest(id, companyID string) *grpc_gateway_entity.Entity {
+	for _, entity := range mr.entities {
+		if entity.Id == id && entity.CompanyId == companyID && entity.Latest {
+			return entity
+		}
+	}
+
+	return nil
+}
+
+func (mr *memoryEntityRepo) CreateEntity(entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	return entity, mr.insert(entity)
+}
+
+func (mr *memoryEntityRepo) GetLatestEntity(id, companyID string) (*grpc_gateway_entity.Entity, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if entity := mr.latest(id, companyID); entity != nil && !entity.IsDeleted {
+		return cloneEntity(entity), nil
+	}
+
+	return &grpc_gateway_entity.Entity{}, mgo.ErrNotFound
+}
+
+func (mr *memoryEntityRepo) GetEntityRevs(id, companyID string, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entities := NewEntityListResponse()
+	for _, entity := range mr.entities {
+		if entity.Id == id && entity.CompanyId == companyID && mr.staged[entity.Id] == "" {
+			entities.Data = append(entities.Data, cloneEntity(entity))
+		}
+	}
+
+	sort.SliceStable(entities.Data, func(i, j int) bool {
+		return entities.Data[i].Rev > entities.Data[j].Rev
+	})
+
+	next, total, err := memoryPage(&entities.Data, page)
+	entities.NextCursor = next
+	entities.Total = int64(total)
+	return entities, err
+}
+
+func (mr *memoryEntityRepo) GetCompanyRevisions(companyID string) (*grpc_gateway_entity.EntityListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entities := NewEntityListResponse()
+	for _, entity := range mr.entities {
+		if entity.CompanyId == companyID && mr.staged[entity.Id] == "" {
+			entities.Data = append(entities.Data, cloneEntity(entity))
+		}
+	}
+
+	sort.SliceStable(entities.Data, func(i, j int) bool {
+		if entities.Data[i].Id != entities.Data[j].Id {
+			return entities.Data[i].Id < entities.Data[j].Id
+		}
+		return entities.Data[i].Rev < entities.Data[j].Rev
+	})
+	return entities, nil
+}
+
+func (mr *memoryEntityRepo) HasEntity(id string) (bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, entity := range mr.entities {
+		if entity.Id == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (mr *memoryEntityRepo) GetEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	return mr.listPage(companyID, params, false, page)
+}
+
+func (mr *memoryEntityRepo) GetDeletedEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	return mr.listPage(companyID, params, true, page)
+}
+
+func (mr *memoryEntityRepo) SearchEntities(companyID string, filter *grpc_gateway_entity.EntitySearchRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entities := NewEntityListResponse()
+	if companyID == "" {
+		return entities, ErrMissedRequiredField
+	}
+
+	search := newEntitySearch(filter, nil)
+	for _, entity := range mr.entities {
+		if !entity.Latest || entity.IsDeleted || entity.CompanyId != companyID || !search.matches(entity) {
+			continue
+		}
+
+		entities.Data = append(entities.Data, cloneEntity(entity))
+	}
+
+	next, total, err := memoryPage(&entities.Data, page)
+	entities.NextCursor = next
+	entities.Total = int64(total)
+	return entities, err
+}
+
+// listPage - page of latest revisions of entities which are deleted or not
+func (mr *memoryEntityRepo) listPage(companyID string, params *grpc_gateway_entity.EntityListRequest, deleted bool, page *Page) (*grpc_gateway_entity.EntityListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entities := NewEntityListResponse()
+	for _, entity := range mr.entities {
+		if !entity.Latest || entity.IsDeleted != deleted || (params.Type != "" && entity.Type != params.Type) || (companyID != "" && entity.CompanyId != companyID) {
+			continue
+		}
+
+		entities.Data = append(entities.Data, cloneEntity(entity))
+	}
+
+	next, total, err := memoryPage(&entities.Data, page)
+	entities.NextCursor = next
+	entities.Total = int64(total)
+	return entities, err
+}
+
+func (mr *memoryEntityRepo) DeleteEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	oldEntity := mr.latest(id, companyID)
+	if oldEntity == nil || oldEntity.IsDeleted {
+		return nil, mgo.ErrNotFound
+	}
+
+	return mr.addRevision(deletedRevision(cloneEntity(oldEntity), userID), oldEntity)
+}
+
+func (mr *memoryEntityRepo) RestoreEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	oldEntity := mr.latest(id, companyID)
+	if oldEntity == nil || !oldEntity.IsDeleted {
+		return nil, mgo.ErrNotFound
+	}
+
+	return mr.addRevision(restoredRevision(cloneEntity(oldEntity), userID), oldEntity)
+}
+
+func (mr *memoryEntityRepo) PurgeDeletedEntities(deletedBefore int64) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	purged := map[string]bool{}
+	for _, entity := range mr.entities {
+		if entity.Latest && entity.IsDeleted && entity.DeletedAt < deletedBefore {
+			purged[entity.Id] = true
+		}
+	}
+
+	entities := []*grpc_gateway_entity.Entity{}
+	for _, entity := range mr.entities {
+		if !purged[entity.Id] {
+			entities = append(entities, entity)
+		}
+	}
+
+	mr.entities = entities
+	return len(purged), nil
+}
+
+func (mr *memoryEntityRepo) RemoveEntities(companyID string, ids []string) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	removed := map[string]bool{}
+	for _, id := range ids {
+		removed[id] = true
+	}
+
+	entities := []*grpc_gateway_entity.Entity{}
+	for _, entity := range mr.entities {
+		if entity.CompanyId != companyID || !removed[entity.Id] {
+			entities = append(entities, entity)
+		}
+	}
+
+	count := len(mr.entities) - len(entities)
+	mr.entities = entities
+	return count, nil
+}
+
+func (mr *memoryEntityRepo) StageEntity(entity *grpc_gateway_entity.Entity, importID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entity.Latest = false
+	if err := mr.insert(entity); err != nil {
+		return err
+	}
+
+	if mr.staged == nil {
+		mr.staged = map[string]string{}
+	}
+	mr.staged[entity.Id] = importID
+	return nil
+}
+
+func (mr *memoryEntityRepo) PublishStagedEntities(companyID, importID string) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	published := 0
+	for _, entity := range mr.entities {
+		if entity.CompanyId == companyID && mr.staged[entity.Id] == importID && importID != "" {
+			entity.Latest = true
+			delete(mr.staged, entity.Id)
+			published++
+		}
+	}
+
+	return published, nil
+}
+
+func (mr *memoryEntityRepo) RemoveStagedEntities(companyID, importID string) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	entities := []*grpc_gateway_entity.Entity{}
+	for _, entity := range mr.entities {
+		if entity.CompanyId == companyID && mr.staged[entity.Id] == importID && importID != "" {
+			delete(mr.staged, entity.Id)
+			continue
+		}
+
+		entities = append(entities, entity)
+	}
+
+	removed := len(mr.entities) - len(entities)
+	mr.entities = entities
+	return removed, nil
+}
+
+func (mr *memoryEntityRepo) UpdateEntity(entity *grpc_gateway_entity.Entity, baseRev int64) (*grpc_gateway_entity.Entity, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	oldEntity := mr.latest(entity.Id, entity.CompanyId)
+	if oldEntity == nil || oldEntity.IsDeleted {
+		return nil, mgo.ErrNotFound
+	}
+
+	if oldEntity.Rev != baseRev {
+		return nil, ErrEntityConflict
+	}
+
+	entity.IsDeleted = false
+	entity.DeletedAt = 0
+	entity.DeletedBy = ""
+	return mr.addRevision(entity, oldEntity)
+}
+
+// addRevision - store entity as revision next to latest revision oldEntity, should be called under lock
+func (mr *memoryEntityRepo) addRevision(entity, oldEntity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error) {
+	entity.Rev = oldEntity.Rev + 1
+	entity.Latest = true
+	oldEntity.Latest = false
+	if err := mr.insert(entity); err != nil {
+		oldEntity.Latest = true
+		return nil, err
+	}
+
+	return entity, nil
+}
+
+// memoryEntityImportRepo - entity import jobs in memory
+type memoryEntityImportRepo struct {
+	mu   sync.Mutex
+	jobs []*grpc_gateway_entity.EntityImport
+}
+
+func cloneEntityImport(job *grpc_gateway_entity.EntityImport) *grpc_gateway_entity.EntityImport {
+	return proto.Clone(job).(*grpc_gateway_entity.EntityImport)
+}
+
+func (mr *memoryEntityImportRepo) CreateEntityImport(job *grpc_gateway_entity.EntityImport) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, stored := range mr.jobs {
+		if stored.Id == job.Id {
+			return ErrDuplicateKey
+		}
+	}
+
+	mr.jobs = append(mr.jobs, cloneEntityImport(job))
+	return nil
+}
+
+func (mr *memoryEntityImportRepo) UpdateEntityImport(job *grpc_gateway_entity.EntityImport, status string, updatedAt int64) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, stored := range mr.jobs {
+		if stored.Id == job.Id && stored.Status == status && stored.UpdatedAt == updatedAt {
+			mr.jobs[i] = cloneEntityImport(job)
+			return nil
+		}
+	}
+
+	return mgo.ErrNotFound
+}
+
+func (mr *memoryEntityImportRepo) GetStaleEntityImports(updatedBefore int64) ([]*grpc_gateway_entity.EntityImport, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	jobs := []*grpc_gateway_entity.EntityImport{}
+	for _, job := range mr.jobs {
+		unfinished := job.Status == EntityImportPending || job.Status == EntityImportRunning || job.Status == EntityImportPublishing
+		if unfinished && job.UpdatedAt < updatedBefore {
+			jobs = append(jobs, cloneEntityImport(job))
+		}
+	}
+
+	return jobs, nil
+}
+
+func (mr *memoryEntityImportRepo) GetEntityImport(id, companyID string) (*grpc_gateway_entity.EntityImport, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, job := range mr.jobs {
+		if job.Id == id && job.CompanyId == companyID {
+			return cloneEntityImport(job), nil
+		}
+	}
+
+	return &grpc_gateway_entity.EntityImport{}, mgo.ErrNotFound
+}
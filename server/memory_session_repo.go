@@ -0,0 +1,364 @@
+package server
+
+import (
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/protobuf/proto"
+	"gopkg.in/mgo.v2"
+	"sync"
+	"time"
+)
+
+// memorySessionRepo - login sessions in memory
+type memorySessionRepo struct {
+	mu       sync.Mutex
+	sessions []*grpc_gateway_user.Session
+}
+
+func cloneSession(session *grpc_gateway_user.Session) *grpc_gateway_user.Session {
+	return proto.Clone(session).(*grpc_gateway_user.Session)
+}
+
+// isActiveSession - session isn't revoked and isn't expired
+func isActiveSession(session *grpc_gateway_user.Session, now int64) bool {
+	return !session.IsRevoked && session.ExpiresAt > now
+}
+
+func (mr *memorySessionRepo) insert(session *grpc_gateway_user.Session) (*grpc_gateway_user.Session, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.sessions = append(mr.sessions, cloneSession(session))
+	return session, nil
+}
+
+// revoke - revoke all sessions which match filter, returns number of revoked sessions
+func (mr *memorySessionRepo) revoke(match func(*grpc_gateway_user.Session) bool, all bool) int {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	revoked := 0
+	for _, session := range mr.sessions {
+		if match(session) {
+			session.IsRevoked = true
+			revoked++
+
+			if !all {
+				break
+			}
+		}
+	}
+
+	return revoked
+}
+
+func (mr *memorySessionRepo) CreateSession(userID, refreshToken, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error) {
+	session := newSession(userID, userAgent, ipAddress, ttl)
+	session.RefreshToken = hashSecretToken(refreshToken)
+	return mr.insert(session)
+}
+
+func (mr *memorySessionRepo) CreateImpersonationSession(userID, impersonatorID, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error) {
+	session := newSession(userID, userAgent, ipAddress, ttl)
+	session.ImpersonatorId = impersonatorID
+	return mr.insert(session)
+}
+
+func (mr *memorySessionRepo) GetActiveSession(id, userID string) (*grpc_gateway_user.Session, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now().Unix()
+	for _, session := range mr.sessions {
+		if session.Id == id && session.UserId == userID && isActiveSession(session, now) {
+			return cloneSession(session), nil
+		}
+	}
+
+	return &grpc_gateway_user.Session{}, mgo.ErrNotFound
+}
+
+func (mr *memorySessionRepo) GetActiveSessions(userID string, page *Page) (*grpc_gateway_user.SessionListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now().Unix()
+	sessions := NewSessionListResponse()
+	for _, session := range mr.sessions {
+		if session.UserId == userID && isActiveSession(session, now) {
+			sessions.Data = append(sessions.Data, cloneSession(session))
+		}
+	}
+
+	next, total, err := memoryPage(&sessions.Data, page)
+	sessions.NextCursor = next
+	sessions.Total = int64(total)
+	return sessions, err
+}
+
+func (mr *memorySessionRepo) TouchSession(session *grpc_gateway_user.Session) error {
+	now := time.Now().Unix()
+	if !isSessionTouchRequired(session, now) {
+		return nil
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	session.LastSeenAt = now
+	for _, stored := range mr.sessions {
+		if stored.Id == session.Id {
+			stored.LastSeenAt = now
+			return nil
+		}
+	}
+
+	return mgo.ErrNotFound
+}
+
+func (mr *memorySessionRepo) RotateRefreshToken(refreshToken, newRefreshToken string) (*grpc_gateway_user.Session, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	hash := hashSecretToken(refreshToken)
+	now := time.Now().Unix()
+
+	for _, session := range mr.sessions {
+		if session.RefreshToken == hash && isActiveSession(session, now) {
+			session.RefreshToken = hashSecretToken(newRefreshToken)
+			session.PreviousRefreshToken = hash
+			session.LastSeenAt = now
+			return cloneSession(session), nil
+		}
+	}
+
+	// reuse of rotated token revokes its session
+	for _, session := range mr.sessions {
+		if session.PreviousRefreshToken == hash {
+			session.IsRevoked = true
+		}
+	}
+
+	return nil, ErrInvalidRefreshToken
+}
+
+func (mr *memorySessionRepo) RevokeSession(id string) error {
+	revoked := mr.revoke(func(session *grpc_gateway_user.Session) bool {
+		return session.Id == id
+	}, false)
+
+	if revoked == 0 {
+		return mgo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (mr *memorySessionRepo) RevokeUserSession(id, userID string) error {
+	revoked := mr.revoke(func(session *grpc_gateway_user.Session) bool {
+		return session.Id == id && session.UserId == userID
+	}, false)
+
+	if revoked == 0 {
+		return mgo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (mr *memorySessionRepo) RevokeUserSessions(userIDs ...string) error {
+	ids := map[string]bool{}
+	for _, id := range userIDs {
+		ids[id] = true
+	}
+
+	mr.revoke(func(session *grpc_gateway_user.Session) bool {
+		return ids[session.UserId]
+	}, true)
+	return nil
+}
+
+// memoryLoginAttemptRepo - counters of failed login attempts by key
+type memoryLoginAttemptRepo struct {
+	mu       sync.Mutex
+	attempts map[string]*LoginAttempt
+}
+
+func newMemoryLoginAttemptRepo() *memoryLoginAttemptRepo {
+	return &memoryLoginAttemptRepo{attempts: map[string]*LoginAttempt{}}
+}
+
+func (mr *memoryLoginAttemptRepo) GetLockout(keys ...string) (time.Duration, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+	var lockout time.Duration
+	for _, key := range keys {
+		if attempt, ok := mr.attempts[key]; ok {
+			if left := attempt.LockedUntil.Sub(now); left > lockout {
+				lockout = left
+			}
+		}
+	}
+
+	return lockout, nil
+}
+
+func (mr *memoryLoginAttemptRepo) RegisterFailure(key string, threshold int, base, max, window time.Duration) (time.Duration, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	now := time.Now()
+
+	// forget stale failures
+	attempt, ok := mr.attempts[key]
+	if ok && attempt.UpdatedAt.Before(now.Add(-window)) && attempt.LockedUntil.Before(now) {
+		ok = false
+	}
+
+	if !ok {
+		attempt = &LoginAttempt{Key: key}
+		mr.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.UpdatedAt = now
+
+	if attempt.Failures < threshold {
+		return 0, nil
+	}
+
+	lockout := lockoutDuration(attempt.Failures, threshold, base, max)
+	attempt.LockedUntil = now.Add(lockout)
+	return lockout, nil
+}
+
+func (mr *memoryLoginAttemptRepo) Reset(keys ...string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, key := range keys {
+		delete(mr.attempts, key)
+	}
+
+	return nil
+}
+
+// memoryAPIKeyRepo - api keys of service accounts in memory
+type memoryAPIKeyRepo struct {
+	mu      sync.Mutex
+	apiKeys []*grpc_gateway_user.APIKey
+}
+
+func cloneAPIKey(apiKey *grpc_gateway_user.APIKey) *grpc_gateway_user.APIKey {
+	return proto.Clone(apiKey).(*grpc_gateway_user.APIKey)
+}
+
+// find - api key by id, should be called under lock
+func (mr *memoryAPIKeyRepo) find(id string) *grpc_gateway_user.APIKey {
+	for _, apiKey := range mr.apiKeys {
+		if apiKey.Id == id {
+			return apiKey
+		}
+	}
+
+	return nil
+}
+
+func (mr *memoryAPIKeyRepo) CreateAPIKey(serviceAccount *grpc_gateway_user.User, name string, expiresAt int64) (*grpc_gateway_user.APIKey, string, error) {
+	apiKey, key, err := newAPIKey(serviceAccount, name, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.apiKeys = append(mr.apiKeys, cloneAPIKey(apiKey))
+	return apiKey, key, nil
+}
+
+func (mr *memoryAPIKeyRepo) GetAPIKeyByID(id string) (*grpc_gateway_user.APIKey, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if apiKey := mr.find(id); apiKey != nil {
+		return cloneAPIKey(apiKey), nil
+	}
+
+	return &grpc_gateway_user.APIKey{}, mgo.ErrNotFound
+}
+
+func (mr *memoryAPIKeyRepo) GetAPIKeys(serviceAccountID string, page *Page) (*grpc_gateway_user.APIKeyListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	apiKeys := NewAPIKeyListResponse()
+	for _, apiKey := range mr.apiKeys {
+		if apiKey.ServiceAccountId == serviceAccountID {
+			apiKeys.Data = append(apiKeys.Data, cloneAPIKey(apiKey))
+		}
+	}
+
+	next, total, err := memoryPage(&apiKeys.Data, page)
+	apiKeys.NextCursor = next
+	apiKeys.Total = int64(total)
+	return apiKeys, err
+}
+
+func (mr *memoryAPIKeyRepo) RevokeAPIKey(id string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	apiKey := mr.find(id)
+	if apiKey == nil {
+		return mgo.ErrNotFound
+	}
+
+	apiKey.IsRevoked = true
+	return nil
+}
+
+func (mr *memoryAPIKeyRepo) RevokeServiceAccountAPIKeys(serviceAccountIDs ...string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	ids := map[string]bool{}
+	for _, id := range serviceAccountIDs {
+		ids[id] = true
+	}
+
+	for _, apiKey := range mr.apiKeys {
+		if ids[apiKey.ServiceAccountId] {
+			apiKey.IsRevoked = true
+		}
+	}
+
+	return nil
+}
+
+func (mr *memoryAPIKeyRepo) Authenticate(key string) (*grpc_gateway_user.APIKey, error) {
+	id, secret, err := parseAPIKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	apiKey := mr.find(id)
+	if apiKey == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now().Unix()
+	if err := checkAPIKey(apiKey, secret, now); err != nil {
+		return nil, err
+	}
+
+	if isAPIKeyTouchRequired(apiKey, now) {
+		apiKey.LastUsedAt = now
+	}
+
+	return cloneAPIKey(apiKey), nil
+}
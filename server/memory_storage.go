@@ -0,0 +1,301 @@
+package server
+
+import (
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	"github.com/golang/protobuf/proto"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"sync"
+	"time"
+)
+
+// MemoryStorage - storage which keeps all objects in memory of process. It has the same semantics as mongo
+// storage, so the whole api can work without database in tests and development
+type MemoryStorage struct {
+	users             *memoryUserRepo
+	companies         *memoryCompanyRepo
+	entities          *memoryEntityRepo
+	sessions          *memorySessionRepo
+	loginAttempts     *memoryLoginAttemptRepo
+	apiKeys           *memoryAPIKeyRepo
+	audit             *memoryAuditRepo
+	identityProviders *memoryIdentityProviderRepo
+	entityImports     *memoryEntityImportRepo
+}
+
+// NewMemoryStorage - returns new empty instance of MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		users:             newMemoryUserRepo(),
+		companies:         new(memoryCompanyRepo),
+		entities:          new(memoryEntityRepo),
+		sessions:          new(memorySessionRepo),
+		loginAttempts:     newMemoryLoginAttemptRepo(),
+		apiKeys:           new(memoryAPIKeyRepo),
+		audit:             new(memoryAuditRepo),
+		identityProviders: newMemoryIdentityProviderRepo(),
+		entityImports:     new(memoryEntityImportRepo),
+	}
+}
+
+// Open - memory storage doesn't have connections, so all requests share the same session
+func (ms *MemoryStorage) Open() (StorageSession, error) {
+	return ms, nil
+}
+
+// Users - returns storage of users
+func (ms *MemoryStorage) Users() UserStorage {
+	return ms.users
+}
+
+// Companies - returns storage of companies
+func (ms *MemoryStorage) Companies() CompanyStorage {
+	return ms.companies
+}
+
+// Entities - returns storage of entities
+func (ms *MemoryStorage) Entities() EntityStorage {
+	return ms.entities
+}
+
+// Sessions - returns storage of login sessions
+func (ms *MemoryStorage) Sessions() SessionStorage {
+	return ms.sessions
+}
+
+// LoginAttempts - returns storage of failed login attempts
+func (ms *MemoryStorage) LoginAttempts() LoginAttemptStorage {
+	return ms.loginAttempts
+}
+
+// APIKeys - returns storage of api keys
+func (ms *MemoryStorage) APIKeys() APIKeyStorage {
+	return ms.apiKeys
+}
+
+// Audit - returns storage of audit trail
+func (ms *MemoryStorage) Audit() AuditStorage {
+	return ms.audit
+}
+
+// IdentityProviders - returns storage of identity providers
+func (ms *MemoryStorage) IdentityProviders() IdentityProviderStorage {
+	return ms.identityProviders
+}
+
+// EntityImports - returns storage of entity import jobs
+func (ms *MemoryStorage) EntityImports() EntityImportStorage {
+	return ms.entityImports
+}
+
+// Close - nothing to release
+func (ms *MemoryStorage) Close() {
+}
+
+// memoryCompanyRepo - companies in memory, deleted companies are kept disabled
+type memoryCompanyRepo struct {
+	mu        sync.Mutex
+	companies []*grpc_gateway_company.Company
+}
+
+func cloneCompany(company *grpc_gateway_company.Company) *grpc_gateway_company.Company {
+	return proto.Clone(company).(*grpc_gateway_company.Company)
+}
+
+func (mr *memoryCompanyRepo) CreateCompany(company *grpc_gateway_company.Company) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	company.Id = uuid.NewV4().String()
+	company.IsEnabled = true
+	mr.companies = append(mr.companies, cloneCompany(company))
+	return nil
+}
+
+func (mr *memoryCompanyRepo) GetCompanyByID(id string) (*grpc_gateway_company.Company, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, company := range mr.companies {
+		if company.Id == id && company.IsEnabled {
+			return cloneCompany(company), nil
+		}
+	}
+
+	return &grpc_gateway_company.Company{}, mgo.ErrNotFound
+}
+
+func (mr *memoryCompanyRepo) GetCompanies(page *Page) (*grpc_gateway_company.CompanyListResponse, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	companies := NewCompanyListResponse()
+	for _, company := range mr.companies {
+		if company.IsEnabled {
+			companies.Data = append(companies.Data, cloneCompany(company))
+		}
+	}
+
+	next, total, err := memoryPage(&companies.Data, page)
+	companies.NextCursor = next
+	companies.Total = int64(total)
+	return companies, err
+}
+
+func (mr *memoryCompanyRepo) DeleteCompanyByID(id string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, company := range mr.companies {
+		if company.Id == id {
+			company.IsEnabled = false
+			return nil
+		}
+	}
+
+	return mgo.ErrNotFound
+}
+
+func (mr *memoryCompanyRepo) ImportCompany(company *grpc_gateway_company.Company) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, stored := range mr.companies {
+		if stored.Id == company.Id {
+			return ErrDuplicateKey
+		}
+	}
+
+	mr.companies = append(mr.companies, cloneCompany(company))
+	return nil
+}
+
+func (mr *memoryCompanyRepo) RemoveCompany(id string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, company := range mr.companies {
+		if company.Id == id {
+			mr.companies = append(mr.companies[:i], mr.companies[i+1:]...)
+			return nil
+		}
+	}
+
+	return mgo.ErrNotFound
+}
+
+func (mr *memoryCompanyRepo) HasCompany(id string) (bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, stored := range mr.companies {
+		if stored.Id == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (mr *memoryCompanyRepo) UpdateCompany(company *grpc_gateway_company.Company) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, stored := range mr.companies {
+		if stored.Id == company.Id && stored.IsEnabled {
+			stored.Name = company.Name
+			return nil
+		}
+	}
+
+	return mgo.ErrNotFound
+}
+
+// memoryAuditRepo - audit trail in memory
+type memoryAuditRepo struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (mr *memoryAuditRepo) Record(record *AuditRecord) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	record.ID = uuid.NewV4().String()
+	record.CreatedAt = time.Now().Unix()
+	mr.records = append(mr.records, *record)
+	return nil
+}
+
+// memoryIdentityProviderRepo - identity providers by company id and pending sso logins by state
+type memoryIdentityProviderRepo struct {
+	mu        sync.Mutex
+	providers map[string]*grpc_gateway_company.IdentityProvider
+	states    map[string]SSOState
+}
+
+func newMemoryIdentityProviderRepo() *memoryIdentityProviderRepo {
+	return &memoryIdentityProviderRepo{
+		providers: map[string]*grpc_gateway_company.IdentityProvider{},
+		states:    map[string]SSOState{},
+	}
+}
+
+func (mr *memoryIdentityProviderRepo) SetIdentityProvider(idp *grpc_gateway_company.IdentityProvider) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	mr.providers[idp.CompanyId] = proto.Clone(idp).(*grpc_gateway_company.IdentityProvider)
+	return nil
+}
+
+func (mr *memoryIdentityProviderRepo) RemoveIdentityProvider(companyID string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	delete(mr.providers, companyID)
+	return nil
+}
+
+func (mr *memoryIdentityProviderRepo) GetIdentityProvider(companyID string) (*grpc_gateway_company.IdentityProvider, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	idp, ok := mr.providers[companyID]
+	if !ok {
+		return &grpc_gateway_company.IdentityProvider{}, mgo.ErrNotFound
+	}
+
+	return proto.Clone(idp).(*grpc_gateway_company.IdentityProvider), nil
+}
+
+func (mr *memoryIdentityProviderRepo) CreateSSOState(state, companyID, nonce string, ttl time.Duration) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.states[state]; ok {
+		return ErrDuplicateKey
+	}
+
+	mr.states[state] = SSOState{
+		State:     state,
+		CompanyID: companyID,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+	return nil
+}
+
+func (mr *memoryIdentityProviderRepo) ConsumeSSOState(state string) (*SSOState, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	ssoState, ok := mr.states[state]
+	delete(mr.states, state)
+
+	if !ok || ssoState.ExpiresAt <= time.Now().Unix() {
+		return nil, ErrInvalidSSOState
+	}
+
+	return &ssoState, nil
+}
@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+)
+
+type MemoryStorageTestSuite struct {
+	sess server.StorageSession
+}
+
+var _ = Suite(&MemoryStorageTestSuite{})
+
+func (mt *MemoryStorageTestSuite) SetUpTest(c *C) {
+	var err error
+	mt.sess, err = server.NewMemoryStorage().Open()
+	c.Assert(err, IsNil)
+}
+
+func (mt *MemoryStorageTestSuite) TestUniqueEmail(c *C) {
+	users := mt.sess.Users()
+
+	c.Assert(users.CreateUser(&grpc_gateway_user.User{Email: "first@test.com"}), IsNil)
+	c.Assert(users.CreateUser(&grpc_gateway_user.User{Email: "first@test.com"}), Equals, server.ErrDuplicateKey)
+
+	second := &grpc_gateway_user.User{Email: "second@test.com"}
+	c.Assert(users.CreateUser(second), IsNil)
+	c.Assert(users.EnableUserAndSetPasswordPhone(second.Id, "12345", "9999"), IsNil)
+
+	stored, err := users.GetUserByID(second.Id)
+	c.Assert(err, IsNil)
+
+	// email can't be changed to email of another user
+	_, err = users.UpdateUserByID(stored, &grpc_gateway_user.User{Id: second.Id, Email: "first@test.com"}, server.RoleViewer, "")
+	c.Assert(err, Equals, server.ErrDuplicateKey)
+
+	// returned users are copies
+	stored.Name = "changed"
+	stored, err = users.GetUserByID(second.Id)
+	c.Assert(err, IsNil)
+	c.Assert(stored.Name, Equals, "")
+}
+
+func (mt *MemoryStorageTestSuite) TestSoftDelete(c *C) {
+	users := mt.sess.Users()
+
+	user := &grpc_gateway_user.User{Email: "user@test.com", CompanyId: "c1"}
+	c.Assert(users.CreateUser(user), IsNil)
+	c.Assert(users.EnableUserAndSetPasswordPhone(user.Id, "12345", "9999"), IsNil)
+
+	_, err := users.LoginUser("user@test.com", "12345")
+	c.Assert(err, IsNil)
+
+	c.Assert(users.DeleteUserByID(user.Id), IsNil)
+	_, err = users.GetUserByID(user.Id)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	list, err := users.GetUsersByCompanyID("c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 0)
+
+	companies := mt.sess.Companies()
+	company := &grpc_gateway_company.Company{Name: "company"}
+	c.Assert(companies.CreateCompany(company), IsNil)
+	c.Assert(companies.DeleteCompanyByID(company.Id), IsNil)
+
+	_, err = companies.GetCompanyByID(company.Id)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+	c.Assert(companies.DeleteCompanyByID("unknown"), Equals, mgo.ErrNotFound)
+}
+
+func (mt *MemoryStorageTestSuite) TestEntityRevisions(c *C) {
+	entities := mt.sess.Entities()
+
+	_, err := entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", CommonName: "first", Latest: true})
+	c.Assert(err, IsNil)
+
+	updated, err := entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", CommonName: "second", Latest: true}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(updated.Rev, Equals, int64(1))
+
+	latest, err := entities.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.CommonName, Equals, "second")
+
+	revs, err := entities.GetEntityRevs("e1", "c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 2)
+	c.Assert(revs.Data[0].Rev, Equals, int64(1))
+	c.Assert(revs.Data[1].Latest, Equals, false)
+
+	// entities of another company aren't visible
+	_, err = entities.GetLatestEntity("e1", "c2")
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	_, err = entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c2", Latest: true}, 1)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	// update of stale revision is conflict
+	_, err = entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", CommonName: "stale"}, 0)
+	c.Assert(err, Equals, server.ErrEntityConflict)
+
+	// only one revision of entity is latest
+	_, err = entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", Rev: 5, Latest: true})
+	c.Assert(err, Equals, server.ErrDuplicateKey)
+
+	list, err := entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+}
+
+func (mt *MemoryStorageTestSuite) TestConcurrentEntityUpdates(c *C) {
+	checkConcurrentEntityUpdates(c, mt.sess.Entities())
+}
+
+func (mt *MemoryStorageTestSuite) TestEntityTrash(c *C) {
+	checkEntityTrash(c, mt.sess.Entities())
+}
+
+func (mt *MemoryStorageTestSuite) TestEntityPages(c *C) {
+	checkEntityPages(c, mt.sess.Entities())
+}
+
+func (mt *MemoryStorageTestSuite) TestEntitySearch(c *C) {
+	checkEntitySearch(c, mt.sess.Entities())
+}
+
+func (mt *MemoryStorageTestSuite) TestRemoveEntities(c *C) {
+	checkRemoveEntities(c, mt.sess.Entities())
+}
+
+func (mt *MemoryStorageTestSuite) TestStagedEntities(c *C) {
+	checkStagedEntities(c, mt.sess.Entities())
+}
@@ -0,0 +1,414 @@
+package server
+
+import (
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/satori/go.uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/mgo.v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryUserRepo - users in memory. Ids and emails are unique like in mongo collection
+type memoryUserRepo struct {
+	mu    sync.Mutex
+	users []*grpc_gateway_user.User
+
+	// smsAttempts - wrong attempts of issued sms-code by user id, user model doesn't have this field
+	smsAttempts map[string]int
+}
+
+func newMemoryUserRepo() *memoryUserRepo {
+	return &memoryUserRepo{smsAttempts: map[string]int{}}
+}
+
+func cloneUser(user *grpc_gateway_user.User) *grpc_gateway_user.User {
+	return proto.Clone(user).(*grpc_gateway_user.User)
+}
+
+// isActiveUser - user who is enabled and confirmed email
+func isActiveUser(user *grpc_gateway_user.User) bool {
+	return user.IsEnabled && user.IsConfirmed
+}
+
+// isPendingUser - invited user who didn't confirm email yet, the same as pendingUserQuery
+func isPendingUser(user *grpc_gateway_user.User) bool {
+	return user.IsEnabled && !user.IsConfirmed && !user.IsServiceAccount
+}
+
+// find - first user which matches filter, should be called under lock
+func (mr *memoryUserRepo) find(match func(*grpc_gateway_user.User) bool) *grpc_gateway_user.User {
+	for _, user := range mr.users {
+		if match(user) {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// findCopy - copy of first user which matches filter
+func (mr *memoryUserRepo) findCopy(match func(*grpc_gateway_user.User) bool) (*grpc_gateway_user.User, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if user := mr.find(match); user != nil {
+		return cloneUser(user), nil
+	}
+
+	return &grpc_gateway_user.User{}, mgo.ErrNotFound
+}
+
+// update - change first user which matches filter
+func (mr *memoryUserRepo) update(match func(*grpc_gateway_user.User) bool, change func(*grpc_gateway_user.User)) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	user := mr.find(match)
+	if user == nil {
+		return mgo.ErrNotFound
+	}
+
+	change(user)
+	return nil
+}
+
+// checkUnique - check that there is no other user with the same id or email, should be called under lock
+func (mr *memoryUserRepo) checkUnique(user *grpc_gateway_user.User, except *grpc_gateway_user.User) error {
+	for _, stored := range mr.users {
+		if stored != except && (stored.Id == user.Id || stored.Email == user.Email) {
+			return ErrDuplicateKey
+		}
+	}
+
+	return nil
+}
+
+func (mr *memoryUserRepo) insert(user *grpc_gateway_user.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if err := mr.checkUnique(user, nil); err != nil {
+		return err
+	}
+
+	mr.users = append(mr.users, cloneUser(user))
+	return nil
+}
+
+func byID(id string) func(*grpc_gateway_user.User) bool {
+	return func(user *grpc_gateway_user.User) bool {
+		return user.Id == id
+	}
+}
+
+func byEmail(email string) func(*grpc_gateway_user.User) bool {
+	return func(user *grpc_gateway_user.User) bool {
+		return user.Email == email
+	}
+}
+
+func (mr *memoryUserRepo) CreateUser(user *grpc_gateway_user.User) error {
+	prepareNewUser(user)
+	return mr.insert(user)
+}
+
+func (mr *memoryUserRepo) ImportUser(user *grpc_gateway_user.User) error {
+	return mr.insert(user)
+}
+
+func (mr *memoryUserRepo) RemoveCompanyUsers(companyID string) (int, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	users := []*grpc_gateway_user.User{}
+	for _, user := range mr.users {
+		if user.CompanyId != companyID {
+			users = append(users, user)
+		}
+	}
+
+	removed := len(mr.users) - len(users)
+	mr.users = users
+	return removed, nil
+}
+
+func (mr *memoryUserRepo) HasUser(id, email string) (bool, bool, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	idTaken, emailTaken := false, false
+	for _, stored := range mr.users {
+		idTaken = idTaken || stored.Id == id
+		emailTaken = emailTaken || stored.Email == email
+	}
+
+	return idTaken, emailTaken, nil
+}
+
+func (mr *memoryUserRepo) CreateServiceAccount(name, companyID, role string) (*grpc_gateway_user.User, error) {
+	user := newServiceAccount(name, companyID, role)
+	return user, mr.insert(user)
+}
+
+func (mr *memoryUserRepo) GetUserByID(id string) (*grpc_gateway_user.User, error) {
+	return mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return user.Id == id && isActiveUser(user)
+	})
+}
+
+func (mr *memoryUserRepo) GetUserByEmailCode(user *grpc_gateway_user.User) (*grpc_gateway_user.User, error) {
+	return mr.findCopy(func(stored *grpc_gateway_user.User) bool {
+		return stored.EmailCode == user.EmailCode
+	})
+}
+
+func (mr *memoryUserRepo) GetPendingUserByID(id string) (*grpc_gateway_user.User, error) {
+	return mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return user.Id == id && isPendingUser(user)
+	})
+}
+
+func (mr *memoryUserRepo) GetPendingUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return mr.listPage(func(user *grpc_gateway_user.User) bool {
+		return user.CompanyId == companyID && isPendingUser(user)
+	}, page)
+}
+
+func (mr *memoryUserRepo) RegenerateEmailCode(id string) (*grpc_gateway_user.User, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	user := mr.find(func(user *grpc_gateway_user.User) bool {
+		return user.Id == id && isPendingUser(user)
+	})
+	if user == nil {
+		return &grpc_gateway_user.User{}, mgo.ErrNotFound
+	}
+
+	user.EmailCode = uuid.NewV4().String()
+	user.EmailSentAt = &timestamp.Timestamp{Seconds: time.Now().Unix()}
+	return cloneUser(user), nil
+}
+
+func (mr *memoryUserRepo) SetSMSCode(userID, code string) error {
+	return mr.update(byID(userID), func(user *grpc_gateway_user.User) {
+		user.SmsCode = code
+		user.SmsSentAt = &timestamp.Timestamp{Seconds: time.Now().Unix()}
+		mr.smsAttempts[user.Id] = 0
+	})
+}
+
+func (mr *memoryUserRepo) RegisterSMSCodeFailure(email string, maxAttempts int) error {
+	return mr.update(byEmail(email), func(user *grpc_gateway_user.User) {
+		mr.smsAttempts[user.Id]++
+		if mr.smsAttempts[user.Id] >= maxAttempts {
+			user.SmsCode = ""
+			user.SmsSentAt = nil
+		}
+	})
+}
+
+func (mr *memoryUserRepo) SetTOTPPendingSecret(userID, secret string) error {
+	return mr.update(byID(userID), func(user *grpc_gateway_user.User) {
+		user.TotpPendingSecret = secret
+	})
+}
+
+func (mr *memoryUserRepo) EnableTOTP(userID, secret string, step int64) error {
+	match := func(user *grpc_gateway_user.User) bool {
+		return user.Id == userID && user.TotpPendingSecret == secret
+	}
+
+	return mr.update(match, func(user *grpc_gateway_user.User) {
+		user.TotpSecret = secret
+		user.TotpPendingSecret = ""
+		user.TotpEnabled = true
+		user.TotpLastStep = step
+	})
+}
+
+func (mr *memoryUserRepo) SetTOTPLastStep(userID string, step int64) error {
+	match := func(user *grpc_gateway_user.User) bool {
+		return user.Id == userID && user.TotpLastStep < step
+	}
+
+	return mr.update(match, func(user *grpc_gateway_user.User) {
+		user.TotpLastStep = step
+	})
+}
+
+func (mr *memoryUserRepo) SetPreferredFactor(userID, factor string) error {
+	return mr.update(byID(userID), func(user *grpc_gateway_user.User) {
+		user.PreferredFactor = factor
+	})
+}
+
+func (mr *memoryUserRepo) SetPasswordResetToken(email, token string) (*grpc_gateway_user.User, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	user := mr.find(func(user *grpc_gateway_user.User) bool {
+		return user.Email == email && isActiveUser(user) && !user.IsServiceAccount
+	})
+	if user == nil {
+		return &grpc_gateway_user.User{}, mgo.ErrNotFound
+	}
+
+	user.PasswordResetToken = hashSecretToken(token)
+	user.PasswordResetSentAt = &timestamp.Timestamp{Seconds: time.Now().Unix()}
+	return cloneUser(user), nil
+}
+
+func (mr *memoryUserRepo) GetUserByPasswordResetToken(token string) (*grpc_gateway_user.User, error) {
+	hash := hashSecretToken(token)
+	return mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return user.PasswordResetToken == hash && isActiveUser(user)
+	})
+}
+
+func (mr *memoryUserRepo) ResetPassword(userID, token, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	tokenHash := hashSecretToken(token)
+	match := func(user *grpc_gateway_user.User) bool {
+		return user.Id == userID && user.PasswordResetToken == tokenHash
+	}
+
+	err = mr.update(match, func(user *grpc_gateway_user.User) {
+		user.Password = string(hash)
+		user.PasswordResetToken = ""
+		user.PasswordResetSentAt = nil
+		user.SmsCode = ""
+		user.SmsSentAt = nil
+	})
+	if err == mgo.ErrNotFound {
+		return ErrPasswordResetExpired
+	}
+
+	return err
+}
+
+func (mr *memoryUserRepo) EnableUserAndSetPasswordPhone(userID, password, phone string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return mr.update(byID(userID), func(user *grpc_gateway_user.User) {
+		user.EmailCode = ""
+		user.SmsCode = ""
+		user.EmailSentAt = nil
+		user.SmsSentAt = nil
+		user.IsConfirmed = true
+		user.Password = string(hash)
+		user.Phone = phone
+	})
+}
+
+func (mr *memoryUserRepo) LoginUser(email, password string) (*grpc_gateway_user.User, error) {
+	user, err := mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return user.Email == email && isActiveUser(user) && !user.IsServiceAccount
+	})
+	if err != nil {
+		return user, err
+	}
+
+	return user, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+}
+
+func (mr *memoryUserRepo) GetSSOUser(email, companyID string) (*grpc_gateway_user.User, error) {
+	return mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return strings.EqualFold(user.Email, email) && user.CompanyId == companyID && isActiveUser(user) && !user.IsServiceAccount
+	})
+}
+
+func (mr *memoryUserRepo) GetUserBySMSCode(email, code string) (*grpc_gateway_user.User, error) {
+	return mr.findCopy(func(user *grpc_gateway_user.User) bool {
+		return user.Email == email && user.SmsCode == code
+	})
+}
+
+func (mr *memoryUserRepo) ConfirmSMSUser(email, code string) error {
+	match := func(user *grpc_gateway_user.User) bool {
+		return user.Email == email && user.SmsCode == code
+	}
+
+	return mr.update(match, func(user *grpc_gateway_user.User) {
+		user.SmsCode = ""
+		user.SmsSentAt = nil
+	})
+}
+
+func (mr *memoryUserRepo) DeleteUserByID(id string) error {
+	return mr.update(byID(id), func(user *grpc_gateway_user.User) {
+		user.IsEnabled = false
+	})
+}
+
+// list - copies of all users which match filter
+func (mr *memoryUserRepo) list(match func(*grpc_gateway_user.User) bool) *grpc_gateway_user.UserListResponse {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	users := NewUserListResponse()
+	for _, user := range mr.users {
+		if match(user) {
+			users.Data = append(users.Data, cloneUser(user))
+		}
+	}
+
+	return users
+}
+
+func (mr *memoryUserRepo) GetUsers(page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return mr.listPage(isActiveUser, page)
+}
+
+func (mr *memoryUserRepo) GetUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return mr.listPage(func(user *grpc_gateway_user.User) bool {
+		return user.IsEnabled && user.CompanyId == companyID
+	}, page)
+}
+
+// listPage - page of users which match filter
+func (mr *memoryUserRepo) listPage(match func(*grpc_gateway_user.User) bool, page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	users := mr.list(match)
+
+	next, total, err := memoryPage(&users.Data, page)
+	users.NextCursor = next
+	users.Total = int64(total)
+	return users, err
+}
+
+func (mr *memoryUserRepo) UpdateUserByID(oldUser, user *grpc_gateway_user.User, role, companyID string) (*grpc_gateway_user.User, error) {
+	if err := applyUserUpdate(oldUser, user, role, companyID); err != nil {
+		return oldUser, err
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for i, stored := range mr.users {
+		if stored.Id != user.Id {
+			continue
+		}
+
+		// stored user is replaced completely, like document in mongo
+		if err := mr.checkUnique(oldUser, stored); err != nil {
+			return oldUser, err
+		}
+
+		mr.users[i] = cloneUser(oldUser)
+		delete(mr.smsAttempts, stored.Id)
+		return oldUser, nil
+	}
+
+	return oldUser, mgo.ErrNotFound
+}
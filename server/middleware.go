@@ -1,22 +1,93 @@
 package server
 
 import (
-	"fmt"
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/golang/glog"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"net"
 	"net/http"
 	"path"
 	"strings"
 )
 
-var secretKey = []byte(`lc}m8K!0.-{3m8y%VJL!eFAc!S2:673<eN29Cod1c9q9dRH6Wa|&//l^6tt4hq3`)
+// ErrAuthenticationRequired - error when request doesn't have valid credentials
+var ErrAuthenticationRequired = errors.New("authentication required")
 
-func isPathRequriredAuthorization(info *grpc.UnaryServerInfo) bool {
-	return info.FullMethod != "/grpc.gateway.user.UserService/Login" &&
-		info.FullMethod != "/grpc.gateway.user.UserService/ConfirmEmail"
+// ErrImpersonationForbidden - error when action isn't allowed while admin acts as another user
+var ErrImpersonationForbidden = errors.New("this action isn't allowed while impersonating user")
+
+func isPathRequriredAuthorization(fullMethod string) bool {
+	policy, ok := GetAuthPolicy(fullMethod)
+	return !ok || policy.Kind != PolicyPublic
+}
+
+// authErrorStatus - http status of authorization error
+func authErrorStatus(err error) int32 {
+	if err == ErrPermissionDenied || err == ErrImpersonationForbidden {
+		return http.StatusForbidden
+	}
+
+	return http.StatusUnauthorized
+}
+
+// authErrorCode - grpc code of authorization error
+func authErrorCode(err error) codes.Code {
+	if authErrorStatus(err) == http.StatusForbidden {
+		return codes.PermissionDenied
+	}
+
+	return codes.Unauthenticated
+}
+
+// authorizeCall - authenticate caller of method, check access policy of method and put caller to context.
+// Request is nil for streaming calls, their requests are checked by authorizedStream when they are received
+func authorizeCall(ctx context.Context, fullMethod string, req interface{}) (context.Context, *grpc_gateway_user.User, error) {
+	// retrieve metadata from context
+	md, ok := metadata.FromContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return ctx, nil, ErrAuthenticationRequired
+	}
+
+	authorizationParts := strings.Split(md["authorization"][0], " ")
+	if len(authorizationParts) < 2 {
+		return ctx, nil, ErrAuthenticationRequired
+	}
+
+	// service accounts pass api key instead of bearer token
+	var user *grpc_gateway_user.User
+	var err error
+	if strings.EqualFold(authorizationParts[0], "ApiKey") {
+		ctx, user, err = authenticateAPIKey(ctx, authorizationParts[1])
+	} else {
+		ctx, user, err = authenticateToken(ctx, authorizationParts[1])
+	}
+
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	// methods without declared policy aren't available at all
+	policy, ok := GetAuthPolicy(fullMethod)
+	if !ok || (req != nil && !policy.Allows(user, req)) || (req == nil && !policy.AllowsRole(user)) {
+		return ctx, nil, ErrPermissionDenied
+	}
+
+	// every call under impersonation is audited, even if it's denied
+	if impersonatorID, _ := ctx.Value("impersonator_id").(string); impersonatorID != "" {
+		auditImpersonatedCall(ctx, fullMethod, user, impersonatorID, policy.DenyImpersonation)
+
+		if policy.DenyImpersonation {
+			return ctx, nil, ErrImpersonationForbidden
+		}
+	}
+
+	return ctx, user, nil
 }
 
 // AuthUnaryInterceptor - interceptor function
@@ -28,52 +99,264 @@ func AuthUnaryInterceptor(
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
 
-	if isPathRequriredAuthorization(info) {
-		errMessage := NewCommonResponse()
-		errMessage.Meta.Ok = false
-		errMessage.Meta.Error = "authentication required"
-		errMessage.Meta.StatusCode = http.StatusUnauthorized
-
-		// retrieve metadata from context
-		md, ok := metadata.FromContext(ctx)
-		if !ok {
+	if isPathRequriredAuthorization(info.FullMethod) {
+		var err error
+		ctx, _, err = authorizeCall(ctx, info.FullMethod, req)
+		if err != nil {
+			errMessage := NewCommonResponse()
+			errMessage.Meta.Ok = false
+			errMessage.Meta.Error = err.Error()
+			errMessage.Meta.StatusCode = authErrorStatus(err)
 			return errMessage, nil
 		}
+	}
 
-		if _, ok := md["authorization"]; !ok || len(md["authorization"]) < 0 {
-			return errMessage, nil
+	return handler(ctx, req)
+}
+
+// AuthStreamInterceptor - interceptor function for streaming calls, which uses the same policies as
+// AuthUnaryInterceptor. Streams don't have response with meta, so errors are returned as grpc statuses
+// https://godoc.org/google.golang.org/grpc#StreamServerInterceptor
+func AuthStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+
+	if isPathRequriredAuthorization(info.FullMethod) {
+		ctx, user, err := authorizeCall(ss.Context(), info.FullMethod, nil)
+		if err != nil {
+			return grpc.Errorf(authErrorCode(err), "%s", err)
 		}
 
-		authorizationParts := strings.Split(md["authorization"][0], " ")
-		if len(authorizationParts) < 2 {
-			return errMessage, nil
+		policy, _ := GetAuthPolicy(info.FullMethod)
+		ss = &authorizedStream{ServerStream: ss, ctx: ctx, user: user, policy: policy}
+	}
+
+	return handler(srv, ss)
+}
+
+// authorizedStream - stream of authorized call, which checks company of every received request
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	user   *grpc_gateway_user.User
+	policy AuthPolicy
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if !s.policy.Allows(s.user, m) {
+		return grpc.Errorf(codes.PermissionDenied, "%s", ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+// authenticateToken - check access token and put user and session of token to context
+func authenticateToken(ctx context.Context, tokenString string) (context.Context, *grpc_gateway_user.User, error) {
+	token, err := GetJWTKeySet().ParseToken(tokenString)
+	if err != nil {
+		return ctx, nil, ErrAuthenticationRequired
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return ctx, nil, ErrAuthenticationRequired
+	}
+
+	userID, _ := claims["user_id"].(string)
+	sessionID, _ := claims["sid"].(string)
+	impersonatorID, _ := claims["impersonator_id"].(string)
+
+	// token is valid only while its session is active and user is enabled
+	user, err := checkSession(userID, sessionID, impersonatorID)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	ctx = context.WithValue(ctx, "user_id", userID)
+	ctx = context.WithValue(ctx, "session_id", sessionID)
+	ctx = context.WithValue(ctx, "impersonator_id", impersonatorID)
+	return ctx, user, nil
+}
+
+// authenticateAPIKey - check api key and put its service account to context
+func authenticateAPIKey(ctx context.Context, key string) (context.Context, *grpc_gateway_user.User, error) {
+	sess, err := storageInstance.Open()
+	if err != nil {
+		return ctx, nil, err
+	}
+	defer sess.Close()
+
+	apiKey, err := sess.APIKeys().Authenticate(key)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	user, err := sess.Users().GetUserByID(apiKey.ServiceAccountId)
+	if err != nil {
+		return ctx, nil, ErrInvalidAPIKey
+	}
+
+	// keys of disabled company are rejected even if they weren't revoked with its sessions
+	if user.CompanyId != "" {
+		if _, err := sess.Companies().GetCompanyByID(user.CompanyId); err != nil {
+			return ctx, nil, ErrInvalidAPIKey
 		}
+	}
 
-		tokenString := authorizationParts[1]
+	ctx = context.WithValue(ctx, "user_id", user.Id)
+	ctx = context.WithValue(ctx, "session_id", "")
+	ctx = context.WithValue(ctx, "api_key_id", apiKey.Id)
+	return ctx, user, nil
+}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Don't forget to validate the alg is what you expect:
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				errMessage.Meta.Error = fmt.Sprintf("Unexpected signing method: %v", token.Header["alg"])
-				return errMessage, nil
-			}
+func checkSession(userID, sessionID, impersonatorID string) (*grpc_gateway_user.User, error) {
+	sess, err := storageInstance.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
 
-			return secretKey, nil
-		})
+	sessionRepo := sess.Sessions()
+	session, err := sessionRepo.GetActiveSession(sessionID, userID)
+	if err != nil || session.ImpersonatorId != impersonatorID {
+		return nil, ErrSessionRevoked
+	}
 
-		if err != nil {
-			return errMessage, nil
+	userRepo := sess.Users()
+
+	// impersonation ends as soon as impersonator loses admin rights
+	if impersonatorID != "" {
+		impersonator, err := userRepo.GetUserByID(impersonatorID)
+		if err != nil || !impersonator.IsEnabled || !IsPlatformAdmin(impersonator) {
+			return nil, ErrSessionRevoked
 		}
+	}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userID := claims["user_id"].(string)
-			ctx = context.WithValue(ctx, "user_id", userID)
-		} else {
-			return errMessage, nil
+	if err := sessionRepo.TouchSession(session); err != nil {
+		glog.Error(err)
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrSessionRevoked
+	}
+
+	return user, nil
+}
+
+// auditImpersonatedCall - write call made by admin as another user to log and audit trail
+func auditImpersonatedCall(ctx context.Context, fullMethod string, user *grpc_gateway_user.User, impersonatorID string, denied bool) {
+	glog.Infof("IMPERSONATION: admin %v calls %v as user %v (denied: %v)", impersonatorID, fullMethod, user.Id, denied)
+
+	sess, err := storageInstance.Open()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	defer sess.Close()
+
+	details := ""
+	if denied {
+		details = ErrImpersonationForbidden.Error()
+	}
+
+	err = sess.Audit().Record(&AuditRecord{
+		Action:         fullMethod,
+		UserID:         user.Id,
+		CompanyID:      user.CompanyId,
+		ImpersonatorID: impersonatorID,
+		Address:        ClientAddress(ctx),
+		Details:        details,
+	})
+	if err != nil {
+		glog.Error(err)
+	}
+}
+
+// trustedProxiesInstance - number of reverse proxies in front of http listener, see Config.TrustedProxies
+var trustedProxiesInstance int
+
+// forwardedClient - address of client in chain of x-forwarded-for addresses where the last address is peer
+// of our listener. Every proxy appends address of its peer, so only addresses appended by trusted proxies
+// are used and addresses which were sent by client itself are ignored
+func forwardedClient(chain []string, trustedProxies int) string {
+	addrs := []string{}
+	for _, addr := range chain {
+		for _, part := range strings.Split(addr, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				addrs = append(addrs, part)
+			}
 		}
 	}
 
-	return handler(ctx, req)
+	if len(addrs) == 0 {
+		return ""
+	}
+
+	index := len(addrs) - 1 - trustedProxies
+	if index < 0 {
+		index = 0
+	}
+
+	return addrs[index]
+}
+
+// peerHost - host of network address without port
+func peerHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
+
+// ClientAddress - return address of client. Gateway calls grpc listener through loopback and appends
+// address of http client to x-forwarded-for, metadata of other grpc clients isn't trusted
+func ClientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host := peerHost(p.Addr.String())
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	if md, ok := metadata.FromContext(ctx); ok && len(md["x-forwarded-for"]) > 0 {
+		return forwardedClient(md["x-forwarded-for"], trustedProxiesInstance)
+	}
+
+	return host
+}
+
+// clientUserAgent - return user agent of client. Gateway passes user agent of http client with prefix
+func clientUserAgent(ctx context.Context) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if len(md["grpcgateway-user-agent"]) > 0 {
+		return md["grpcgateway-user-agent"][0]
+	}
+
+	if len(md["user-agent"]) > 0 {
+		return md["user-agent"][0]
+	}
+
+	return ""
 }
 
 func serveSwagger(w http.ResponseWriter, r *http.Request) {
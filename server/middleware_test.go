@@ -0,0 +1,138 @@
+package server_test
+
+import (
+	"fmt"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	. "gopkg.in/check.v1"
+	"net"
+	"net/http"
+	"time"
+)
+
+// testServerStream - server stream without transport
+type testServerStream struct {
+	ctx context.Context
+}
+
+func (s *testServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *testServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *testServerStream) SetTrailer(metadata.MD)       {}
+func (s *testServerStream) Context() context.Context     { return s.ctx }
+func (s *testServerStream) SendMsg(m interface{}) error  { return nil }
+func (s *testServerStream) RecvMsg(m interface{}) error  { return nil }
+
+// forwardedContext - context of call from peer with x-forwarded-for metadata
+func forwardedContext(peerAddr string, forwarded string) context.Context {
+	addr, _ := net.ResolveTCPAddr("tcp", peerAddr)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	return metadata.NewContext(ctx, metadata.Pairs("x-forwarded-for", forwarded))
+}
+
+type MiddlewareTestSuite struct{}
+
+var _ = Suite(&MiddlewareTestSuite{})
+
+func (mt *MiddlewareTestSuite) TestUnaryInterceptorRequiresCredentials(c *C) {
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	// public methods don't require credentials
+	_, err := server.AuthUnaryInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.gateway.user.UserService/Login"}, handler)
+	c.Assert(err, IsNil)
+	c.Assert(called, Equals, true)
+
+	called = false
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.NewContext(context.Background(), metadata.Pairs("authorization", "Bearer")),
+	} {
+		resp, err := server.AuthUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.gateway.user.UserService/GetUser"}, handler)
+		c.Assert(err, IsNil)
+		c.Assert(called, Equals, false)
+
+		message, ok := resp.(*grpc_gateway_common.CommonResponse)
+		c.Assert(ok, Equals, true)
+		c.Assert(message.Meta.StatusCode, Equals, int32(http.StatusUnauthorized))
+		c.Assert(message.Meta.Error, Equals, server.ErrAuthenticationRequired.Error())
+	}
+}
+
+func (mt *MiddlewareTestSuite) TestStreamInterceptorRequiresCredentials(c *C) {
+	called := false
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	}
+
+	// the same policies are used for streaming calls
+	stream := &testServerStream{ctx: context.Background()}
+	err := server.AuthStreamInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/grpc.gateway.user.UserService/Login"}, handler)
+	c.Assert(err, IsNil)
+	c.Assert(called, Equals, true)
+
+	called = false
+	for _, method := range []string{"/grpc.gateway.entity.EntityService/GetEntities", "/grpc.gateway.entity.EntityService/Unknown"} {
+		err = server.AuthStreamInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}, handler)
+		c.Assert(grpc.Code(err), Equals, codes.Unauthenticated)
+		c.Assert(called, Equals, false)
+	}
+
+	stream = &testServerStream{ctx: metadata.NewContext(context.Background(), metadata.Pairs("authorization", "Bearer"))}
+	err = server.AuthStreamInterceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/grpc.gateway.entity.EntityService/GetEntities"}, handler)
+	c.Assert(grpc.Code(err), Equals, codes.Unauthenticated)
+	c.Assert(called, Equals, false)
+}
+
+func (mt *MiddlewareTestSuite) TestClientAddress(c *C) {
+	// gateway appends address of http client to the end of chain
+	c.Assert(server.ClientAddress(forwardedContext("127.0.0.1:50000", "10.0.0.7")), Equals, "10.0.0.7")
+	c.Assert(server.ClientAddress(forwardedContext("127.0.0.1:50000", "1.2.3.4, 10.0.0.7")), Equals, "10.0.0.7")
+
+	// metadata of direct grpc clients isn't trusted
+	c.Assert(server.ClientAddress(forwardedContext("192.168.1.5:50000", "1.2.3.4")), Equals, "192.168.1.5")
+
+	c.Assert(server.ClientAddress(context.Background()), Equals, "")
+}
+
+func (mt *MiddlewareTestSuite) TestForgedForwardedForIsLockedOut(c *C) {
+	users := server.NewUserServer(&server.Config{
+		LoginMaxFailures:        100,
+		LoginMaxAddressFailures: 3,
+		LoginLockoutBase:        time.Minute,
+		LoginLockoutMax:         time.Hour,
+		LoginFailuresWindow:     time.Hour,
+	}, server.NewMemoryStorage())
+
+	// every attempt forges another address and account, but all of them come from the same client
+	for i := 0; i < 3; i++ {
+		ctx := forwardedContext("127.0.0.1:50000", fmt.Sprintf("1.2.3.%d, 10.0.0.8", i))
+		login, err := users.Login(ctx, &grpc_gateway_user.LoginRequest{Email: fmt.Sprintf("forged_%d@test.com", i), Password: "wrong"})
+		c.Assert(err, IsNil)
+		c.Assert(login.Meta.Ok, Equals, false)
+
+		if i < 2 {
+			c.Assert(login.Meta.StatusCode, Not(Equals), int32(http.StatusTooManyRequests))
+		} else {
+			c.Assert(login.Meta.StatusCode, Equals, int32(http.StatusTooManyRequests))
+		}
+	}
+
+	login, err := users.Login(forwardedContext("127.0.0.1:50000", "9.9.9.9, 10.0.0.8"), &grpc_gateway_user.LoginRequest{Email: "forged@test.com", Password: "wrong"})
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Equals, int32(http.StatusTooManyRequests))
+
+	// other client isn't locked
+	login, err = users.Login(forwardedContext("127.0.0.1:50000", "10.0.0.9"), &grpc_gateway_user.LoginRequest{Email: "forged@test.com", Password: "wrong"})
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Not(Equals), int32(http.StatusTooManyRequests))
+}
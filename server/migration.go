@@ -0,0 +1,597 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"sort"
+	"time"
+)
+
+// ErrMigrationLocked - error when another runner applies migrations for too long
+var ErrMigrationLocked = errors.New("migrations are being applied by another runner")
+
+// migrationLockTTL - lock of runner expires after this time, so crashed runner doesn't block migrations forever
+const migrationLockTTL = time.Minute * 10
+
+// migrationLockWait - runner waits for lock of another runner not longer than this
+const migrationLockWait = time.Minute
+
+// Migration - versioned change of indexes or stored documents. Migrate returns number of changed documents,
+// in dry run it doesn't change anything and returns number of documents which would be changed
+type Migration struct {
+	Version     int
+	Description string
+	Migrate     func(sess *mgo.Database, dryRun bool) (int, error)
+}
+
+// AppliedMigration - record about applied migration
+type AppliedMigration struct {
+	Version     int
+	Description string
+	AppliedAt   int64
+	Changed     int
+}
+
+// MigrationResult - result of migration which was applied or checked in dry run
+type MigrationResult struct {
+	Version     int
+	Description string
+	Changed     int
+	DryRun      bool
+}
+
+// MigrationLock - lock which allows only one runner to apply migrations
+type MigrationLock struct {
+	Name        string
+	Owner       string
+	LockedUntil time.Time
+}
+
+// Migrations - all migrations of database, versions are never reused or changed after release. Migrations
+// don't use indexes of repositories, so every migration keeps indexes which it created on release
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "create indexes of users, sessions, login attempts, api keys, identity providers and audit log",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			indexes := map[string][]mgo.Index{
+				"users": {
+					{Key: []string{"email"}, Unique: true},
+					{Key: []string{"id"}, Unique: true},
+				},
+				"sessions": {
+					{Key: []string{"id"}, Unique: true},
+					{Key: []string{"refreshtoken"}},
+					{Key: []string{"userid"}},
+				},
+				"login_attempts": {
+					{Key: []string{"key"}, Unique: true},
+				},
+				"api_keys": {
+					{Key: []string{"id"}, Unique: true},
+					{Key: []string{"serviceaccountid"}},
+				},
+				"identity_providers": {
+					{Key: []string{"companyid"}, Unique: true},
+				},
+				"sso_states": {
+					{Key: []string{"state"}, Unique: true},
+				},
+				"audit_log": {
+					{Key: []string{"userid", "-createdat"}},
+					{Key: []string{"impersonatorid", "-createdat"}},
+				},
+			}
+
+			for coll, collIndexes := range indexes {
+				if err := ensureIndexes(sess, coll, collIndexes...); err != nil {
+					return 0, err
+				}
+			}
+
+			return 0, nil
+		},
+	},
+	{
+		Version:     2,
+		Description: "create indexes of companies",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			return 0, ensureIndexes(sess, "companies", mgo.Index{Key: []string{"id"}, Unique: true})
+		},
+	},
+	{
+		Version:     3,
+		Description: "set role of users created before roles",
+		Migrate:     migrateUserRoles,
+	},
+	{
+		Version:     4,
+		Description: "replace entity indexes with indexes of revisions",
+		Migrate:     migrateEntityIndexes,
+	},
+	{
+		Version:     5,
+		Description: "create index of deleted entities",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			return 0, ensureIndexes(sess, "entities", mgo.Index{Key: []string{"latest", "isdeleted", "deletedat"}})
+		},
+	},
+	{
+		Version:     6,
+		Description: "create indexes of sorted entity pages",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			return 0, ensureIndexes(sess, "entities",
+				mgo.Index{Key: []string{"companyid", "latest", "commonname", "id"}},
+				mgo.Index{Key: []string{"companyid", "latest", "createdat", "id"}},
+			)
+		},
+	},
+	{
+		Version:     7,
+		Description: "store search fields of entities and create search indexes",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			changed, err := NewEntityRepo(sess).BackfillSearchFields("searchname", dryRun)
+			if err != nil || dryRun {
+				return changed, err
+			}
+
+			return changed, ensureIndexes(sess, "entities",
+				mgo.Index{Key: []string{"companyid", "latest", "searchname"}},
+				mgo.Index{Key: []string{"companyid", "latest", "searchcountries"}},
+				mgo.Index{Key: []string{"companyid", "latest", "searchnationality"}},
+				mgo.Index{Key: []string{"companyid", "latest", "searchlegalform"}},
+				mgo.Index{Key: []string{"companyid", "kvk"}},
+				mgo.Index{Key: []string{"companyid", "rsin"}},
+				mgo.Index{Key: []string{"companyid", "bfinumber"}},
+			)
+		},
+	},
+	{
+		Version:     8,
+		Description: "store blind indexes of encrypted entity fields",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			changed, err := NewEntityRepo(sess).BackfillSearchFields("bfinumberindex", dryRun)
+			if err != nil || dryRun {
+				return changed, err
+			}
+
+			// encrypted bfi number is found only by its blind index
+			if err := dropIndexes(sess, "entities", "companyid_1_bfinumber_1"); err != nil {
+				return changed, err
+			}
+
+			return changed, ensureIndexes(sess, "entities", mgo.Index{Key: []string{"companyid", "bfinumberindex"}})
+		},
+	},
+	{
+		Version:     9,
+		Description: "create indexes of entity imports",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			return 0, ensureIndexes(sess, "entity_imports", mgo.Index{Key: []string{"id"}, Unique: true})
+		},
+	},
+	{
+		Version:     10,
+		Description: "drop unique index of latest entity revisions",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			return 0, dropIndexes(sess, "entities", "id_latest")
+		},
+	},
+	{
+		Version:     11,
+		Description: "create indexes of staged entities and stale entity imports",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 0, nil
+			}
+
+			// only staged revisions of imports have import id
+			if err := ensureIndexes(sess, "entities", mgo.Index{Key: []string{"importid"}, Sparse: true}); err != nil {
+				return 0, err
+			}
+
+			return 0, ensureIndexes(sess, "entity_imports", mgo.Index{Key: []string{"status", "updatedat"}})
+		},
+	},
+	{
+		Version:     12,
+		Description: "keep the newest of latest entity revisions and create unique index of latest revisions",
+		Migrate:     migrateLatestEntityIndex,
+	},
+}
+
+// ensureIndexes - create indexes of collection, existing indexes aren't changed
+func ensureIndexes(sess *mgo.Database, coll string, indexes ...mgo.Index) error {
+	c := sess.C(coll)
+	for _, index := range indexes {
+		if err := c.EnsureIndex(index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropIndexes - drop indexes of collection by names, indexes which don't exist are skipped
+func dropIndexes(sess *mgo.Database, coll string, names ...string) error {
+	c := sess.C(coll)
+
+	indexes, err := c.Indexes()
+	if err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		for _, name := range names {
+			if index.Name == name {
+				if err := c.DropIndexName(name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureUniquePartialIndex - create unique index of documents which match filter. mgo doesn't support
+// partial indexes, so index is created by command
+func ensureUniquePartialIndex(sess *mgo.Database, coll, name string, key bson.D, filter bson.M) error {
+	return sess.Run(bson.D{
+		{Name: "createIndexes", Value: coll},
+		{Name: "indexes", Value: []bson.M{{
+			"key":                     key,
+			"name":                    name,
+			"unique":                  true,
+			"partialFilterExpression": filter,
+		}}},
+	}, nil)
+}
+
+// migrateUserRoles - store role which UserRole computes for users without role
+func migrateUserRoles(sess *mgo.Database, dryRun bool) (int, error) {
+	c := sess.C("users")
+	changed := 0
+
+	for isAdmin, role := range map[bool]string{true: RolePlatformAdmin, false: RoleEditor} {
+		query := bson.M{
+			"isadmin": isAdmin,
+			"$or":     []bson.M{{"role": ""}, {"role": bson.M{"$exists": false}}},
+		}
+
+		if dryRun {
+			n, err := c.Find(query).Count()
+			if err != nil {
+				return changed, err
+			}
+
+			changed += n
+			continue
+		}
+
+		info, err := c.UpdateAll(query, bson.M{"$set": bson.M{"role": role}})
+		if err != nil {
+			return changed, err
+		}
+
+		changed += info.Updated
+	}
+
+	return changed, nil
+}
+
+// migrateEntityIndexes - check that stored entities can be indexed by revisions and create indexes
+func migrateEntityIndexes(sess *mgo.Database, dryRun bool) (int, error) {
+	repo := NewEntityRepo(sess)
+
+	violations, err := repo.CheckInvariants()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(violations) > 0 {
+		for _, violation := range violations {
+			glog.Errorf("Entity %s: %s (rev %d, count %d)", violation.EntityID, violation.Problem, violation.Rev, violation.Count)
+		}
+
+		return 0, ErrEntityInvariantsViolated
+	}
+
+	if dryRun {
+		return 0, nil
+	}
+
+	// indexes of first versions made revisions of one entity impossible
+	if err := dropIndexes(sess, "entities", "id_1", "rev_1"); err != nil {
+		return 0, err
+	}
+
+	err = ensureIndexes(sess, "entities",
+		mgo.Index{Key: []string{"id", "rev"}, Unique: true},
+		mgo.Index{Key: []string{"companyid", "latest", "type"}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// only one revision of entity is latest
+	return 0, ensureUniquePartialIndex(sess, "entities", "id_latest", bson.D{{Name: "id", Value: 1}}, bson.M{"latest": true})
+}
+
+// migrateLatestEntityIndex - revisions before the newest latest revision of entity stop being latest, then
+// index makes second latest revision of entity impossible. Returns number of entities which had many latest revisions
+func migrateLatestEntityIndex(sess *mgo.Database, dryRun bool) (int, error) {
+	c := sess.C("entities")
+
+	duplicates := []struct {
+		ID struct {
+			CompanyID string `bson:"companyid"`
+			ID        string `bson:"id"`
+		} `bson:"_id"`
+		Rev int64 `bson:"rev"`
+	}{}
+	err := c.Pipe([]bson.M{
+		{"$match": bson.M{"latest": true}},
+		{"$group": bson.M{
+			"_id":   bson.M{"companyid": "$companyid", "id": "$id"},
+			"rev":   bson.M{"$max": "$rev"},
+			"count": bson.M{"$sum": 1},
+		}},
+		{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	}).AllowDiskUse().All(&duplicates)
+	if err != nil || dryRun {
+		return len(duplicates), err
+	}
+
+	for _, duplicate := range duplicates {
+		glog.Warningf("Entity %s has many latest revisions, rev %d is kept", duplicate.ID.ID, duplicate.Rev)
+
+		_, err := c.UpdateAll(
+			bson.M{"companyid": duplicate.ID.CompanyID, "id": duplicate.ID.ID, "latest": true, "rev": bson.M{"$lt": duplicate.Rev}},
+			bson.M{"$set": bson.M{"latest": false}},
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	key := bson.D{{Name: "companyid", Value: 1}, {Name: "id", Value: 1}}
+	return len(duplicates), ensureUniquePartialIndex(sess, "entities", "companyid_id_latest", key, bson.M{"latest": true})
+}
+
+// CheckEntities - connect to database from config and find entities which violate invariants of revisions
+func CheckEntities(cfg *Config) ([]*EntityViolation, error) {
+	pool, err := NewConnectionPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	sess, err := pool.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Session.Close()
+
+	return NewEntityRepo(sess).CheckInvariants()
+}
+
+// ResealDocuments - connect to database from config and seal personal data by active encryption key
+func ResealDocuments(cfg *Config) (int, error) {
+	pool, err := NewConnectionPool(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer pool.Close()
+
+	return NewMongoStorage(pool).Reseal()
+}
+
+// ValidateMigrations - check that versions of migrations are positive and unique
+func ValidateMigrations(migrations []Migration) error {
+	versions := map[int]bool{}
+	for _, migration := range migrations {
+		if migration.Version <= 0 || versions[migration.Version] {
+			return fmt.Errorf("migration version %d is invalid or duplicated", migration.Version)
+		}
+
+		versions[migration.Version] = true
+	}
+
+	return nil
+}
+
+// MigrationRunner - applies migrations which weren't applied yet and records their versions
+type MigrationRunner struct {
+	sess       *mgo.Database
+	coll       string
+	locksColl  string
+	migrations []Migration
+}
+
+// NewMigrationRunner - returns new instance of MigrationRunner, migrations are applied in order of versions
+func NewMigrationRunner(sess *mgo.Database, migrations []Migration) (*MigrationRunner, error) {
+	if err := ValidateMigrations(migrations); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return &MigrationRunner{
+		sess:       sess,
+		coll:       "schema_migrations",
+		locksColl:  "schema_migrations_lock",
+		migrations: sorted,
+	}, nil
+}
+
+// GetAppliedMigrations - get records about applied migrations
+func (mr *MigrationRunner) GetAppliedMigrations() ([]*AppliedMigration, error) {
+	c := mr.sess.C(mr.coll)
+	applied := []*AppliedMigration{}
+
+	err := c.Find(nil).Sort("version").All(&applied)
+	return applied, err
+}
+
+// Run - apply pending migrations. Dry run doesn't change database and doesn't need lock
+func (mr *MigrationRunner) Run(dryRun bool) ([]*MigrationResult, error) {
+	if !dryRun {
+		owner, err := mr.lock()
+		if err != nil {
+			return nil, err
+		}
+		defer mr.unlock(owner)
+	}
+
+	// applied versions are read under lock, so migrations of another runner aren't applied again
+	applied, err := mr.GetAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedVersions := map[int]bool{}
+	for _, migration := range applied {
+		appliedVersions[migration.Version] = true
+	}
+
+	results := []*MigrationResult{}
+	for _, migration := range mr.migrations {
+		if appliedVersions[migration.Version] {
+			continue
+		}
+
+		changed, err := migration.Migrate(mr.sess, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("migration %d failed: %v", migration.Version, err)
+		}
+
+		results = append(results, &MigrationResult{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Changed:     changed,
+			DryRun:      dryRun,
+		})
+
+		if dryRun {
+			continue
+		}
+
+		err = mr.sess.C(mr.coll).Insert(&AppliedMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now().Unix(),
+			Changed:     changed,
+		})
+		if err != nil {
+			return results, err
+		}
+
+		glog.Infof("Migration %d applied: %s (%d documents changed)", migration.Version, migration.Description, changed)
+	}
+
+	return results, nil
+}
+
+// lock - take lock of migrations, waits while lock is held by another runner. Returns owner of lock
+func (mr *MigrationRunner) lock() (string, error) {
+	c := mr.sess.C(mr.locksColl)
+
+	// unique name makes concurrent upserts of the same lock fail
+	if err := c.EnsureIndex(mgo.Index{Key: []string{"name"}, Unique: true}); err != nil {
+		return "", err
+	}
+
+	if err := mr.sess.C(mr.coll).EnsureIndex(mgo.Index{Key: []string{"version"}, Unique: true}); err != nil {
+		return "", err
+	}
+
+	owner := uuid.NewV4().String()
+	deadline := time.Now().Add(migrationLockWait)
+
+	for {
+		now := time.Now()
+		change := mgo.Change{
+			Update: bson.M{"$set": bson.M{"owner": owner, "lockeduntil": now.Add(migrationLockTTL)}},
+			Upsert: true,
+		}
+
+		// lock is taken if it doesn't exist or is expired
+		_, err := c.Find(bson.M{"name": "migrations", "lockeduntil": bson.M{"$lt": now}}).Apply(change, nil)
+		if err == nil {
+			return owner, nil
+		}
+
+		if !mgo.IsDup(err) {
+			return "", err
+		}
+
+		if now.After(deadline) {
+			return "", ErrMigrationLocked
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// unlock - release lock of migrations if it's still held by owner
+func (mr *MigrationRunner) unlock(owner string) {
+	c := mr.sess.C(mr.locksColl)
+	if err := c.Remove(bson.M{"name": "migrations", "owner": owner}); err != nil {
+		glog.Error(err)
+	}
+}
+
+// RunMigrations - connect to database from config and apply pending migrations
+func RunMigrations(cfg *Config, dryRun bool) ([]*MigrationResult, error) {
+	pool, err := NewConnectionPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+
+	sess, err := pool.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Session.Close()
+
+	runner, err := NewMigrationRunner(sess, Migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.Run(dryRun)
+}
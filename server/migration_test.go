@@ -0,0 +1,279 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"sync"
+)
+
+type MigrationTestSuite struct {
+	sess *mgo.Database
+}
+
+var _ = Suite(&MigrationTestSuite{})
+
+func (mt *MigrationTestSuite) TearDownTest(c *C) {
+	if mt.sess != nil {
+		mt.sess.DropDatabase()
+		mt.sess.Session.Close()
+		mt.sess = nil
+	}
+}
+
+// countingMigration - migration which counts its applications
+func countingMigration(version int, applied *[]int, mu *sync.Mutex) server.Migration {
+	return server.Migration{
+		Version:     version,
+		Description: "test",
+		Migrate: func(sess *mgo.Database, dryRun bool) (int, error) {
+			if dryRun {
+				return 1, nil
+			}
+
+			mu.Lock()
+			*applied = append(*applied, version)
+			mu.Unlock()
+			return 1, sess.C("test").Insert(bson.M{"version": version})
+		},
+	}
+}
+
+func (mt *MigrationTestSuite) TestValidateMigrations(c *C) {
+	c.Assert(server.ValidateMigrations(server.Migrations), IsNil)
+	c.Assert(server.ValidateMigrations([]server.Migration{{Version: 1}, {Version: 1}}), NotNil)
+	c.Assert(server.ValidateMigrations([]server.Migration{{Version: 0}}), NotNil)
+
+	_, err := server.NewMigrationRunner(nil, []server.Migration{{Version: 2}, {Version: 2}})
+	c.Assert(err, NotNil)
+}
+
+func (mt *MigrationTestSuite) TestRunInOrder(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	applied := []int{}
+	mu := &sync.Mutex{}
+	runner, err := server.NewMigrationRunner(mt.sess, []server.Migration{
+		countingMigration(2, &applied, mu),
+		countingMigration(1, &applied, mu),
+	})
+	c.Assert(err, IsNil)
+
+	// dry run doesn't change database
+	results, err := runner.Run(true)
+	c.Assert(err, IsNil)
+	c.Assert(results, HasLen, 2)
+	c.Assert(results[0].DryRun, Equals, true)
+	c.Assert(applied, HasLen, 0)
+
+	records, err := runner.GetAppliedMigrations()
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 0)
+
+	results, err = runner.Run(false)
+	c.Assert(err, IsNil)
+	c.Assert(results, HasLen, 2)
+	c.Assert(applied, DeepEquals, []int{1, 2})
+
+	records, err = runner.GetAppliedMigrations()
+	c.Assert(err, IsNil)
+	c.Assert(records, HasLen, 2)
+	c.Assert(records[1].Version, Equals, 2)
+	c.Assert(records[1].Changed, Equals, 1)
+
+	// applied migrations aren't applied again
+	results, err = runner.Run(false)
+	c.Assert(err, IsNil)
+	c.Assert(results, HasLen, 0)
+	c.Assert(applied, HasLen, 2)
+}
+
+func (mt *MigrationTestSuite) TestConcurrentRunners(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	applied := []int{}
+	mu := &sync.Mutex{}
+	migrations := []server.Migration{
+		countingMigration(1, &applied, mu),
+		countingMigration(2, &applied, mu),
+	}
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sess := mt.sess.Session.Copy()
+			defer sess.Close()
+
+			runner, err := server.NewMigrationRunner(sess.DB(mt.sess.Name), migrations)
+			if err == nil {
+				_, err = runner.Run(false)
+			}
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(applied, DeepEquals, []int{1, 2})
+}
+
+func (mt *MigrationTestSuite) TestUserRoles(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	users := mt.sess.C("users")
+	c.Assert(users.Insert(bson.M{"email": "admin@test.com", "isadmin": true}), IsNil)
+	c.Assert(users.Insert(bson.M{"email": "user@test.com", "isadmin": false, "role": ""}), IsNil)
+	c.Assert(users.Insert(bson.M{"email": "viewer@test.com", "isadmin": false, "role": server.RoleViewer}), IsNil)
+
+	runner, err := server.NewMigrationRunner(mt.sess, server.Migrations)
+	c.Assert(err, IsNil)
+
+	results, err := runner.Run(true)
+	c.Assert(err, IsNil)
+	c.Assert(migrationResult(c, results, 3).Changed, Equals, 2)
+
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+
+	user := bson.M{}
+	c.Assert(users.Find(bson.M{"email": "admin@test.com"}).One(&user), IsNil)
+	c.Assert(user["role"], Equals, server.RolePlatformAdmin)
+	c.Assert(users.Find(bson.M{"email": "user@test.com"}).One(&user), IsNil)
+	c.Assert(user["role"], Equals, server.RoleEditor)
+	c.Assert(users.Find(bson.M{"email": "viewer@test.com"}).One(&user), IsNil)
+	c.Assert(user["role"], Equals, server.RoleViewer)
+}
+
+func (mt *MigrationTestSuite) TestEntityIndexes(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	entities := mt.sess.C("entities")
+	c.Assert(entities.EnsureIndex(mgo.Index{Key: []string{"rev"}}), IsNil)
+	c.Assert(entities.Insert(
+		bson.M{"id": "duplicate", "rev": 0, "latest": false},
+		bson.M{"id": "duplicate", "rev": 0, "latest": true},
+		bson.M{"id": "nolatest", "rev": 0, "latest": false},
+		bson.M{"id": "latest", "companyid": "c1", "rev": 0, "latest": true},
+		bson.M{"id": "latest", "companyid": "c1", "rev": 1, "latest": true},
+	), IsNil)
+
+	repo := server.NewEntityRepo(mt.sess)
+	violations, err := repo.CheckInvariants()
+	c.Assert(err, IsNil)
+	c.Assert(violations, HasLen, 3)
+	c.Assert(*violations[0], DeepEquals, server.EntityViolation{EntityID: "duplicate", Rev: 0, Problem: server.EntityDuplicateRev, Count: 2})
+	c.Assert(*violations[1], DeepEquals, server.EntityViolation{EntityID: "latest", Problem: server.EntityMultipleLatest, Count: 2})
+	c.Assert(*violations[2], DeepEquals, server.EntityViolation{EntityID: "nolatest", Problem: server.EntityNoLatest, Count: 0})
+
+	// migration isn't applied while data violates invariants
+	runner, err := server.NewMigrationRunner(mt.sess, server.Migrations)
+	c.Assert(err, IsNil)
+	_, err = runner.Run(false)
+	c.Assert(err, ErrorMatches, ".*"+server.ErrEntityInvariantsViolated.Error())
+
+	_, err = entities.RemoveAll(bson.M{"id": bson.M{"$in": []string{"duplicate", "nolatest"}}})
+	c.Assert(err, IsNil)
+	c.Assert(entities.Update(bson.M{"id": "latest", "rev": 0}, bson.M{"$set": bson.M{"latest": false}}), IsNil)
+
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+
+	// many entities have first revision, but every revision of entity is stored once
+	c.Assert(entities.Insert(bson.M{"id": "another", "rev": 0, "latest": true}), IsNil)
+	c.Assert(mgo.IsDup(entities.Insert(bson.M{"id": "latest", "rev": 1, "latest": false})), Equals, true)
+	c.Assert(mgo.IsDup(entities.Insert(bson.M{"id": "latest", "companyid": "c1", "rev": 2, "latest": true})), Equals, true)
+
+	updated, err := repo.UpdateEntity(&grpc_gateway_entity.Entity{Id: "latest", CompanyId: "c1", Latest: true}, 1)
+	c.Assert(err, IsNil)
+	c.Assert(updated.Rev, Equals, int64(2))
+
+	violations, err = repo.CheckInvariants()
+	c.Assert(err, IsNil)
+	c.Assert(violations, HasLen, 0)
+}
+
+func (mt *MigrationTestSuite) TestLatestEntityIndex(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	// migrations before unique index of latest revisions
+	runner, err := server.NewMigrationRunner(mt.sess, server.Migrations[:11])
+	c.Assert(err, IsNil)
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+
+	entities := mt.sess.C("entities")
+	c.Assert(entities.Insert(
+		bson.M{"id": "e1", "companyid": "c1", "rev": 0, "latest": true},
+		bson.M{"id": "e1", "companyid": "c1", "rev": 1, "latest": true},
+		bson.M{"id": "e1", "companyid": "c1", "rev": 2, "latest": true},
+		bson.M{"id": "e2", "companyid": "c1", "rev": 0, "latest": true},
+	), IsNil)
+
+	runner, err = server.NewMigrationRunner(mt.sess, server.Migrations)
+	c.Assert(err, IsNil)
+
+	results, err := runner.Run(true)
+	c.Assert(err, IsNil)
+	c.Assert(migrationResult(c, results, 12).Changed, Equals, 1)
+
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+
+	latest, err := server.NewEntityRepo(mt.sess).GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Rev, Equals, int64(2))
+
+	n, err := entities.Find(bson.M{"id": "e1", "latest": true}).Count()
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 1)
+
+	// second latest revision isn't stored
+	c.Assert(mgo.IsDup(entities.Insert(bson.M{"id": "e2", "companyid": "c1", "rev": 1, "latest": true})), Equals, true)
+}
+
+func (mt *MigrationTestSuite) TestEntitySearchFields(c *C) {
+	mt.sess = testMongoDatabase(c)
+
+	entities := mt.sess.C("entities")
+	c.Assert(entities.Insert(
+		bson.M{"id": "e1", "companyid": "c1", "rev": 0, "latest": false, "commonname": "Muller"},
+		bson.M{"id": "e1", "companyid": "c1", "rev": 1, "latest": true, "commonname": "Müller"},
+	), IsNil)
+
+	runner, err := server.NewMigrationRunner(mt.sess, server.Migrations)
+	c.Assert(err, IsNil)
+
+	results, err := runner.Run(true)
+	c.Assert(err, IsNil)
+	c.Assert(migrationResult(c, results, 8).Changed, Equals, 2)
+
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+
+	list, err := server.NewEntityRepo(mt.sess).SearchEntities("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "MULLER"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+	c.Assert(list.Data[0].Rev, Equals, int64(1))
+}
+
+// migrationResult - result of migration with version
+func migrationResult(c *C, results []*server.MigrationResult, version int) *server.MigrationResult {
+	for _, result := range results {
+		if result.Version == version {
+			return result
+		}
+	}
+
+	c.Fatalf("migration %d isn't applied", version)
+	return nil
+}
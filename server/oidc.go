@@ -0,0 +1,264 @@
+package server
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrOIDCDiscoveryFailed - error when configuration of identity provider can't be loaded
+var ErrOIDCDiscoveryFailed = errors.New("cannot load configuration of identity provider")
+
+// ErrOIDCExchangeFailed - error when identity provider doesn't exchange authorization code for tokens
+var ErrOIDCExchangeFailed = errors.New("cannot exchange authorization code")
+
+// ErrInvalidIDToken - error when id token of identity provider is malformed, expired, signed by unknown key
+// or issued for another client
+var ErrInvalidIDToken = errors.New("id token is invalid")
+
+// OIDCProvider - client of OpenID Connect identity provider, which supports authorization code flow
+type OIDCProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	clientID     string
+	clientSecret string
+	client       *http.Client
+}
+
+// OIDCClaims - claims of verified id token which are used for mapping on users
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// DiscoverOIDCProvider - load configuration of identity provider from its well-known discovery document
+func DiscoverOIDCProvider(issuer, clientID, clientSecret string) (*OIDCProvider, error) {
+	provider := &OIDCProvider{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       GetHTTPClient(),
+	}
+
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := provider.getJSON(wellKnown, provider); err != nil {
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	// issuer in document should be exactly the same as configured one
+	if provider.Issuer != issuer || provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	return provider, nil
+}
+
+// AuthCodeURL - return url of identity provider where user should be redirected for authentication
+func (p *OIDCProvider) AuthCodeURL(redirectURI, state, nonce string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", redirectURI)
+	params.Set("scope", "openid email profile")
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	separator := "?"
+	if strings.Contains(p.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.AuthorizationEndpoint + separator + params.Encode()
+}
+
+// Exchange - exchange authorization code for id token
+func (p *OIDCProvider) Exchange(code, redirectURI string) (string, error) {
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequest("POST", p.TokenEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", ErrOIDCExchangeFailed
+	}
+	defer resp.Body.Close()
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&tokens) != nil || tokens.IDToken == "" {
+		return "", ErrOIDCExchangeFailed
+	}
+
+	return tokens.IDToken, nil
+}
+
+// VerifyIDToken - check signature, issuer, audience, expiration and nonce of id token and return its claims
+func (p *OIDCProvider) VerifyIDToken(rawIDToken, nonce string) (*OIDCClaims, error) {
+	keys, err := p.fetchKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrInvalidIDToken
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+
+		// provider with single key may omit key id
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+
+		return nil, ErrInvalidIDToken
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyIssuer(p.Issuer, true) || !claims.VerifyExpiresAt(0, true) {
+		return nil, ErrInvalidIDToken
+	}
+
+	if !hasAudience(claims["aud"], p.clientID) {
+		return nil, ErrInvalidIDToken
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrInvalidIDToken
+	}
+
+	result := &OIDCClaims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Email, _ = claims["email"].(string)
+
+	// some providers pass email_verified as string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = verified
+	case string:
+		result.EmailVerified = verified == "true"
+	}
+
+	return result, nil
+}
+
+// fetchKeys - load rsa signing keys of provider by key id
+func (p *OIDCProvider) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+
+	if err := p.getJSON(p.JWKSURI, &jwks); err != nil {
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.N, "="))
+		if err != nil {
+			continue
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.E, "="))
+		if err != nil || len(e) == 0 {
+			continue
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrOIDCDiscoveryFailed
+	}
+
+	return keys, nil
+}
+
+func (p *OIDCProvider) getJSON(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v of %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// hasAudience - check if audience claim, which can be string or list, contains client id
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsEmailDomainAllowed - check if domain of email is in list of allowed domains
+func IsEmailDomainAllowed(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range domains {
+		if strings.ToLower(strings.TrimPrefix(allowed, "@")) == domain {
+			return true
+		}
+	}
+
+	return false
+}
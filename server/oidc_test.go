@@ -0,0 +1,269 @@
+package server_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	"github.com/dgrijalva/jwt-go"
+	. "gopkg.in/check.v1"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// stubIdP - local OpenID Connect identity provider, which authenticates everyone as configured email
+type stubIdP struct {
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	clientID     string
+	clientSecret string
+
+	mu            sync.Mutex
+	email         string
+	emailVerified bool
+	nonces        map[string]string
+}
+
+func newStubIdP(clientID, clientSecret string) (*stubIdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	idp := &stubIdP{
+		key:           key,
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		emailVerified: true,
+		nonces:        map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", idp.jwks)
+	idp.server = httptest.NewServer(mux)
+	return idp, nil
+}
+
+func (idp *stubIdP) Issuer() string {
+	return idp.server.URL
+}
+
+func (idp *stubIdP) Close() {
+	idp.server.Close()
+}
+
+func (idp *stubIdP) SetUser(email string, verified bool) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.email = email
+	idp.emailVerified = verified
+}
+
+func (idp *stubIdP) SignIDToken(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "stub"
+	signed, _ := token.SignedString(idp.key)
+	return signed
+}
+
+func (idp *stubIdP) Claims(nonce string) jwt.MapClaims {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	return jwt.MapClaims{
+		"iss":            idp.Issuer(),
+		"aud":            idp.clientID,
+		"sub":            "subject",
+		"email":          idp.email,
+		"email_verified": idp.emailVerified,
+		"nonce":          nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func (idp *stubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 idp.Issuer(),
+		"authorization_endpoint": idp.Issuer() + "/authorize",
+		"token_endpoint":         idp.Issuer() + "/token",
+		"jwks_uri":               idp.Issuer() + "/jwks",
+	})
+}
+
+func (idp *stubIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code := fmt.Sprintf("code_%v", time.Now().UnixNano())
+
+	idp.mu.Lock()
+	idp.nonces[code] = query.Get("nonce")
+	idp.mu.Unlock()
+
+	redirect := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != idp.clientID || clientSecret != idp.clientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	code := r.FormValue("code")
+	idp.mu.Lock()
+	nonce, ok := idp.nonces[code]
+	delete(idp.nonces, code)
+	idp.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"token_type": "Bearer",
+		"id_token":   idp.SignIDToken(idp.Claims(nonce)),
+	})
+}
+
+func (idp *stubIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := idp.key.PublicKey
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": "stub",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+type OIDCTestSuite struct {
+	idp *stubIdP
+}
+
+var _ = Suite(&OIDCTestSuite{})
+
+func (ot *OIDCTestSuite) SetUpSuite(c *C) {
+	var err error
+	ot.idp, err = newStubIdP("client", "secret")
+	c.Assert(err, IsNil)
+}
+
+func (ot *OIDCTestSuite) TearDownSuite(c *C) {
+	ot.idp.Close()
+}
+
+func (ot *OIDCTestSuite) TestDiscovery(c *C) {
+	provider, err := server.DiscoverOIDCProvider(ot.idp.Issuer(), "client", "secret")
+	c.Assert(err, IsNil)
+	c.Assert(provider.TokenEndpoint, Equals, ot.idp.Issuer()+"/token")
+
+	authURL, err := url.Parse(provider.AuthCodeURL("http://localhost/callback", "state1", "nonce1"))
+	c.Assert(err, IsNil)
+	c.Assert(authURL.Path, Equals, "/authorize")
+	c.Assert(authURL.Query().Get("client_id"), Equals, "client")
+	c.Assert(authURL.Query().Get("response_type"), Equals, "code")
+	c.Assert(authURL.Query().Get("state"), Equals, "state1")
+	c.Assert(authURL.Query().Get("nonce"), Equals, "nonce1")
+
+	// issuer in discovery document should match configured one
+	_, err = server.DiscoverOIDCProvider(ot.idp.Issuer()+"/", "client", "secret")
+	c.Assert(err, Equals, server.ErrOIDCDiscoveryFailed)
+}
+
+func (ot *OIDCTestSuite) TestExchangeAndVerify(c *C) {
+	ot.idp.SetUser("user@example.com", true)
+
+	provider, err := server.DiscoverOIDCProvider(ot.idp.Issuer(), "client", "secret")
+	c.Assert(err, IsNil)
+
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(provider.AuthCodeURL("http://localhost/callback", "state1", "nonce1"))
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	c.Assert(err, IsNil)
+	c.Assert(location.Query().Get("state"), Equals, "state1")
+
+	rawIDToken, err := provider.Exchange(location.Query().Get("code"), "http://localhost/callback")
+	c.Assert(err, IsNil)
+
+	claims, err := provider.VerifyIDToken(rawIDToken, "nonce1")
+	c.Assert(err, IsNil)
+	c.Assert(claims.Email, Equals, "user@example.com")
+	c.Assert(claims.EmailVerified, Equals, true)
+
+	// code can be used only once
+	_, err = provider.Exchange(location.Query().Get("code"), "http://localhost/callback")
+	c.Assert(err, Equals, server.ErrOIDCExchangeFailed)
+
+	// client with wrong secret can't exchange code
+	wrongClient, err := server.DiscoverOIDCProvider(ot.idp.Issuer(), "client", "wrong")
+	c.Assert(err, IsNil)
+	_, err = wrongClient.Exchange("code", "http://localhost/callback")
+	c.Assert(err, Equals, server.ErrOIDCExchangeFailed)
+}
+
+func (ot *OIDCTestSuite) TestVerifyInvalidIDToken(c *C) {
+	provider, err := server.DiscoverOIDCProvider(ot.idp.Issuer(), "client", "secret")
+	c.Assert(err, IsNil)
+
+	_, err = provider.VerifyIDToken(ot.idp.SignIDToken(ot.idp.Claims("nonce1")), "nonce2")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+
+	claims := ot.idp.Claims("nonce1")
+	claims["aud"] = []string{"another"}
+	_, err = provider.VerifyIDToken(ot.idp.SignIDToken(claims), "nonce1")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+
+	claims = ot.idp.Claims("nonce1")
+	claims["aud"] = []string{"another", "client"}
+	_, err = provider.VerifyIDToken(ot.idp.SignIDToken(claims), "nonce1")
+	c.Assert(err, IsNil)
+
+	claims = ot.idp.Claims("nonce1")
+	claims["iss"] = "https://evil.example.com"
+	_, err = provider.VerifyIDToken(ot.idp.SignIDToken(claims), "nonce1")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+
+	claims = ot.idp.Claims("nonce1")
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = provider.VerifyIDToken(ot.idp.SignIDToken(claims), "nonce1")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+
+	// token signed by another key
+	other, err := newStubIdP("client", "secret")
+	c.Assert(err, IsNil)
+	defer other.Close()
+
+	claims = ot.idp.Claims("nonce1")
+	_, err = provider.VerifyIDToken(other.SignIDToken(claims), "nonce1")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+
+	// symmetric algorithms aren't accepted
+	hmacToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	_, err = provider.VerifyIDToken(hmacToken, "nonce1")
+	c.Assert(err, Equals, server.ErrInvalidIDToken)
+}
+
+func (ot *OIDCTestSuite) TestIsEmailDomainAllowed(c *C) {
+	c.Assert(server.IsEmailDomainAllowed("user@Example.com", []string{"example.com"}), Equals, true)
+	c.Assert(server.IsEmailDomainAllowed("user@example.com", []string{"other.com", "@example.com"}), Equals, true)
+	c.Assert(server.IsEmailDomainAllowed("user@sub.example.com", []string{"example.com"}), Equals, false)
+	c.Assert(server.IsEmailDomainAllowed("user", []string{"example.com"}), Equals, false)
+}
@@ -0,0 +1,364 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidCursor - error when cursor of page is malformed or was issued for another sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidSort - error when list can't be sorted by requested field
+var ErrInvalidSort = errors.New("list can't be sorted by requested field")
+
+// DefaultPageSize - size of page when limit isn't requested, MaxPageSize - limit of page size
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// SortField - stored name of field which list can be sorted by and kind of its values
+type SortField struct {
+	Name string
+	Kind reflect.Kind
+}
+
+// SortFields - fields which list can be sorted by, api name of field to stored field
+type SortFields map[string]SortField
+
+// Fields which lists can be sorted by. Objects with equal values are sorted by id
+var (
+	EntitySortFields = SortFields{
+		"id":          {"id", reflect.String},
+		"common_name": {"commonname", reflect.String},
+		"type":        {"type", reflect.String},
+		"created_at":  {"createdat", reflect.Int64},
+	}
+	DeletedEntitySortFields = SortFields{
+		"id":          {"id", reflect.String},
+		"common_name": {"commonname", reflect.String},
+		"type":        {"type", reflect.String},
+		"created_at":  {"createdat", reflect.Int64},
+		"deleted_at":  {"deletedat", reflect.Int64},
+	}
+	UserSortFields = SortFields{
+		"id":    {"id", reflect.String},
+		"email": {"email", reflect.String},
+		"name":  {"name", reflect.String},
+		"role":  {"role", reflect.String},
+	}
+	InvitationSortFields = SortFields{
+		"id":    {"id", reflect.String},
+		"email": {"email", reflect.String},
+		"name":  {"name", reflect.String},
+	}
+	CompanySortFields = SortFields{
+		"id":   {"id", reflect.String},
+		"name": {"name", reflect.String},
+	}
+	// revisions of entity have the same id, so they are sorted only by number of revision
+	EntityRevisionSortFields = SortFields{
+		"rev": {"rev", reflect.Int64},
+	}
+	SessionSortFields = SortFields{
+		"id":           {"id", reflect.String},
+		"created_at":   {"createdat", reflect.Int64},
+		"last_seen_at": {"lastseenat", reflect.Int64},
+	}
+	APIKeySortFields = SortFields{
+		"id":         {"id", reflect.String},
+		"name":       {"name", reflect.String},
+		"created_at": {"createdat", reflect.Int64},
+	}
+)
+
+// accepts - check that value of cursor can be compared with field. Only null and scalars of kind
+// of field are accepted, so documents with query operators don't get into query
+func (f SortField) accepts(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	switch f.Kind {
+	case reflect.String:
+		_, ok := value.(string)
+		return ok
+	case reflect.Int64:
+		_, ok := toInt64(value)
+		return ok
+	}
+
+	return false
+}
+
+// Page - requested page of list. Objects are sorted by Field and by id, after is position of last object
+// of previous page. Nil page means whole list
+type Page struct {
+	Limit int
+	Sort  string
+	Field string
+	Desc  bool
+
+	after *pageCursor
+}
+
+// pageCursor - position in list, it's passed to clients as opaque string
+type pageCursor struct {
+	Sort  string      `bson:"s"`
+	Value interface{} `bson:"v"`
+	ID    string      `bson:"i"`
+}
+
+// NewPage - parse requested page. Sort is api name of field, "-" prefix means descending order,
+// defaultSort is used when sort isn't requested
+func NewPage(cursor string, limit int64, sortBy string, fields SortFields, defaultSort string) (*Page, error) {
+	if sortBy == "" {
+		sortBy = defaultSort
+	}
+
+	page := &Page{
+		Limit: int(limit),
+		Sort:  sortBy,
+		Desc:  strings.HasPrefix(sortBy, "-"),
+	}
+
+	field, ok := fields[strings.TrimPrefix(sortBy, "-")]
+	if !ok {
+		return nil, ErrInvalidSort
+	}
+	page.Field = field.Name
+
+	if page.Limit <= 0 {
+		page.Limit = DefaultPageSize
+	}
+	if page.Limit > MaxPageSize {
+		page.Limit = MaxPageSize
+	}
+
+	if cursor == "" {
+		return page, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	page.after = &pageCursor{}
+	if err := bson.Unmarshal(raw, page.after); err != nil || page.after.Sort != sortBy || !field.accepts(page.after.Value) {
+		return nil, ErrInvalidCursor
+	}
+
+	return page, nil
+}
+
+// cursor - cursor of page which starts after obj
+func (p *Page) cursor(obj interface{}) (string, error) {
+	value, id, err := p.key(obj)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := bson.Marshal(&pageCursor{Sort: p.Sort, Value: value, ID: id})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// key - value of sort field and id of stored object
+func (p *Page) key(obj interface{}) (interface{}, string, error) {
+	raw, err := bson.Marshal(obj)
+	if err != nil {
+		return nil, "", err
+	}
+
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, doc); err != nil {
+		return nil, "", err
+	}
+
+	id, _ := doc["id"].(string)
+	return doc[p.Field], id, nil
+}
+
+// sort - fields of mongo sort
+func (p *Page) sort() []string {
+	if p.Desc {
+		return []string{"-" + p.Field, "-id"}
+	}
+
+	return []string{p.Field, "id"}
+}
+
+// query - add condition of objects after cursor to query
+func (p *Page) query(query bson.M) bson.M {
+	if p.after == nil {
+		return query
+	}
+
+	op := "$gt"
+	if p.Desc {
+		op = "$lt"
+	}
+
+	after := bson.M{"$or": []bson.M{
+		{p.Field: bson.M{op: p.after.Value}},
+		{p.Field: p.after.Value, "id": bson.M{op: p.after.ID}},
+	}}
+
+	return bson.M{"$and": []bson.M{query, after}}
+}
+
+// findPage - find page of objects which match query into result, which is pointer to slice.
+// Returns cursor of next page and number of all objects which match query
+func findPage(c *mgo.Collection, query bson.M, page *Page, result interface{}) (string, int, error) {
+	if page == nil {
+		err := c.Find(query).All(result)
+		return "", reflect.ValueOf(result).Elem().Len(), err
+	}
+
+	total, err := c.Find(query).Count()
+	if err != nil {
+		return "", 0, err
+	}
+
+	// one more object shows if there is next page
+	err = c.Find(page.query(query)).Sort(page.sort()...).Limit(page.Limit + 1).All(result)
+	if err != nil {
+		return "", 0, err
+	}
+
+	next, err := page.cut(result)
+	return next, total, err
+}
+
+// memoryPage - sort objects and leave only objects of page in them, objects is pointer to slice.
+// Returns cursor of next page and number of all objects
+func memoryPage(objects interface{}, page *Page) (string, int, error) {
+	list := reflect.ValueOf(objects).Elem()
+	total := list.Len()
+	if page == nil {
+		return "", total, nil
+	}
+
+	type item struct {
+		value interface{}
+		id    string
+		obj   reflect.Value
+	}
+
+	items := make([]item, 0, total)
+	for i := 0; i < total; i++ {
+		value, id, err := page.key(list.Index(i).Interface())
+		if err != nil {
+			return "", 0, err
+		}
+
+		items = append(items, item{value: value, id: id, obj: list.Index(i)})
+	}
+
+	compare := func(value interface{}, id string, other interface{}, otherID string) int {
+		result := compareValues(value, other)
+		if result == 0 {
+			result = strings.Compare(id, otherID)
+		}
+
+		if page.Desc {
+			return -result
+		}
+		return result
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return compare(items[i].value, items[i].id, items[j].value, items[j].id) < 0
+	})
+
+	sorted := reflect.MakeSlice(list.Type(), 0, total)
+	for _, item := range items {
+		if page.after != nil && compare(item.value, item.id, page.after.Value, page.after.ID) <= 0 {
+			continue
+		}
+
+		sorted = reflect.Append(sorted, item.obj)
+	}
+
+	list.Set(sorted)
+	next, err := page.cut(objects)
+	return next, total, err
+}
+
+// cut - leave only objects of page in objects, which can contain one more object of next page
+func (p *Page) cut(objects interface{}) (string, error) {
+	list := reflect.ValueOf(objects).Elem()
+	if list.Len() <= p.Limit {
+		return "", nil
+	}
+
+	list.Set(list.Slice(0, p.Limit))
+	return p.cursor(list.Index(p.Limit - 1).Interface())
+}
+
+// compareValues - compare stored values of the same field, values of different types are ordered by type
+func compareValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			if a == b {
+				return 0
+			}
+			if !a {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if a, ok := toInt64(a); ok {
+		if b, ok := toInt64(b); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	return strings.Compare(reflect.TypeOf(a).String(), reflect.TypeOf(b).String())
+}
+
+// toInt64 - integer value of stored number
+func toInt64(value interface{}) (int64, bool) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package server_test
+
+import (
+	"encoding/base64"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type PaginationTestSuite struct{}
+
+var _ = Suite(&PaginationTestSuite{})
+
+func (pt *PaginationTestSuite) TestNewPage(c *C) {
+	page, err := server.NewPage("", 0, "", server.CompanySortFields, "name")
+	c.Assert(err, IsNil)
+	c.Assert(page.Limit, Equals, server.DefaultPageSize)
+	c.Assert(page.Field, Equals, "name")
+	c.Assert(page.Desc, Equals, false)
+
+	// size of page is capped
+	page, err = server.NewPage("", 100000, "-id", server.CompanySortFields, "name")
+	c.Assert(err, IsNil)
+	c.Assert(page.Limit, Equals, server.MaxPageSize)
+	c.Assert(page.Field, Equals, "id")
+	c.Assert(page.Desc, Equals, true)
+
+	_, err = server.NewPage("", 10, "password", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidSort)
+
+	_, err = server.NewPage("not a cursor", 10, "", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+
+	_, err = server.NewPage("AAAA", 10, "", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+}
+
+// cursor is decoded from request, so value of sort field must be scalar of type of field
+func (pt *PaginationTestSuite) TestForgedCursor(c *C) {
+	cursor := func(sort string, value interface{}) string {
+		raw, err := bson.Marshal(bson.M{"s": sort, "v": value, "i": "id"})
+		c.Assert(err, IsNil)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	_, err := server.NewPage(cursor("email", "a@test.com"), 10, "", server.UserSortFields, "email")
+	c.Assert(err, IsNil)
+
+	_, err = server.NewPage(cursor("email", nil), 10, "", server.UserSortFields, "email")
+	c.Assert(err, IsNil)
+
+	_, err = server.NewPage(cursor("-created_at", int64(100)), 10, "-created_at", server.EntitySortFields, "")
+	c.Assert(err, IsNil)
+
+	_, err = server.NewPage(cursor("email", bson.M{"$ne": ""}), 10, "", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+
+	_, err = server.NewPage(cursor("email", []string{"a@test.com"}), 10, "", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+
+	_, err = server.NewPage(cursor("email", 100), 10, "", server.UserSortFields, "email")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+
+	_, err = server.NewPage(cursor("-created_at", "100"), 10, "-created_at", server.EntitySortFields, "")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+}
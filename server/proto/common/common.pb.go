@@ -10,6 +10,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	IDRequest
+	ListRequest
 	MetaResponse
 	CommonResponse
 	IDResponse
@@ -34,7 +35,7 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type IDRequest struct {
-	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id"`
 }
 
 func (m *IDRequest) Reset()                    { *m = IDRequest{} }
@@ -49,16 +50,56 @@ func (m *IDRequest) GetId() string {
 	return ""
 }
 
+type ListRequest struct {
+	Id     string `protobuf:"bytes,1,opt,name=id" json:"id"`
+	Cursor string `protobuf:"bytes,2,opt,name=cursor" json:"cursor"`
+	Limit  int64  `protobuf:"varint,3,opt,name=limit" json:"limit"`
+	Sort   string `protobuf:"bytes,4,opt,name=sort" json:"sort"`
+}
+
+func (m *ListRequest) Reset()                    { *m = ListRequest{} }
+func (m *ListRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()               {}
+func (*ListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ListRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ListRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
 type MetaResponse struct {
-	Ok         bool   `protobuf:"varint,1,opt,name=ok" json:"ok,omitempty"`
-	Error      string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
-	StatusCode int32  `protobuf:"varint,3,opt,name=status_code,json=statusCode" json:"status_code,omitempty"`
+	Ok         bool   `protobuf:"varint,1,opt,name=ok" json:"ok"`
+	Error      string `protobuf:"bytes,2,opt,name=error" json:"error"`
+	StatusCode int32  `protobuf:"varint,3,opt,name=status_code,json=statusCode" json:"status_code"`
 }
 
 func (m *MetaResponse) Reset()                    { *m = MetaResponse{} }
 func (m *MetaResponse) String() string            { return proto.CompactTextString(m) }
 func (*MetaResponse) ProtoMessage()               {}
-func (*MetaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*MetaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 func (m *MetaResponse) GetOk() bool {
 	if m != nil {
@@ -82,13 +123,13 @@ func (m *MetaResponse) GetStatusCode() int32 {
 }
 
 type CommonResponse struct {
-	Meta *MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta,omitempty"`
+	Meta *MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
 }
 
 func (m *CommonResponse) Reset()                    { *m = CommonResponse{} }
 func (m *CommonResponse) String() string            { return proto.CompactTextString(m) }
 func (*CommonResponse) ProtoMessage()               {}
-func (*CommonResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*CommonResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *CommonResponse) GetMeta() *MetaResponse {
 	if m != nil {
@@ -98,14 +139,14 @@ func (m *CommonResponse) GetMeta() *MetaResponse {
 }
 
 type IDResponse struct {
-	Meta *MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta,omitempty"`
-	Id   string        `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+	Meta *MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Id   string        `protobuf:"bytes,2,opt,name=id" json:"id"`
 }
 
 func (m *IDResponse) Reset()                    { *m = IDResponse{} }
 func (m *IDResponse) String() string            { return proto.CompactTextString(m) }
 func (*IDResponse) ProtoMessage()               {}
-func (*IDResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*IDResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *IDResponse) GetMeta() *MetaResponse {
 	if m != nil {
@@ -122,18 +163,18 @@ func (m *IDResponse) GetId() string {
 }
 
 type Address struct {
-	AddressLine_1 string `protobuf:"bytes,1,opt,name=address_line_1,json=addressLine1" json:"address_line_1,omitempty"`
-	AddressLine_2 string `protobuf:"bytes,2,opt,name=address_line_2,json=addressLine2" json:"address_line_2,omitempty"`
-	City          string `protobuf:"bytes,3,opt,name=city" json:"city,omitempty"`
-	Region        string `protobuf:"bytes,4,opt,name=region" json:"region,omitempty"`
-	PostalCode    string `protobuf:"bytes,5,opt,name=postal_code,json=postalCode" json:"postal_code,omitempty"`
-	Country       string `protobuf:"bytes,6,opt,name=country" json:"country,omitempty"`
+	AddressLine_1 string `protobuf:"bytes,1,opt,name=address_line_1,json=addressLine1" json:"address_line_1"`
+	AddressLine_2 string `protobuf:"bytes,2,opt,name=address_line_2,json=addressLine2" json:"address_line_2"`
+	City          string `protobuf:"bytes,3,opt,name=city" json:"city"`
+	Region        string `protobuf:"bytes,4,opt,name=region" json:"region"`
+	PostalCode    string `protobuf:"bytes,5,opt,name=postal_code,json=postalCode" json:"postal_code"`
+	Country       string `protobuf:"bytes,6,opt,name=country" json:"country"`
 }
 
 func (m *Address) Reset()                    { *m = Address{} }
 func (m *Address) String() string            { return proto.CompactTextString(m) }
 func (*Address) ProtoMessage()               {}
-func (*Address) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*Address) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *Address) GetAddressLine_1() string {
 	if m != nil {
@@ -179,6 +220,7 @@ func (m *Address) GetCountry() string {
 
 func init() {
 	proto.RegisterType((*IDRequest)(nil), "grpc.gateway.common.IDRequest")
+	proto.RegisterType((*ListRequest)(nil), "grpc.gateway.common.ListRequest")
 	proto.RegisterType((*MetaResponse)(nil), "grpc.gateway.common.MetaResponse")
 	proto.RegisterType((*CommonResponse)(nil), "grpc.gateway.common.CommonResponse")
 	proto.RegisterType((*IDResponse)(nil), "grpc.gateway.common.IDResponse")
@@ -188,26 +230,28 @@ func init() {
 func init() { proto.RegisterFile("proto/common/common.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 326 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x51, 0x4d, 0x4b, 0x03, 0x31,
-	0x10, 0x65, 0xd7, 0x76, 0xdb, 0x4e, 0x4b, 0x0f, 0x51, 0x64, 0xfd, 0x00, 0xeb, 0xe2, 0xa1, 0xa7,
-	0x2d, 0xad, 0xf8, 0x03, 0xb4, 0x82, 0x08, 0x7a, 0x89, 0x78, 0xf1, 0x52, 0xe2, 0xee, 0xb0, 0x84,
-	0xb6, 0x99, 0x35, 0x49, 0x91, 0xfe, 0x37, 0x7f, 0x9c, 0x6c, 0x12, 0x4b, 0x41, 0x6f, 0x9e, 0x32,
-	0xf3, 0xde, 0xcc, 0x63, 0xde, 0x0b, 0x9c, 0xd4, 0x9a, 0x2c, 0x4d, 0x0a, 0x5a, 0xaf, 0x49, 0x85,
-	0x27, 0x77, 0x18, 0x3b, 0xac, 0x74, 0x5d, 0xe4, 0x95, 0xb0, 0xf8, 0x29, 0xb6, 0xb9, 0xa7, 0x4e,
-	0xcf, 0x2b, 0xa2, 0x6a, 0x85, 0x13, 0x51, 0xcb, 0x89, 0x50, 0x8a, 0xac, 0xb0, 0x92, 0x94, 0xf1,
-	0x2b, 0xd9, 0x19, 0xf4, 0x1e, 0xef, 0x39, 0x7e, 0x6c, 0xd0, 0x58, 0x36, 0x84, 0x58, 0x96, 0x69,
-	0x34, 0x8a, 0xc6, 0x3d, 0x1e, 0xcb, 0x32, 0x7b, 0x85, 0xc1, 0x33, 0x5a, 0xc1, 0xd1, 0xd4, 0xa4,
-	0x0c, 0x36, 0x3c, 0x2d, 0x1d, 0xdf, 0xe5, 0x31, 0x2d, 0xd9, 0x11, 0xb4, 0x51, 0x6b, 0xd2, 0x69,
-	0xec, 0x56, 0x7c, 0xc3, 0x2e, 0xa0, 0x6f, 0xac, 0xb0, 0x1b, 0xb3, 0x28, 0xa8, 0xc4, 0xf4, 0x60,
-	0x14, 0x8d, 0xdb, 0x1c, 0x3c, 0x34, 0xa7, 0x12, 0xb3, 0x07, 0x18, 0xce, 0xdd, 0x6d, 0x3b, 0xe1,
-	0x1b, 0x68, 0xad, 0xd1, 0x0a, 0x27, 0xdd, 0x9f, 0x5d, 0xe6, 0x7f, 0xf8, 0xc8, 0xf7, 0x2f, 0xe1,
-	0x6e, 0x3c, 0x7b, 0x01, 0x68, 0x8e, 0xff, 0x97, 0x48, 0x30, 0x1d, 0xef, 0x4c, 0x7f, 0x45, 0xd0,
-	0xb9, 0x2d, 0x4b, 0x8d, 0xc6, 0xb0, 0x2b, 0x18, 0x0a, 0x5f, 0x2e, 0x56, 0x52, 0xe1, 0x62, 0x1a,
-	0xc2, 0x19, 0x04, 0xf4, 0x49, 0x2a, 0x9c, 0xfe, 0x9a, 0x9a, 0x05, 0xb5, 0xfd, 0xa9, 0x19, 0x63,
-	0xd0, 0x2a, 0xa4, 0xdd, 0xba, 0x3c, 0x7a, 0xdc, 0xd5, 0xec, 0x18, 0x12, 0x8d, 0x95, 0x24, 0x95,
-	0xb6, 0x1c, 0x1a, 0xba, 0x26, 0xc2, 0x9a, 0x8c, 0x15, 0x2b, 0x1f, 0x61, 0xdb, 0x91, 0xe0, 0xa1,
-	0x26, 0x42, 0x96, 0x42, 0xa7, 0xa0, 0x8d, 0xb2, 0x7a, 0x9b, 0x26, 0x8e, 0xfc, 0x69, 0xef, 0xba,
-	0x6f, 0x89, 0xf7, 0xfa, 0x9e, 0xb8, 0x1f, 0xbe, 0xfe, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x78, 0x37,
-	0xe4, 0xa5, 0x31, 0x02, 0x00, 0x00,
+	// 364 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x4a, 0xeb, 0x40,
+	0x14, 0x86, 0x49, 0xda, 0xa6, 0xed, 0x69, 0xe9, 0x62, 0xee, 0xe5, 0x92, 0xab, 0x82, 0x35, 0xb8,
+	0xe8, 0x2a, 0xa5, 0x15, 0x1f, 0x40, 0x5b, 0x90, 0x42, 0xdd, 0x8c, 0xb8, 0x71, 0x13, 0xc6, 0x64,
+	0x08, 0x43, 0x93, 0x39, 0x71, 0x66, 0x82, 0xf4, 0xdd, 0x7c, 0x38, 0xc9, 0x4c, 0x5a, 0x84, 0xea,
+	0xc6, 0x55, 0xce, 0xff, 0x9f, 0x93, 0xf3, 0x67, 0xbe, 0x0c, 0xfc, 0xaf, 0x14, 0x1a, 0x9c, 0xa7,
+	0x58, 0x96, 0x28, 0xdb, 0x47, 0x6c, 0x3d, 0xf2, 0x27, 0x57, 0x55, 0x1a, 0xe7, 0xcc, 0xf0, 0x77,
+	0xb6, 0x8f, 0x5d, 0xeb, 0xec, 0x22, 0x47, 0xcc, 0x0b, 0x3e, 0x67, 0x95, 0x98, 0x33, 0x29, 0xd1,
+	0x30, 0x23, 0x50, 0x6a, 0xf7, 0x4a, 0x74, 0x0e, 0xc3, 0xcd, 0x9a, 0xf2, 0xb7, 0x9a, 0x6b, 0x43,
+	0x26, 0xe0, 0x8b, 0x2c, 0xf4, 0xa6, 0xde, 0x6c, 0x48, 0x7d, 0x91, 0x45, 0x09, 0x8c, 0xb6, 0x42,
+	0x9b, 0x1f, 0xda, 0xe4, 0x1f, 0x04, 0x69, 0xad, 0x34, 0xaa, 0xd0, 0xb7, 0x5e, 0xab, 0xc8, 0x5f,
+	0xe8, 0x15, 0xa2, 0x14, 0x26, 0xec, 0x4c, 0xbd, 0x59, 0x87, 0x3a, 0x41, 0x08, 0x74, 0x35, 0x2a,
+	0x13, 0x76, 0xed, 0xac, 0xad, 0xa3, 0x67, 0x18, 0x3f, 0x72, 0xc3, 0x28, 0xd7, 0x15, 0x4a, 0xcd,
+	0x9b, 0x04, 0xdc, 0xd9, 0x84, 0x01, 0xf5, 0x71, 0xd7, 0x6c, 0xe2, 0x4a, 0x1d, 0x03, 0x9c, 0x20,
+	0x97, 0x30, 0xd2, 0x86, 0x99, 0x5a, 0x27, 0x29, 0x66, 0xdc, 0xa6, 0xf4, 0x28, 0x38, 0x6b, 0x85,
+	0x19, 0x8f, 0x1e, 0x60, 0xb2, 0xb2, 0x87, 0x3f, 0x2e, 0xbe, 0x85, 0x6e, 0xc9, 0x0d, 0xb3, 0xab,
+	0x47, 0xcb, 0xab, 0xf8, 0x1b, 0x50, 0xf1, 0xd7, 0x2f, 0xa1, 0x76, 0x3c, 0x7a, 0x02, 0xd8, 0xac,
+	0x0f, 0xde, 0x2f, 0x97, 0xb4, 0xd8, 0xfc, 0x23, 0xd5, 0x0f, 0x0f, 0xfa, 0x77, 0x59, 0xa6, 0xb8,
+	0xd6, 0xe4, 0x1a, 0x26, 0xcc, 0x95, 0x49, 0x21, 0x24, 0x4f, 0x16, 0x2d, 0xde, 0x71, 0xeb, 0x6e,
+	0x85, 0xe4, 0x8b, 0x93, 0xa9, 0x65, 0xe8, 0x9f, 0x4c, 0x2d, 0x1b, 0xc0, 0xa9, 0x30, 0x7b, 0xcb,
+	0x63, 0x48, 0x6d, 0xdd, 0xfc, 0x22, 0xc5, 0x73, 0x81, 0xb2, 0xc5, 0xde, 0xaa, 0x06, 0x61, 0x85,
+	0xda, 0xb0, 0xc2, 0x21, 0xec, 0xd9, 0x26, 0x38, 0xab, 0x41, 0x48, 0x42, 0xe8, 0xa7, 0x58, 0x4b,
+	0xa3, 0xf6, 0x61, 0x60, 0x9b, 0x07, 0x79, 0x3f, 0x78, 0x09, 0xdc, 0x59, 0x5f, 0x03, 0x7b, 0x85,
+	0x6e, 0x3e, 0x07, 0x00, 0xb4, 0x1e, 0x88, 0xd5, 0x92, 0x02, 0x00, 0x00,
 }
@@ -10,6 +10,8 @@ It is generated from these files:
 
 It has these top-level messages:
 	Company
+	IdentityProvider
+	IdentityProviderResponse
 	CompanyListResponse
 	CompanyResponse
 */
@@ -20,7 +22,7 @@ import fmt "fmt"
 import math "math"
 import _ "github.com/grpc-ecosystem/grpc-gateway/third_party/googleapis/google/api"
 import grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
-import google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
+import _ "github.com/golang/protobuf/ptypes/empty"
 
 import (
 	context "golang.org/x/net/context"
@@ -70,15 +72,97 @@ func (m *Company) GetName() string {
 	return ""
 }
 
-type CompanyListResponse struct {
+type IdentityProvider struct {
+	CompanyId      string   `protobuf:"bytes,1,opt,name=company_id,json=companyId" json:"company_id"`
+	Issuer         string   `protobuf:"bytes,2,opt,name=issuer" json:"issuer"`
+	ClientId       string   `protobuf:"bytes,3,opt,name=client_id,json=clientId" json:"client_id"`
+	ClientSecret   string   `protobuf:"bytes,4,opt,name=client_secret,json=clientSecret" json:"client_secret"`
+	AllowedDomains []string `protobuf:"bytes,5,rep,name=allowed_domains,json=allowedDomains" json:"allowed_domains"`
+	IsEnabled      bool     `protobuf:"varint,6,opt,name=is_enabled,json=isEnabled" json:"is_enabled"`
+}
+
+func (m *IdentityProvider) Reset()                    { *m = IdentityProvider{} }
+func (m *IdentityProvider) String() string            { return proto.CompactTextString(m) }
+func (*IdentityProvider) ProtoMessage()               {}
+func (*IdentityProvider) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *IdentityProvider) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *IdentityProvider) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *IdentityProvider) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *IdentityProvider) GetClientSecret() string {
+	if m != nil {
+		return m.ClientSecret
+	}
+	return ""
+}
+
+func (m *IdentityProvider) GetAllowedDomains() []string {
+	if m != nil {
+		return m.AllowedDomains
+	}
+	return nil
+}
+
+func (m *IdentityProvider) GetIsEnabled() bool {
+	if m != nil {
+		return m.IsEnabled
+	}
+	return false
+}
+
+type IdentityProviderResponse struct {
 	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
-	Data []*Company                        `protobuf:"bytes,2,rep,name=data" json:"data"`
+	Data *IdentityProvider                 `protobuf:"bytes,2,opt,name=data" json:"data"`
+}
+
+func (m *IdentityProviderResponse) Reset()                    { *m = IdentityProviderResponse{} }
+func (m *IdentityProviderResponse) String() string            { return proto.CompactTextString(m) }
+func (*IdentityProviderResponse) ProtoMessage()               {}
+func (*IdentityProviderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *IdentityProviderResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *IdentityProviderResponse) GetData() *IdentityProvider {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type CompanyListResponse struct {
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*Company                        `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
 }
 
 func (m *CompanyListResponse) Reset()                    { *m = CompanyListResponse{} }
 func (m *CompanyListResponse) String() string            { return proto.CompactTextString(m) }
 func (*CompanyListResponse) ProtoMessage()               {}
-func (*CompanyListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*CompanyListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *CompanyListResponse) GetMeta() *grpc_gateway_common.MetaResponse {
 	if m != nil {
@@ -94,6 +178,20 @@ func (m *CompanyListResponse) GetData() []*Company {
 	return nil
 }
 
+func (m *CompanyListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *CompanyListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
 type CompanyResponse struct {
 	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
 	Data *Company                          `protobuf:"bytes,2,opt,name=data" json:"data"`
@@ -102,7 +200,7 @@ type CompanyResponse struct {
 func (m *CompanyResponse) Reset()                    { *m = CompanyResponse{} }
 func (m *CompanyResponse) String() string            { return proto.CompactTextString(m) }
 func (*CompanyResponse) ProtoMessage()               {}
-func (*CompanyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*CompanyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *CompanyResponse) GetMeta() *grpc_gateway_common.MetaResponse {
 	if m != nil {
@@ -120,6 +218,8 @@ func (m *CompanyResponse) GetData() *Company {
 
 func init() {
 	proto.RegisterType((*Company)(nil), "grpc.gateway.company.Company")
+	proto.RegisterType((*IdentityProvider)(nil), "grpc.gateway.company.IdentityProvider")
+	proto.RegisterType((*IdentityProviderResponse)(nil), "grpc.gateway.company.IdentityProviderResponse")
 	proto.RegisterType((*CompanyListResponse)(nil), "grpc.gateway.company.CompanyListResponse")
 	proto.RegisterType((*CompanyResponse)(nil), "grpc.gateway.company.CompanyResponse")
 }
@@ -138,8 +238,10 @@ type CompanyServiceClient interface {
 	CreateCompany(ctx context.Context, in *Company, opts ...grpc.CallOption) (*grpc_gateway_common.IDResponse, error)
 	UpdateCompany(ctx context.Context, in *Company, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
 	GetCompany(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*CompanyResponse, error)
-	GetCompanies(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*CompanyListResponse, error)
+	GetCompanies(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*CompanyListResponse, error)
 	DeleteCompany(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	SetIdentityProvider(ctx context.Context, in *IdentityProvider, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	GetIdentityProvider(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*IdentityProviderResponse, error)
 }
 
 type companyServiceClient struct {
@@ -177,7 +279,7 @@ func (c *companyServiceClient) GetCompany(ctx context.Context, in *grpc_gateway_
 	return out, nil
 }
 
-func (c *companyServiceClient) GetCompanies(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*CompanyListResponse, error) {
+func (c *companyServiceClient) GetCompanies(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*CompanyListResponse, error) {
 	out := new(CompanyListResponse)
 	err := grpc.Invoke(ctx, "/grpc.gateway.company.CompanyService/GetCompanies", in, out, c.cc, opts...)
 	if err != nil {
@@ -195,14 +297,34 @@ func (c *companyServiceClient) DeleteCompany(ctx context.Context, in *grpc_gatew
 	return out, nil
 }
 
+func (c *companyServiceClient) SetIdentityProvider(ctx context.Context, in *IdentityProvider, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.company.CompanyService/SetIdentityProvider", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *companyServiceClient) GetIdentityProvider(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*IdentityProviderResponse, error) {
+	out := new(IdentityProviderResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.company.CompanyService/GetIdentityProvider", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for CompanyService service
 
 type CompanyServiceServer interface {
 	CreateCompany(context.Context, *Company) (*grpc_gateway_common.IDResponse, error)
 	UpdateCompany(context.Context, *Company) (*grpc_gateway_common.CommonResponse, error)
 	GetCompany(context.Context, *grpc_gateway_common.IDRequest) (*CompanyResponse, error)
-	GetCompanies(context.Context, *google_protobuf1.Empty) (*CompanyListResponse, error)
+	GetCompanies(context.Context, *grpc_gateway_common.ListRequest) (*CompanyListResponse, error)
 	DeleteCompany(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	SetIdentityProvider(context.Context, *IdentityProvider) (*grpc_gateway_common.CommonResponse, error)
+	GetIdentityProvider(context.Context, *grpc_gateway_common.IDRequest) (*IdentityProviderResponse, error)
 }
 
 func RegisterCompanyServiceServer(s *grpc.Server, srv CompanyServiceServer) {
@@ -264,7 +386,7 @@ func _CompanyService_GetCompany_Handler(srv interface{}, ctx context.Context, de
 }
 
 func _CompanyService_GetCompanies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf1.Empty)
+	in := new(grpc_gateway_common.ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -276,7 +398,7 @@ func _CompanyService_GetCompanies_Handler(srv interface{}, ctx context.Context,
 		FullMethod: "/grpc.gateway.company.CompanyService/GetCompanies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CompanyServiceServer).GetCompanies(ctx, req.(*google_protobuf1.Empty))
+		return srv.(CompanyServiceServer).GetCompanies(ctx, req.(*grpc_gateway_common.ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -299,6 +421,42 @@ func _CompanyService_DeleteCompany_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CompanyService_SetIdentityProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdentityProvider)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).SetIdentityProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.company.CompanyService/SetIdentityProvider",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).SetIdentityProvider(ctx, req.(*IdentityProvider))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompanyService_GetIdentityProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompanyServiceServer).GetIdentityProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.company.CompanyService/GetIdentityProvider",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompanyServiceServer).GetIdentityProvider(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CompanyService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "grpc.gateway.company.CompanyService",
 	HandlerType: (*CompanyServiceServer)(nil),
@@ -323,6 +481,14 @@ var _CompanyService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCompany",
 			Handler:    _CompanyService_DeleteCompany_Handler,
 		},
+		{
+			MethodName: "SetIdentityProvider",
+			Handler:    _CompanyService_SetIdentityProvider_Handler,
+		},
+		{
+			MethodName: "GetIdentityProvider",
+			Handler:    _CompanyService_GetIdentityProvider_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/company/company.proto",
@@ -331,33 +497,45 @@ var _CompanyService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("proto/company/company.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 435 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x92, 0xc1, 0x6e, 0x13, 0x31,
-	0x10, 0x86, 0xb5, 0xdb, 0x15, 0x25, 0x13, 0xb6, 0x54, 0x2e, 0x54, 0xcb, 0x86, 0x42, 0x58, 0x84,
-	0x14, 0x7a, 0xf0, 0xaa, 0x41, 0x5c, 0x38, 0x92, 0x56, 0x08, 0xa9, 0x5c, 0x16, 0x71, 0xe1, 0x82,
-	0x9c, 0xec, 0x74, 0x65, 0x29, 0x6b, 0x9b, 0xb5, 0x5b, 0x54, 0x45, 0x08, 0x89, 0x57, 0xe0, 0x4d,
-	0x78, 0x15, 0x5e, 0x81, 0x07, 0x41, 0xf1, 0xda, 0x21, 0x22, 0x21, 0xe4, 0xc0, 0x69, 0xec, 0xb1,
-	0x67, 0xbe, 0x99, 0x5f, 0x3f, 0xf4, 0x54, 0x23, 0x8d, 0xcc, 0x27, 0xb2, 0x56, 0x4c, 0x5c, 0xfb,
-	0x48, 0x6d, 0x96, 0xdc, 0xa9, 0x1a, 0x35, 0xa1, 0x15, 0x33, 0xf8, 0x89, 0x5d, 0x53, 0xf7, 0x96,
-	0xde, 0xaf, 0xa4, 0xac, 0xa6, 0x98, 0x33, 0xc5, 0x73, 0x26, 0x84, 0x34, 0xcc, 0x70, 0x29, 0x74,
-	0x5b, 0x93, 0xde, 0x5b, 0x34, 0xac, 0xa5, 0x70, 0xc1, 0x3d, 0xf5, 0x5c, 0xa1, 0xbd, 0x8d, 0x2f,
-	0x2f, 0x72, 0xac, 0x95, 0x71, 0xac, 0xec, 0x1c, 0x76, 0x47, 0x2d, 0x80, 0xec, 0x41, 0xc8, 0xcb,
-	0x24, 0xe8, 0x07, 0x83, 0x4e, 0x11, 0xf2, 0x92, 0x1c, 0x01, 0x70, 0xfd, 0x01, 0x05, 0x1b, 0x4f,
-	0xb1, 0x4c, 0xc2, 0x7e, 0x30, 0xb8, 0x59, 0x74, 0xb8, 0x3e, 0x6b, 0x13, 0x84, 0x40, 0x24, 0x58,
-	0x8d, 0xc9, 0x8e, 0x2d, 0xb0, 0xe7, 0xec, 0x0b, 0x1c, 0xb8, 0x6e, 0xe7, 0x5c, 0x9b, 0x02, 0xb5,
-	0x92, 0x42, 0x23, 0x79, 0x0e, 0x51, 0x8d, 0x86, 0xd9, 0xde, 0xdd, 0xe1, 0x23, 0xfa, 0xe7, 0x7e,
-	0xf3, 0x59, 0xdf, 0xa0, 0x61, 0xbe, 0xa0, 0xb0, 0xdf, 0xc9, 0x09, 0x44, 0x25, 0x33, 0x2c, 0x09,
-	0xfb, 0x3b, 0x83, 0xee, 0xf0, 0x88, 0xae, 0x93, 0x85, 0x3a, 0x5e, 0x61, 0xbf, 0x66, 0x33, 0xb8,
-	0xed, 0x13, 0xff, 0x0d, 0x1e, 0x6c, 0x09, 0x1f, 0x7e, 0x8f, 0x60, 0xcf, 0x65, 0xde, 0x62, 0x73,
-	0xc5, 0x27, 0x48, 0x2a, 0x88, 0x47, 0x0d, 0x32, 0x83, 0x5e, 0xe4, 0xcd, 0x8d, 0xd2, 0x87, 0x6b,
-	0xc7, 0x7b, 0x7d, 0xea, 0x87, 0xcb, 0x0e, 0xbf, 0xfe, 0xf8, 0xf9, 0x2d, 0xdc, 0xcf, 0xba, 0xf9,
-	0xd5, 0x89, 0xb7, 0xcd, 0x8b, 0xe0, 0x98, 0x28, 0x88, 0xdf, 0xa9, 0x72, 0x7b, 0xd0, 0xe3, 0xb5,
-	0xa0, 0x91, 0x0d, 0x0b, 0x58, 0xcf, 0xc2, 0xee, 0x66, 0xfb, 0x4b, 0xb0, 0x7c, 0xc6, 0xcb, 0xcf,
-	0x73, 0x62, 0x0d, 0xf0, 0x0a, 0x8d, 0xc7, 0x3d, 0xf8, 0xeb, 0xe0, 0x1f, 0x2f, 0x51, 0x9b, 0xf4,
-	0xc9, 0x66, 0x01, 0x3d, 0x31, 0xb1, 0x44, 0x42, 0x56, 0x88, 0xe4, 0x02, 0x6e, 0x2d, 0x70, 0x1c,
-	0x35, 0x39, 0xa4, 0xad, 0xad, 0xa9, 0xb7, 0x35, 0x3d, 0x9b, 0xdb, 0x3a, 0x7d, 0xba, 0x11, 0xb4,
-	0x6c, 0xcb, 0xec, 0xc0, 0xc2, 0x62, 0xb2, 0xac, 0x25, 0x11, 0x10, 0x9f, 0xe2, 0x14, 0x7f, 0x0b,
-	0xf9, 0xaf, 0xcd, 0xb6, 0x52, 0xd2, 0xed, 0x75, 0xbc, 0xb2, 0xd7, 0xcb, 0xce, 0xfb, 0x5d, 0x77,
-	0x1f, 0xdf, 0xb0, 0xab, 0x3c, 0xfb, 0x15, 0x00, 0x00, 0xff, 0xff, 0x21, 0x9a, 0x49, 0xd7, 0x1d,
-	0x04, 0x00, 0x00,
+	// 639 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0xe5, 0x24, 0x4d, 0xeb, 0x49, 0xd3, 0x56, 0x9b, 0x52, 0x99, 0xa4, 0xa5, 0xa9, 0x2b,
+	0x68, 0xe8, 0xc1, 0x56, 0x83, 0xb8, 0xf4, 0x48, 0x8a, 0xaa, 0x48, 0x45, 0x42, 0xae, 0xb8, 0x70,
+	0x89, 0xb6, 0xf1, 0x10, 0xad, 0x64, 0x7b, 0x8d, 0x77, 0xd3, 0x52, 0x55, 0xbd, 0xc0, 0x11, 0x71,
+	0xe2, 0x5d, 0x78, 0x0e, 0xa4, 0xbe, 0x02, 0x0f, 0x82, 0xbc, 0x5e, 0x87, 0x92, 0x84, 0x60, 0x09,
+	0x4e, 0xce, 0xfe, 0xeb, 0x99, 0xef, 0x9f, 0x19, 0xef, 0x06, 0x5a, 0x71, 0xc2, 0x25, 0x77, 0x87,
+	0x3c, 0x8c, 0x69, 0x74, 0x9d, 0x3f, 0x1d, 0xa5, 0x92, 0xcd, 0x51, 0x12, 0x0f, 0x9d, 0x11, 0x95,
+	0x78, 0x45, 0xaf, 0x1d, 0xbd, 0xd7, 0xdc, 0x1e, 0x71, 0x3e, 0x0a, 0xd0, 0xa5, 0x31, 0x73, 0x69,
+	0x14, 0x71, 0x49, 0x25, 0xe3, 0x91, 0xc8, 0x62, 0x9a, 0x0f, 0x27, 0x09, 0x43, 0x1e, 0xe9, 0x87,
+	0xde, 0x6a, 0xe9, 0x40, 0xb5, 0xba, 0x18, 0xbf, 0x73, 0x31, 0x8c, 0xa5, 0x66, 0xd9, 0x67, 0xb0,
+	0xdc, 0xcb, 0x00, 0x64, 0x0d, 0x4a, 0xcc, 0xb7, 0x8c, 0xb6, 0xd1, 0x31, 0xbd, 0x12, 0xf3, 0xc9,
+	0x0e, 0x00, 0x13, 0x03, 0x8c, 0xe8, 0x45, 0x80, 0xbe, 0x55, 0x6a, 0x1b, 0x9d, 0x15, 0xcf, 0x64,
+	0xe2, 0x65, 0x26, 0x10, 0x02, 0x95, 0x88, 0x86, 0x68, 0x95, 0x55, 0x80, 0xfa, 0x6d, 0xdf, 0x19,
+	0xb0, 0xd1, 0xf7, 0x31, 0x92, 0x4c, 0x5e, 0xbf, 0x4e, 0xf8, 0x25, 0xf3, 0x31, 0x49, 0xf3, 0xe8,
+	0x1a, 0x06, 0x93, 0xfc, 0xa6, 0x56, 0xfa, 0x3e, 0xd9, 0x82, 0x2a, 0x13, 0x62, 0x8c, 0x89, 0x42,
+	0x98, 0x9e, 0x5e, 0x91, 0x16, 0x98, 0xc3, 0x80, 0x61, 0x24, 0xd3, 0xa8, 0x0c, 0xb2, 0x92, 0x09,
+	0x7d, 0x9f, 0xec, 0x43, 0x5d, 0x6f, 0x0a, 0x1c, 0x26, 0x28, 0xad, 0x8a, 0x7a, 0x61, 0x35, 0x13,
+	0xcf, 0x95, 0x46, 0x0e, 0x60, 0x9d, 0x06, 0x01, 0xbf, 0x42, 0x7f, 0xe0, 0xf3, 0x90, 0xb2, 0x48,
+	0x58, 0x4b, 0xed, 0x72, 0xc7, 0xf4, 0xd6, 0xb4, 0x7c, 0x92, 0xa9, 0x53, 0x95, 0x56, 0xa7, 0x2a,
+	0xb5, 0xbf, 0x18, 0x60, 0x4d, 0x57, 0xe5, 0xa1, 0x88, 0x79, 0x24, 0x90, 0x3c, 0x87, 0x4a, 0x88,
+	0x92, 0xaa, 0xba, 0x6a, 0xdd, 0x3d, 0x67, 0x7a, 0x76, 0xe9, 0x1c, 0x5e, 0xa1, 0xa4, 0x79, 0x80,
+	0xa7, 0x5e, 0x27, 0xc7, 0x50, 0xf1, 0xa9, 0xa4, 0xaa, 0xe6, 0x5a, 0xf7, 0x89, 0x33, 0x6f, 0xe4,
+	0xce, 0x0c, 0x54, 0xc5, 0xd8, 0xdf, 0x0c, 0x68, 0xe8, 0xa1, 0x9d, 0x31, 0x21, 0xff, 0xd5, 0xca,
+	0xd1, 0xc4, 0x4a, 0xb9, 0x53, 0xeb, 0xee, 0xcc, 0xb7, 0xa2, 0x79, 0x99, 0x03, 0xb2, 0x0b, 0xb5,
+	0x08, 0x3f, 0xc8, 0xc1, 0x70, 0x9c, 0x08, 0x9e, 0xe8, 0xe9, 0x40, 0x2a, 0xf5, 0x94, 0x42, 0x36,
+	0x61, 0x49, 0x72, 0x49, 0x03, 0x35, 0x97, 0xb2, 0x97, 0x2d, 0xec, 0x1b, 0x58, 0xcf, 0xf3, 0xfc,
+	0x37, 0xcf, 0x46, 0x41, 0xcf, 0xdd, 0xef, 0x55, 0x58, 0xd3, 0xca, 0x39, 0x26, 0x97, 0x6c, 0x88,
+	0x64, 0x04, 0xf5, 0x5e, 0x82, 0x54, 0x62, 0x7e, 0x04, 0x16, 0x27, 0x6a, 0xee, 0xce, 0xb5, 0xd7,
+	0x3f, 0xc9, 0xcd, 0xd9, 0x5b, 0x1f, 0xef, 0x7e, 0x7c, 0x2d, 0x6d, 0xd8, 0x35, 0xf7, 0xf2, 0x28,
+	0x3f, 0xd4, 0xc7, 0xc6, 0x21, 0x89, 0xa1, 0xfe, 0x26, 0xf6, 0x8b, 0x83, 0xf6, 0xe7, 0x82, 0x7a,
+	0xea, 0x31, 0x81, 0xb5, 0x14, 0xec, 0x81, 0xbd, 0x71, 0x0f, 0xe6, 0xde, 0x30, 0xff, 0x36, 0x25,
+	0x86, 0x00, 0xa7, 0x28, 0x73, 0xdc, 0xa3, 0x3f, 0x1a, 0x7f, 0x3f, 0x46, 0x21, 0x9b, 0x8f, 0x17,
+	0x37, 0x30, 0x27, 0x5a, 0x8a, 0x48, 0xc8, 0x0c, 0x91, 0xc4, 0xb0, 0x3a, 0xc1, 0x31, 0x14, 0xa4,
+	0x3d, 0x17, 0x98, 0x7d, 0xad, 0x19, 0xf2, 0xe9, 0x42, 0xe4, 0xfd, 0xef, 0xda, 0x6e, 0x28, 0x6c,
+	0x9d, 0xdc, 0xef, 0x2a, 0x89, 0xa0, 0x7e, 0x82, 0x01, 0x4a, 0x2c, 0x5a, 0x63, 0xa1, 0x9e, 0xea,
+	0x0a, 0x0f, 0x67, 0x2b, 0xfc, 0x6c, 0x40, 0xe3, 0x1c, 0xe5, 0xcc, 0xed, 0x56, 0xf0, 0xe8, 0x16,
+	0xc3, 0x1f, 0x28, 0xfc, 0x9e, 0xbd, 0xfd, 0x1b, 0xfe, 0xd7, 0xed, 0x79, 0xeb, 0x0a, 0xc1, 0xd3,
+	0xf1, 0x7e, 0x32, 0xa0, 0x71, 0x3a, 0xc7, 0xcd, 0xdf, 0x9a, 0xe0, 0x14, 0xbc, 0x68, 0x72, 0x43,
+	0xdb, 0xca, 0xd0, 0x16, 0xd9, 0x9c, 0xee, 0x47, 0x6a, 0xe4, 0x85, 0xf9, 0x76, 0x59, 0x6b, 0x17,
+	0x55, 0xf5, 0x77, 0xf2, 0xec, 0xe7, 0x00, 0xae, 0x92, 0x34, 0x93, 0xd9, 0x06, 0x00, 0x00,
 }
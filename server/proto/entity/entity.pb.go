@@ -13,7 +13,14 @@ It has these top-level messages:
 	Entity
 	EntityListResponse
 	EntityResponse
+	PurgeEntitiesResponse
+	EntitySearchRequest
 	EntityListRequest
+	EntityImportColumn
+	EntityImportRequest
+	EntityImportError
+	EntityImport
+	EntityImportResponse
 */
 package entity
 
@@ -22,6 +29,7 @@ import fmt "fmt"
 import math "math"
 import _ "github.com/grpc-ecosystem/grpc-gateway/third_party/googleapis/google/api"
 import grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
 
 import (
 	context "golang.org/x/net/context"
@@ -102,6 +110,9 @@ type Entity struct {
 	Proxyholders        *EntityLink                  `protobuf:"bytes,40,opt,name=proxyholders" json:"proxyholders"`
 	Trustees            *EntityLink                  `protobuf:"bytes,41,opt,name=trustees" json:"trustees"`
 	Shareholders        *EntityLink                  `protobuf:"bytes,42,opt,name=shareholders" json:"shareholders"`
+	IsDeleted           bool                         `protobuf:"varint,43,opt,name=is_deleted,json=isDeleted" json:"is_deleted"`
+	DeletedAt           int64                        `protobuf:"varint,44,opt,name=deleted_at,json=deletedAt" json:"deleted_at"`
+	DeletedBy           string                       `protobuf:"bytes,45,opt,name=deleted_by,json=deletedBy" json:"deleted_by"`
 }
 
 func (m *Entity) Reset()                    { *m = Entity{} }
@@ -375,9 +386,32 @@ func (m *Entity) GetShareholders() *EntityLink {
 	return nil
 }
 
+func (m *Entity) GetIsDeleted() bool {
+	if m != nil {
+		return m.IsDeleted
+	}
+	return false
+}
+
+func (m *Entity) GetDeletedAt() int64 {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return 0
+}
+
+func (m *Entity) GetDeletedBy() string {
+	if m != nil {
+		return m.DeletedBy
+	}
+	return ""
+}
+
 type EntityListResponse struct {
-	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
-	Data []*Entity                         `protobuf:"bytes,2,rep,name=data" json:"data"`
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*Entity                         `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
 }
 
 func (m *EntityListResponse) Reset()                    { *m = EntityListResponse{} }
@@ -399,6 +433,20 @@ func (m *EntityListResponse) GetData() []*Entity {
 	return nil
 }
 
+func (m *EntityListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *EntityListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
 type EntityResponse struct {
 	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
 	Data *Entity                           `protobuf:"bytes,2,opt,name=data" json:"data"`
@@ -423,16 +471,154 @@ func (m *EntityResponse) GetData() *Entity {
 	return nil
 }
 
+type PurgeEntitiesResponse struct {
+	Meta   *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Purged int64                             `protobuf:"varint,2,opt,name=purged" json:"purged"`
+}
+
+func (m *PurgeEntitiesResponse) Reset()                    { *m = PurgeEntitiesResponse{} }
+func (m *PurgeEntitiesResponse) String() string            { return proto.CompactTextString(m) }
+func (*PurgeEntitiesResponse) ProtoMessage()               {}
+func (*PurgeEntitiesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *PurgeEntitiesResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *PurgeEntitiesResponse) GetPurged() int64 {
+	if m != nil {
+		return m.Purged
+	}
+	return 0
+}
+
+type EntitySearchRequest struct {
+	Query       string `protobuf:"bytes,1,opt,name=query" json:"query"`
+	Type        string `protobuf:"bytes,2,opt,name=type" json:"type"`
+	Kvk         string `protobuf:"bytes,3,opt,name=kvk" json:"kvk"`
+	Rsin        string `protobuf:"bytes,4,opt,name=rsin" json:"rsin"`
+	BfiNumber   string `protobuf:"bytes,5,opt,name=bfi_number,json=bfiNumber" json:"bfi_number"`
+	Nationality string `protobuf:"bytes,6,opt,name=nationality" json:"nationality"`
+	LegalForm   string `protobuf:"bytes,7,opt,name=legal_form,json=legalForm" json:"legal_form"`
+	Country     string `protobuf:"bytes,8,opt,name=country" json:"country"`
+	CreatedFrom int64  `protobuf:"varint,9,opt,name=created_from,json=createdFrom" json:"created_from"`
+	CreatedTo   int64  `protobuf:"varint,10,opt,name=created_to,json=createdTo" json:"created_to"`
+	Cursor      string `protobuf:"bytes,11,opt,name=cursor" json:"cursor"`
+	Limit       int64  `protobuf:"varint,12,opt,name=limit" json:"limit"`
+	Sort        string `protobuf:"bytes,13,opt,name=sort" json:"sort"`
+}
+
+func (m *EntitySearchRequest) Reset()                    { *m = EntitySearchRequest{} }
+func (m *EntitySearchRequest) String() string            { return proto.CompactTextString(m) }
+func (*EntitySearchRequest) ProtoMessage()               {}
+func (*EntitySearchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *EntitySearchRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetKvk() string {
+	if m != nil {
+		return m.Kvk
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetRsin() string {
+	if m != nil {
+		return m.Rsin
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetBfiNumber() string {
+	if m != nil {
+		return m.BfiNumber
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetNationality() string {
+	if m != nil {
+		return m.Nationality
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetLegalForm() string {
+	if m != nil {
+		return m.LegalForm
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetCountry() string {
+	if m != nil {
+		return m.Country
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetCreatedFrom() int64 {
+	if m != nil {
+		return m.CreatedFrom
+	}
+	return 0
+}
+
+func (m *EntitySearchRequest) GetCreatedTo() int64 {
+	if m != nil {
+		return m.CreatedTo
+	}
+	return 0
+}
+
+func (m *EntitySearchRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *EntitySearchRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *EntitySearchRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
 type EntityListRequest struct {
-	Type  string `protobuf:"bytes,1,opt,name=type" json:"type"`
-	Page  int64  `protobuf:"varint,2,opt,name=page" json:"page"`
-	Limit int64  `protobuf:"varint,3,opt,name=limit" json:"limit"`
+	Type   string `protobuf:"bytes,1,opt,name=type" json:"type"`
+	Page   int64  `protobuf:"varint,2,opt,name=page" json:"page"`
+	Limit  int64  `protobuf:"varint,3,opt,name=limit" json:"limit"`
+	Cursor string `protobuf:"bytes,4,opt,name=cursor" json:"cursor"`
+	Sort   string `protobuf:"bytes,5,opt,name=sort" json:"sort"`
 }
 
 func (m *EntityListRequest) Reset()                    { *m = EntityListRequest{} }
 func (m *EntityListRequest) String() string            { return proto.CompactTextString(m) }
 func (*EntityListRequest) ProtoMessage()               {}
-func (*EntityListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*EntityListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *EntityListRequest) GetType() string {
 	if m != nil {
@@ -455,12 +641,297 @@ func (m *EntityListRequest) GetLimit() int64 {
 	return 0
 }
 
+func (m *EntityListRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *EntityListRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+type EntityImportColumn struct {
+	Column string `protobuf:"bytes,1,opt,name=column" json:"column"`
+	Field  string `protobuf:"bytes,2,opt,name=field" json:"field"`
+}
+
+func (m *EntityImportColumn) Reset()                    { *m = EntityImportColumn{} }
+func (m *EntityImportColumn) String() string            { return proto.CompactTextString(m) }
+func (*EntityImportColumn) ProtoMessage()               {}
+func (*EntityImportColumn) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *EntityImportColumn) GetColumn() string {
+	if m != nil {
+		return m.Column
+	}
+	return ""
+}
+
+func (m *EntityImportColumn) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+type EntityImportRequest struct {
+	FileName string                `protobuf:"bytes,1,opt,name=file_name,json=fileName" json:"file_name"`
+	Format   string                `protobuf:"bytes,2,opt,name=format" json:"format"`
+	Content  []byte                `protobuf:"bytes,3,opt,name=content" json:"content"`
+	Mapping  []*EntityImportColumn `protobuf:"bytes,4,rep,name=mapping" json:"mapping"`
+	Mode     string                `protobuf:"bytes,5,opt,name=mode" json:"mode"`
+}
+
+func (m *EntityImportRequest) Reset()                    { *m = EntityImportRequest{} }
+func (m *EntityImportRequest) String() string            { return proto.CompactTextString(m) }
+func (*EntityImportRequest) ProtoMessage()               {}
+func (*EntityImportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *EntityImportRequest) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+func (m *EntityImportRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *EntityImportRequest) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *EntityImportRequest) GetMapping() []*EntityImportColumn {
+	if m != nil {
+		return m.Mapping
+	}
+	return nil
+}
+
+func (m *EntityImportRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+type EntityImportError struct {
+	Row     int64  `protobuf:"varint,1,opt,name=row" json:"row"`
+	Column  string `protobuf:"bytes,2,opt,name=column" json:"column"`
+	Field   string `protobuf:"bytes,3,opt,name=field" json:"field"`
+	Message string `protobuf:"bytes,4,opt,name=message" json:"message"`
+}
+
+func (m *EntityImportError) Reset()                    { *m = EntityImportError{} }
+func (m *EntityImportError) String() string            { return proto.CompactTextString(m) }
+func (*EntityImportError) ProtoMessage()               {}
+func (*EntityImportError) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *EntityImportError) GetRow() int64 {
+	if m != nil {
+		return m.Row
+	}
+	return 0
+}
+
+func (m *EntityImportError) GetColumn() string {
+	if m != nil {
+		return m.Column
+	}
+	return ""
+}
+
+func (m *EntityImportError) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *EntityImportError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type EntityImport struct {
+	Id           string               `protobuf:"bytes,1,opt,name=id" json:"id"`
+	CompanyId    string               `protobuf:"bytes,2,opt,name=company_id,json=companyId" json:"company_id"`
+	CreatedBy    string               `protobuf:"bytes,3,opt,name=created_by,json=createdBy" json:"created_by"`
+	CreatedAt    int64                `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"created_at"`
+	FinishedAt   int64                `protobuf:"varint,5,opt,name=finished_at,json=finishedAt" json:"finished_at"`
+	FileName     string               `protobuf:"bytes,6,opt,name=file_name,json=fileName" json:"file_name"`
+	Format       string               `protobuf:"bytes,7,opt,name=format" json:"format"`
+	Mode         string               `protobuf:"bytes,8,opt,name=mode" json:"mode"`
+	Status       string               `protobuf:"bytes,9,opt,name=status" json:"status"`
+	TotalRows    int64                `protobuf:"varint,10,opt,name=total_rows,json=totalRows" json:"total_rows"`
+	InvalidRows  int64                `protobuf:"varint,11,opt,name=invalid_rows,json=invalidRows" json:"invalid_rows"`
+	ImportedRows int64                `protobuf:"varint,12,opt,name=imported_rows,json=importedRows" json:"imported_rows"`
+	Errors       []*EntityImportError `protobuf:"bytes,13,rep,name=errors" json:"errors"`
+	Error        string               `protobuf:"bytes,14,opt,name=error" json:"error"`
+	UpdatedAt    int64                `protobuf:"varint,15,opt,name=updated_at,json=updatedAt" json:"updated_at"`
+}
+
+func (m *EntityImport) Reset()                    { *m = EntityImport{} }
+func (m *EntityImport) String() string            { return proto.CompactTextString(m) }
+func (*EntityImport) ProtoMessage()               {}
+func (*EntityImport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *EntityImport) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *EntityImport) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *EntityImport) GetCreatedBy() string {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return ""
+}
+
+func (m *EntityImport) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *EntityImport) GetFinishedAt() int64 {
+	if m != nil {
+		return m.FinishedAt
+	}
+	return 0
+}
+
+func (m *EntityImport) GetFileName() string {
+	if m != nil {
+		return m.FileName
+	}
+	return ""
+}
+
+func (m *EntityImport) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *EntityImport) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *EntityImport) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *EntityImport) GetTotalRows() int64 {
+	if m != nil {
+		return m.TotalRows
+	}
+	return 0
+}
+
+func (m *EntityImport) GetInvalidRows() int64 {
+	if m != nil {
+		return m.InvalidRows
+	}
+	return 0
+}
+
+func (m *EntityImport) GetImportedRows() int64 {
+	if m != nil {
+		return m.ImportedRows
+	}
+	return 0
+}
+
+func (m *EntityImport) GetErrors() []*EntityImportError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *EntityImport) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *EntityImport) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+type EntityImportResponse struct {
+	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data *EntityImport                     `protobuf:"bytes,2,opt,name=data" json:"data"`
+}
+
+func (m *EntityImportResponse) Reset()                    { *m = EntityImportResponse{} }
+func (m *EntityImportResponse) String() string            { return proto.CompactTextString(m) }
+func (*EntityImportResponse) ProtoMessage()               {}
+func (*EntityImportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *EntityImportResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *EntityImportResponse) GetData() *EntityImport {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*EntityLink)(nil), "grpc.gateway.entity.EntityLink")
 	proto.RegisterType((*Entity)(nil), "grpc.gateway.entity.Entity")
 	proto.RegisterType((*EntityListResponse)(nil), "grpc.gateway.entity.EntityListResponse")
 	proto.RegisterType((*EntityResponse)(nil), "grpc.gateway.entity.EntityResponse")
+	proto.RegisterType((*PurgeEntitiesResponse)(nil), "grpc.gateway.entity.PurgeEntitiesResponse")
+	proto.RegisterType((*EntitySearchRequest)(nil), "grpc.gateway.entity.EntitySearchRequest")
 	proto.RegisterType((*EntityListRequest)(nil), "grpc.gateway.entity.EntityListRequest")
+	proto.RegisterType((*EntityImportColumn)(nil), "grpc.gateway.entity.EntityImportColumn")
+	proto.RegisterType((*EntityImportRequest)(nil), "grpc.gateway.entity.EntityImportRequest")
+	proto.RegisterType((*EntityImportError)(nil), "grpc.gateway.entity.EntityImportError")
+	proto.RegisterType((*EntityImport)(nil), "grpc.gateway.entity.EntityImport")
+	proto.RegisterType((*EntityImportResponse)(nil), "grpc.gateway.entity.EntityImportResponse")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -478,7 +949,14 @@ type EntityServiceClient interface {
 	UpdateEntity(ctx context.Context, in *Entity, opts ...grpc.CallOption) (*EntityResponse, error)
 	GetEntities(ctx context.Context, in *EntityListRequest, opts ...grpc.CallOption) (*EntityListResponse, error)
 	GetLatestEntity(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityResponse, error)
-	GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityListResponse, error)
+	GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*EntityListResponse, error)
+	DeleteEntity(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityResponse, error)
+	RestoreEntity(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityResponse, error)
+	ListDeletedEntities(ctx context.Context, in *EntityListRequest, opts ...grpc.CallOption) (*EntityListResponse, error)
+	PurgeDeletedEntities(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*PurgeEntitiesResponse, error)
+	SearchEntities(ctx context.Context, in *EntitySearchRequest, opts ...grpc.CallOption) (*EntityListResponse, error)
+	ImportEntities(ctx context.Context, in *EntityImportRequest, opts ...grpc.CallOption) (*EntityImportResponse, error)
+	GetEntityImport(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityImportResponse, error)
 }
 
 type entityServiceClient struct {
@@ -525,7 +1003,7 @@ func (c *entityServiceClient) GetLatestEntity(ctx context.Context, in *grpc_gate
 	return out, nil
 }
 
-func (c *entityServiceClient) GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityListResponse, error) {
+func (c *entityServiceClient) GetEntityRevisions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*EntityListResponse, error) {
 	out := new(EntityListResponse)
 	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/GetEntityRevisions", in, out, c.cc, opts...)
 	if err != nil {
@@ -534,6 +1012,69 @@ func (c *entityServiceClient) GetEntityRevisions(ctx context.Context, in *grpc_g
 	return out, nil
 }
 
+func (c *entityServiceClient) DeleteEntity(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
+	out := new(EntityResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/DeleteEntity", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) RestoreEntity(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityResponse, error) {
+	out := new(EntityResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/RestoreEntity", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) ListDeletedEntities(ctx context.Context, in *EntityListRequest, opts ...grpc.CallOption) (*EntityListResponse, error) {
+	out := new(EntityListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/ListDeletedEntities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) PurgeDeletedEntities(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*PurgeEntitiesResponse, error) {
+	out := new(PurgeEntitiesResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/PurgeDeletedEntities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) SearchEntities(ctx context.Context, in *EntitySearchRequest, opts ...grpc.CallOption) (*EntityListResponse, error) {
+	out := new(EntityListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/SearchEntities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) ImportEntities(ctx context.Context, in *EntityImportRequest, opts ...grpc.CallOption) (*EntityImportResponse, error) {
+	out := new(EntityImportResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/ImportEntities", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *entityServiceClient) GetEntityImport(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*EntityImportResponse, error) {
+	out := new(EntityImportResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.entity.EntityService/GetEntityImport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for EntityService service
 
 type EntityServiceServer interface {
@@ -541,7 +1082,14 @@ type EntityServiceServer interface {
 	UpdateEntity(context.Context, *Entity) (*EntityResponse, error)
 	GetEntities(context.Context, *EntityListRequest) (*EntityListResponse, error)
 	GetLatestEntity(context.Context, *grpc_gateway_common.IDRequest) (*EntityResponse, error)
-	GetEntityRevisions(context.Context, *grpc_gateway_common.IDRequest) (*EntityListResponse, error)
+	GetEntityRevisions(context.Context, *grpc_gateway_common.ListRequest) (*EntityListResponse, error)
+	DeleteEntity(context.Context, *grpc_gateway_common.IDRequest) (*EntityResponse, error)
+	RestoreEntity(context.Context, *grpc_gateway_common.IDRequest) (*EntityResponse, error)
+	ListDeletedEntities(context.Context, *EntityListRequest) (*EntityListResponse, error)
+	PurgeDeletedEntities(context.Context, *google_protobuf1.Empty) (*PurgeEntitiesResponse, error)
+	SearchEntities(context.Context, *EntitySearchRequest) (*EntityListResponse, error)
+	ImportEntities(context.Context, *EntityImportRequest) (*EntityImportResponse, error)
+	GetEntityImport(context.Context, *grpc_gateway_common.IDRequest) (*EntityImportResponse, error)
 }
 
 func RegisterEntityServiceServer(s *grpc.Server, srv EntityServiceServer) {
@@ -621,7 +1169,7 @@ func _EntityService_GetLatestEntity_Handler(srv interface{}, ctx context.Context
 }
 
 func _EntityService_GetEntityRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(grpc_gateway_common.IDRequest)
+	in := new(grpc_gateway_common.ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -633,7 +1181,133 @@ func _EntityService_GetEntityRevisions_Handler(srv interface{}, ctx context.Cont
 		FullMethod: "/grpc.gateway.entity.EntityService/GetEntityRevisions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EntityServiceServer).GetEntityRevisions(ctx, req.(*grpc_gateway_common.IDRequest))
+		return srv.(EntityServiceServer).GetEntityRevisions(ctx, req.(*grpc_gateway_common.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_DeleteEntity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).DeleteEntity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/DeleteEntity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).DeleteEntity(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_RestoreEntity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).RestoreEntity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/RestoreEntity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).RestoreEntity(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_ListDeletedEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntityListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).ListDeletedEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/ListDeletedEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).ListDeletedEntities(ctx, req.(*EntityListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_PurgeDeletedEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).PurgeDeletedEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/PurgeDeletedEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).PurgeDeletedEntities(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_SearchEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntitySearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).SearchEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/SearchEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).SearchEntities(ctx, req.(*EntitySearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_ImportEntities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntityImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).ImportEntities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/ImportEntities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).ImportEntities(ctx, req.(*EntityImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EntityService_GetEntityImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntityServiceServer).GetEntityImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.entity.EntityService/GetEntityImport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntityServiceServer).GetEntityImport(ctx, req.(*grpc_gateway_common.IDRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -662,6 +1336,34 @@ var _EntityService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetEntityRevisions",
 			Handler:    _EntityService_GetEntityRevisions_Handler,
 		},
+		{
+			MethodName: "DeleteEntity",
+			Handler:    _EntityService_DeleteEntity_Handler,
+		},
+		{
+			MethodName: "RestoreEntity",
+			Handler:    _EntityService_RestoreEntity_Handler,
+		},
+		{
+			MethodName: "ListDeletedEntities",
+			Handler:    _EntityService_ListDeletedEntities_Handler,
+		},
+		{
+			MethodName: "PurgeDeletedEntities",
+			Handler:    _EntityService_PurgeDeletedEntities_Handler,
+		},
+		{
+			MethodName: "SearchEntities",
+			Handler:    _EntityService_SearchEntities_Handler,
+		},
+		{
+			MethodName: "ImportEntities",
+			Handler:    _EntityService_ImportEntities_Handler,
+		},
+		{
+			MethodName: "GetEntityImport",
+			Handler:    _EntityService_GetEntityImport_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/entity/entity.proto",
@@ -670,71 +1372,115 @@ var _EntityService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("proto/entity/entity.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1042 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcd, 0x6e, 0x1b, 0x37,
-	0x10, 0x86, 0x2c, 0x45, 0x95, 0x46, 0xb2, 0x65, 0xd3, 0x3f, 0x61, 0x64, 0x27, 0x56, 0x95, 0x26,
-	0x56, 0x53, 0x40, 0x6a, 0x5d, 0xf4, 0xd2, 0xa2, 0x07, 0xdb, 0x4d, 0x0d, 0xa3, 0xf9, 0x69, 0x37,
-	0xc8, 0xa5, 0x97, 0x05, 0xa5, 0xe5, 0xca, 0x84, 0xf7, 0xaf, 0x24, 0xa5, 0x7a, 0x91, 0xf6, 0xd2,
-	0x57, 0xe8, 0x4b, 0xf5, 0x58, 0xa0, 0xaf, 0xd0, 0x07, 0x29, 0x38, 0xe4, 0x4a, 0xeb, 0xc2, 0x70,
-	0x74, 0xc8, 0x49, 0xe4, 0x37, 0xdf, 0xcc, 0x37, 0x33, 0x1c, 0x91, 0x0b, 0x0f, 0x32, 0x99, 0xea,
-	0x74, 0xc4, 0x13, 0x2d, 0x74, 0xee, 0x7e, 0x86, 0x88, 0x91, 0xed, 0xa9, 0xcc, 0x26, 0xc3, 0x29,
-	0xd3, 0xfc, 0x57, 0x96, 0x0f, 0xad, 0xa9, 0x7b, 0x30, 0x4d, 0xd3, 0x69, 0xc4, 0x47, 0x2c, 0x13,
-	0x23, 0x96, 0x24, 0xa9, 0x66, 0x5a, 0xa4, 0x89, 0xb2, 0x2e, 0x5d, 0x17, 0x6d, 0x92, 0xc6, 0x71,
-	0x9a, 0xb8, 0x1f, 0x6b, 0xea, 0x9f, 0x00, 0x3c, 0xc7, 0x10, 0x2f, 0x44, 0x72, 0x45, 0xf6, 0xa1,
-	0x69, 0x03, 0xfa, 0x22, 0xa0, 0x95, 0x5e, 0x65, 0xd0, 0xf4, 0x1a, 0x16, 0xb8, 0x08, 0xc8, 0x1e,
-	0xd4, 0x59, 0x9c, 0xce, 0x12, 0x4d, 0xd7, 0xd0, 0xe2, 0x76, 0xfd, 0xbf, 0x5b, 0x50, 0xb7, 0x31,
-	0xc8, 0x06, 0xac, 0x2d, 0x1c, 0xd7, 0x44, 0x40, 0x0e, 0xa1, 0x65, 0xd5, 0xfc, 0x84, 0xc5, 0xdc,
-	0xf9, 0x81, 0x85, 0x5e, 0xb1, 0x98, 0x93, 0x87, 0x60, 0x76, 0x19, 0x4b, 0x50, 0xb1, 0x8a, 0xf6,
-	0xa6, 0x43, 0x2e, 0x02, 0xb2, 0x09, 0x55, 0xc9, 0xe7, 0xb4, 0xd6, 0xab, 0x0c, 0xaa, 0x9e, 0x59,
-	0x9a, 0x24, 0x22, 0xa6, 0xb9, 0xd2, 0xf4, 0x5e, 0xaf, 0x32, 0x68, 0x78, 0x6e, 0x47, 0x86, 0xb0,
-	0x3d, 0x91, 0x9c, 0x69, 0x1e, 0xf8, 0xe3, 0xdc, 0x9f, 0x29, 0x2e, 0x51, 0xb1, 0x8e, 0x11, 0xb7,
-	0x9c, 0xe9, 0x34, 0x7f, 0xeb, 0x0c, 0x28, 0xec, 0xf8, 0x4c, 0xd3, 0x8f, 0x50, 0xa0, 0xe9, 0x90,
-	0x13, 0x5d, 0x36, 0x8f, 0x73, 0xda, 0x70, 0x79, 0x15, 0x51, 0x08, 0x81, 0x9a, 0xce, 0x33, 0x4e,
-	0x9b, 0x68, 0xc0, 0xb5, 0x71, 0x99, 0x8a, 0x39, 0x77, 0xa5, 0x82, 0x75, 0x41, 0x04, 0x2b, 0x3d,
-	0x84, 0x56, 0x2c, 0x82, 0x20, 0xe2, 0xd6, 0xde, 0xb2, 0xad, 0xb0, 0x50, 0x41, 0x08, 0x59, 0x2c,
-	0xa2, 0xdc, 0x12, 0xda, 0x96, 0x60, 0xa1, 0x82, 0x60, 0x2c, 0x7e, 0x26, 0x79, 0x28, 0xae, 0xe9,
-	0xba, 0x25, 0x18, 0xe8, 0x47, 0x44, 0x16, 0x04, 0x35, 0x0b, 0x0d, 0x61, 0x63, 0x49, 0x78, 0x83,
-	0x88, 0x69, 0xde, 0x94, 0x27, 0x01, 0x97, 0xb4, 0x63, 0x4f, 0xd0, 0xee, 0x48, 0x17, 0x1a, 0x63,
-	0x21, 0xf5, 0x65, 0xc0, 0x72, 0xba, 0x69, 0x4f, 0xbd, 0xd8, 0x93, 0x47, 0x00, 0xb8, 0xce, 0x22,
-	0x36, 0xe1, 0x74, 0xcb, 0xc6, 0x5c, 0x22, 0xa4, 0x0f, 0x6d, 0xdc, 0x4d, 0xcc, 0x2c, 0xc8, 0x9c,
-	0x12, 0x64, 0xdc, 0xc0, 0x48, 0xcf, 0x24, 0x66, 0x06, 0x92, 0x45, 0x42, 0xe7, 0x74, 0x1b, 0x29,
-	0x65, 0x88, 0xbc, 0x84, 0x6d, 0xc9, 0x95, 0x08, 0xcc, 0xb0, 0xb1, 0xc8, 0x67, 0x41, 0x20, 0xb9,
-	0x52, 0x74, 0xa7, 0x57, 0x19, 0xb4, 0x8e, 0x0f, 0x86, 0x37, 0x46, 0xde, 0xcd, 0xef, 0x89, 0xe5,
-	0x78, 0xa4, 0xe4, 0xe8, 0x30, 0x33, 0x37, 0x57, 0xf3, 0x2b, 0xba, 0x8b, 0x42, 0x66, 0x69, 0x4e,
-	0x27, 0xe2, 0x53, 0x16, 0xf9, 0x61, 0x2a, 0x63, 0xba, 0x67, 0x4f, 0x07, 0x91, 0xef, 0x53, 0x19,
-	0x93, 0x23, 0xe8, 0x48, 0x3e, 0x15, 0x4a, 0x73, 0xc9, 0x03, 0x7b, 0x00, 0xf7, 0x91, 0xb3, 0xb1,
-	0x84, 0xf1, 0x10, 0x3e, 0x83, 0xad, 0x12, 0x31, 0x0d, 0x43, 0x31, 0xe1, 0x94, 0x22, 0x75, 0x73,
-	0x69, 0x78, 0x8d, 0x38, 0xf9, 0x1c, 0x76, 0x02, 0xa6, 0xb9, 0x9f, 0x86, 0xbe, 0xb5, 0x49, 0x2c,
-	0x99, 0x3e, 0x40, 0x3e, 0x31, 0xb6, 0xd7, 0xa1, 0x57, 0xb2, 0x90, 0x63, 0xd8, 0x2d, 0x3c, 0xb8,
-	0xd2, 0x6c, 0x1c, 0x09, 0x75, 0x19, 0xf3, 0x44, 0xd3, 0x2e, 0xba, 0x6c, 0x5b, 0x97, 0xe7, 0x65,
-	0x93, 0x29, 0x4d, 0x4b, 0x16, 0xb8, 0xc1, 0xda, 0xb7, 0xa5, 0x21, 0x82, 0x19, 0x9f, 0xc3, 0xe6,
-	0x5c, 0x28, 0xa1, 0x45, 0x32, 0x5d, 0xf4, 0xf5, 0x60, 0x85, 0xbe, 0x76, 0x0a, 0xaf, 0xa2, 0xa9,
-	0x3f, 0x00, 0x29, 0x95, 0x5e, 0x84, 0x7a, 0xb8, 0x42, 0xa8, 0x52, 0xcb, 0x8a, 0x60, 0x04, 0x6a,
-	0x52, 0x89, 0x84, 0xf6, 0xed, 0x3f, 0xc8, 0xac, 0xc9, 0x13, 0xd8, 0x10, 0x4a, 0xcd, 0x78, 0xe0,
-	0x4f, 0x58, 0x26, 0x34, 0x8b, 0xe8, 0x63, 0xb4, 0xae, 0x5b, 0xf4, 0xcc, 0x82, 0x86, 0x96, 0x31,
-	0x11, 0xcc, 0xb2, 0x05, 0xed, 0x13, 0x4b, 0xb3, 0x68, 0x41, 0xdb, 0x85, 0xba, 0x50, 0xfe, 0x38,
-	0x14, 0xf4, 0x09, 0xde, 0x14, 0xf7, 0x84, 0x3a, 0x0d, 0x85, 0xe9, 0xd6, 0x38, 0x14, 0x7e, 0x32,
-	0x8b, 0xc7, 0x5c, 0xd2, 0xa7, 0xb6, 0x5b, 0xe3, 0x50, 0xbc, 0x42, 0x80, 0x7c, 0x0b, 0xcd, 0x40,
-	0x48, 0x3e, 0xd1, 0xa9, 0x54, 0xf4, 0x08, 0x6b, 0x3b, 0x1c, 0xde, 0x72, 0xe3, 0x0e, 0x97, 0xb7,
-	0xa6, 0xb7, 0xf4, 0x20, 0x67, 0xd0, 0xce, 0x64, 0x7a, 0x9d, 0x5f, 0xa6, 0x51, 0xc0, 0xa5, 0xa2,
-	0x83, 0xd5, 0x22, 0xdc, 0x70, 0x22, 0xdf, 0x40, 0x43, 0xcb, 0x99, 0xd2, 0x9c, 0x2b, 0xfa, 0xe9,
-	0x6a, 0x01, 0x16, 0x0e, 0x26, 0x03, 0x75, 0xc9, 0x24, 0x2f, 0x32, 0x78, 0xb6, 0x62, 0x06, 0x65,
-	0xa7, 0xfe, 0x6f, 0x40, 0x0a, 0x9b, 0xd2, 0x1e, 0x57, 0x59, 0x9a, 0x28, 0x4e, 0xbe, 0x82, 0x5a,
-	0xcc, 0x35, 0xc3, 0xfb, 0xbd, 0x75, 0xfc, 0xf1, 0xad, 0x47, 0xfe, 0x92, 0x6b, 0x56, 0x38, 0x78,
-	0x48, 0x27, 0x23, 0xa8, 0x05, 0x4c, 0x33, 0xba, 0xd6, 0xab, 0x0e, 0x5a, 0xc7, 0xfb, 0x77, 0x64,
-	0xe2, 0x21, 0xb1, 0x7f, 0x0d, 0x1b, 0x6e, 0xff, 0xc1, 0x94, 0x2b, 0xab, 0x29, 0xff, 0x04, 0x5b,
-	0xe5, 0xba, 0x7f, 0x99, 0x99, 0xa7, 0xa5, 0xb8, 0xec, 0x2b, 0xa5, 0xcb, 0x9e, 0x40, 0x2d, 0x63,
-	0x53, 0xfb, 0xa2, 0x55, 0x3d, 0x5c, 0x93, 0x1d, 0xb8, 0x17, 0x89, 0x58, 0x68, 0x7c, 0xc6, 0xaa,
-	0x9e, 0xdd, 0x1c, 0xff, 0x55, 0x83, 0x75, 0x1b, 0xf3, 0x0d, 0x97, 0x73, 0x73, 0x2b, 0x4c, 0xa1,
-	0x7d, 0x86, 0x2f, 0x89, 0x7b, 0x34, 0xef, 0xca, 0xab, 0xfb, 0xf8, 0xae, 0xa4, 0x5d, 0xb5, 0xfd,
-	0xdd, 0x3f, 0xfe, 0xf9, 0xf7, 0xcf, 0xb5, 0x4e, 0x1f, 0x46, 0xf3, 0x2f, 0xdc, 0xc7, 0xc2, 0xd7,
-	0x95, 0x67, 0x24, 0x82, 0xf6, 0xdb, 0x2c, 0xf8, 0x90, 0x42, 0x5d, 0x14, 0xda, 0xe9, 0x77, 0x96,
-	0x42, 0xa3, 0x77, 0x22, 0xf8, 0xdd, 0xa8, 0x49, 0x68, 0x9d, 0x73, 0x8d, 0x0e, 0x82, 0x2b, 0xf2,
-	0xf4, 0xce, 0x89, 0x5b, 0x74, 0xb7, 0x7b, 0xf4, 0x5e, 0x9e, 0xd3, 0x26, 0xa8, 0xdd, 0x26, 0xa5,
-	0x22, 0x49, 0x0a, 0x9d, 0x73, 0xae, 0x5f, 0xe0, 0x27, 0x80, 0x2b, 0xf2, 0xd1, 0xad, 0xc3, 0x71,
-	0xf1, 0x5d, 0xa1, 0xb7, 0x52, 0x9d, 0xf7, 0x51, 0x6b, 0x8b, 0xfc, 0xbf, 0x4e, 0xf2, 0x0e, 0x48,
-	0x51, 0x64, 0xee, 0x71, 0x73, 0x43, 0xa6, 0x89, 0x7a, 0xaf, 0xe6, 0xca, 0x35, 0x1e, 0xa0, 0xee,
-	0x1e, 0xd9, 0x59, 0xea, 0xfa, 0x92, 0xcf, 0x15, 0x8a, 0x9f, 0x36, 0x7e, 0xae, 0x5b, 0x6c, 0x5c,
-	0xc7, 0x8f, 0xb7, 0x2f, 0xff, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xe1, 0x72, 0xee, 0xdf, 0x27, 0x0a,
-	0x00, 0x00,
+	// 1748 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x72, 0x23, 0x39,
+	0x15, 0x2e, 0xc7, 0x8e, 0x93, 0x1c, 0x3b, 0x7f, 0x72, 0x92, 0xd1, 0x38, 0xd9, 0x99, 0x4c, 0x0f,
+	0x3b, 0x93, 0xcd, 0x82, 0x03, 0xa1, 0xf6, 0x06, 0x0a, 0xaa, 0x92, 0xec, 0xec, 0xd4, 0x14, 0xfb,
+	0x57, 0xbd, 0xec, 0x0d, 0x37, 0x2e, 0xd9, 0x2d, 0x3b, 0xaa, 0xe9, 0x6e, 0xf5, 0x4a, 0x72, 0x12,
+	0x17, 0x0c, 0x45, 0x01, 0x6f, 0xc0, 0x73, 0x50, 0x5c, 0x70, 0xc9, 0x63, 0x70, 0xc5, 0x35, 0x3c,
+	0x08, 0xa5, 0x23, 0xc9, 0x6e, 0x3b, 0x21, 0x63, 0x76, 0x67, 0xaf, 0xd2, 0xfa, 0xce, 0x39, 0xfa,
+	0xce, 0x8f, 0xa4, 0x73, 0x62, 0x78, 0x58, 0x28, 0x69, 0xe4, 0x09, 0xcf, 0x8d, 0x30, 0x63, 0xff,
+	0xa7, 0x83, 0x18, 0x69, 0x0d, 0x55, 0xd1, 0xef, 0x0c, 0x99, 0xe1, 0xd7, 0x6c, 0xdc, 0x71, 0xa2,
+	0xf6, 0xc1, 0x50, 0xca, 0x61, 0xca, 0x4f, 0x58, 0x21, 0x4e, 0x58, 0x9e, 0x4b, 0xc3, 0x8c, 0x90,
+	0xb9, 0x76, 0x26, 0x6d, 0xbf, 0x5b, 0x5f, 0x66, 0x99, 0xcc, 0xfd, 0x1f, 0x2f, 0xda, 0xf7, 0x86,
+	0xb8, 0xea, 0x8d, 0x06, 0x27, 0x3c, 0x2b, 0x02, 0x55, 0x74, 0x06, 0xf0, 0x02, 0xf7, 0xff, 0x54,
+	0xe4, 0xaf, 0xc9, 0x3e, 0xac, 0x39, 0xb6, 0xae, 0x48, 0x68, 0xe5, 0xb0, 0x72, 0xb4, 0x16, 0xaf,
+	0x3a, 0xe0, 0x55, 0x42, 0xf6, 0xa0, 0xce, 0x32, 0x39, 0xca, 0x0d, 0x5d, 0x42, 0x89, 0x5f, 0x45,
+	0x7f, 0x6d, 0x42, 0xdd, 0xed, 0x41, 0x36, 0x60, 0x69, 0x62, 0xb8, 0x24, 0x12, 0xf2, 0x18, 0x1a,
+	0xce, 0x95, 0x6e, 0xce, 0x32, 0xee, 0xed, 0xc0, 0x41, 0x9f, 0xb3, 0x8c, 0x93, 0xf7, 0xc0, 0xae,
+	0x0a, 0x96, 0x23, 0x63, 0x15, 0xe5, 0x6b, 0x1e, 0x79, 0x95, 0x90, 0x2d, 0xa8, 0x2a, 0x7e, 0x45,
+	0x6b, 0x87, 0x95, 0xa3, 0x6a, 0x6c, 0x3f, 0xad, 0x13, 0x29, 0x33, 0x5c, 0x1b, 0xba, 0x7c, 0x58,
+	0x39, 0x5a, 0x8d, 0xfd, 0x8a, 0x74, 0xa0, 0xd5, 0x57, 0x9c, 0x19, 0x9e, 0x74, 0x7b, 0xe3, 0xee,
+	0x48, 0x73, 0x85, 0x8c, 0x75, 0xdc, 0x71, 0xdb, 0x8b, 0xce, 0xc7, 0x5f, 0x7b, 0x01, 0x12, 0x7b,
+	0x7d, 0x66, 0xe8, 0x0a, 0x12, 0xac, 0x79, 0xe4, 0xcc, 0x94, 0xc5, 0xbd, 0x31, 0x5d, 0xf5, 0x7e,
+	0x85, 0x5d, 0x08, 0x81, 0x9a, 0x19, 0x17, 0x9c, 0xae, 0xa1, 0x00, 0xbf, 0xad, 0xc9, 0x50, 0x5c,
+	0x71, 0x1f, 0x2a, 0x38, 0x13, 0x44, 0x30, 0xd2, 0xc7, 0xd0, 0xc8, 0x44, 0x92, 0xa4, 0xdc, 0xc9,
+	0x1b, 0x2e, 0x15, 0x0e, 0x0a, 0x0a, 0x03, 0x96, 0x89, 0x74, 0xec, 0x14, 0x9a, 0x4e, 0xc1, 0x41,
+	0x41, 0xc1, 0x4a, 0xba, 0x85, 0xe2, 0x03, 0x71, 0x43, 0xd7, 0x9d, 0x82, 0x85, 0xbe, 0x44, 0x64,
+	0xa2, 0xa0, 0x47, 0x03, 0xab, 0xb0, 0x31, 0x55, 0xf8, 0x0a, 0x11, 0x9b, 0xbc, 0x21, 0xcf, 0x13,
+	0xae, 0xe8, 0xa6, 0xab, 0xa0, 0x5b, 0x91, 0x36, 0xac, 0xf6, 0x84, 0x32, 0x97, 0x09, 0x1b, 0xd3,
+	0x2d, 0x57, 0xf5, 0xb0, 0x26, 0x8f, 0x00, 0xf0, 0xbb, 0x48, 0x59, 0x9f, 0xd3, 0x6d, 0xb7, 0xe7,
+	0x14, 0x21, 0x11, 0x34, 0x71, 0xd5, 0xb7, 0x67, 0x41, 0x8d, 0x29, 0x41, 0x8d, 0x19, 0x8c, 0x1c,
+	0x5a, 0xc7, 0xec, 0x69, 0x65, 0xa9, 0x30, 0x63, 0xda, 0x42, 0x95, 0x32, 0x44, 0x3e, 0x83, 0x96,
+	0xe2, 0x5a, 0x24, 0xf6, 0xb0, 0xb1, 0xb4, 0xcb, 0x92, 0x44, 0x71, 0xad, 0xe9, 0xce, 0x61, 0xe5,
+	0xa8, 0x71, 0x7a, 0xd0, 0x99, 0xb9, 0x0f, 0xfe, 0x70, 0x9f, 0x39, 0x9d, 0x98, 0x94, 0x0c, 0x3d,
+	0x66, 0xcf, 0xcd, 0xeb, 0xab, 0xd7, 0x74, 0x17, 0x89, 0xec, 0xa7, 0xad, 0x4e, 0xca, 0x87, 0x2c,
+	0xed, 0x0e, 0xa4, 0xca, 0xe8, 0x9e, 0xab, 0x0e, 0x22, 0x9f, 0x48, 0x95, 0x91, 0xe7, 0xb0, 0xa9,
+	0xf8, 0x50, 0x68, 0xc3, 0x15, 0x4f, 0x5c, 0x01, 0x1e, 0xa0, 0xce, 0xc6, 0x14, 0xc6, 0x22, 0x7c,
+	0x08, 0xdb, 0x25, 0x45, 0x39, 0x18, 0x88, 0x3e, 0xa7, 0x14, 0x55, 0xb7, 0xa6, 0x82, 0x2f, 0x10,
+	0x27, 0x3f, 0x86, 0x9d, 0x84, 0x19, 0xde, 0x95, 0x83, 0xae, 0x93, 0x29, 0x0c, 0x99, 0x3e, 0x44,
+	0x7d, 0x62, 0x65, 0x5f, 0x0c, 0xe2, 0x92, 0x84, 0x9c, 0xc2, 0x6e, 0xb0, 0xe0, 0xda, 0xb0, 0x5e,
+	0x2a, 0xf4, 0x65, 0xc6, 0x73, 0x43, 0xdb, 0x68, 0xd2, 0x72, 0x26, 0x2f, 0xca, 0x22, 0x1b, 0x9a,
+	0x51, 0x2c, 0xf1, 0x07, 0x6b, 0xdf, 0x85, 0x86, 0x08, 0x7a, 0xfc, 0x12, 0xb6, 0xae, 0x84, 0x16,
+	0x46, 0xe4, 0xc3, 0x49, 0x5e, 0x0f, 0x16, 0xc8, 0xeb, 0x66, 0xb0, 0x0a, 0x49, 0xfd, 0x15, 0x90,
+	0x52, 0xe8, 0x61, 0xab, 0xf7, 0x16, 0xd8, 0xaa, 0x94, 0xb2, 0xb0, 0x19, 0x81, 0x9a, 0xd2, 0x22,
+	0xa7, 0x91, 0xbb, 0x41, 0xf6, 0x9b, 0xbc, 0x0f, 0x1b, 0x42, 0xeb, 0x11, 0x4f, 0xba, 0x7d, 0x56,
+	0x08, 0xc3, 0x52, 0xfa, 0x14, 0xa5, 0xeb, 0x0e, 0xbd, 0x70, 0xa0, 0x55, 0x2b, 0x98, 0x48, 0x46,
+	0xc5, 0x44, 0xed, 0x07, 0x4e, 0xcd, 0xa1, 0x41, 0x6d, 0x17, 0xea, 0x42, 0x77, 0x7b, 0x03, 0x41,
+	0xdf, 0xc7, 0x97, 0x62, 0x59, 0xe8, 0xf3, 0x81, 0xb0, 0xd9, 0xea, 0x0d, 0x44, 0x37, 0x1f, 0x65,
+	0x3d, 0xae, 0xe8, 0x33, 0x97, 0xad, 0xde, 0x40, 0x7c, 0x8e, 0x00, 0xf9, 0x05, 0xac, 0x25, 0x42,
+	0xf1, 0xbe, 0x91, 0x4a, 0xd3, 0xe7, 0x18, 0xdb, 0xe3, 0xce, 0x1d, 0xcf, 0x71, 0x67, 0xfa, 0x6a,
+	0xc6, 0x53, 0x0b, 0x72, 0x01, 0xcd, 0x42, 0xc9, 0x9b, 0xf1, 0xa5, 0x4c, 0x13, 0xae, 0x34, 0x3d,
+	0x5a, 0x6c, 0x87, 0x19, 0x23, 0xf2, 0x73, 0x58, 0x35, 0x6a, 0xa4, 0x0d, 0xe7, 0x9a, 0x7e, 0xb0,
+	0xd8, 0x06, 0x13, 0x03, 0xeb, 0x81, 0xbe, 0x64, 0x8a, 0x07, 0x0f, 0x8e, 0x17, 0xf4, 0xa0, 0x6c,
+	0x64, 0x93, 0x24, 0x74, 0x37, 0xe1, 0x29, 0x37, 0x3c, 0xa1, 0x1f, 0x62, 0xfe, 0xd6, 0x84, 0xfe,
+	0xd8, 0x01, 0x56, 0xec, 0x65, 0xf6, 0xf1, 0xfc, 0xa1, 0x7b, 0x3c, 0x3d, 0xe2, 0x1e, 0xcf, 0x20,
+	0xee, 0x8d, 0xe9, 0x8f, 0x5c, 0x8a, 0x3d, 0x72, 0x3e, 0x8e, 0xfe, 0x5e, 0x01, 0x12, 0x98, 0xb5,
+	0x89, 0xb9, 0x2e, 0x64, 0xae, 0x39, 0xf9, 0x08, 0x6a, 0x19, 0x37, 0x0c, 0xbb, 0x47, 0xe3, 0xf4,
+	0xc9, 0x9d, 0x07, 0xea, 0x33, 0x6e, 0x58, 0x30, 0x88, 0x51, 0x9d, 0x9c, 0x40, 0x2d, 0x61, 0x86,
+	0xd1, 0xa5, 0xc3, 0xea, 0x51, 0xe3, 0x74, 0xff, 0x9e, 0x38, 0x63, 0x54, 0xc4, 0x57, 0x92, 0xdf,
+	0x98, 0x6e, 0x7f, 0xa4, 0xb4, 0x54, 0xbe, 0xe7, 0x80, 0x85, 0x2e, 0x10, 0x21, 0x3b, 0xb0, 0x6c,
+	0xa4, 0x3d, 0x56, 0xae, 0xed, 0xb8, 0x45, 0x74, 0x03, 0x1b, 0x7e, 0x9b, 0x77, 0xe6, 0x70, 0x65,
+	0x21, 0x87, 0xa3, 0x01, 0xec, 0x7e, 0x39, 0x52, 0x43, 0x8e, 0xa0, 0xe0, 0xfa, 0xbb, 0x3a, 0xb0,
+	0x07, 0xf5, 0xc2, 0xee, 0x97, 0xa0, 0x0b, 0xd5, 0xd8, 0xaf, 0xa2, 0x7f, 0x2f, 0x41, 0xcb, 0x11,
+	0x7f, 0xc5, 0x99, 0xea, 0x5f, 0xc6, 0xfc, 0x9b, 0x91, 0x6d, 0xad, 0x3b, 0xb0, 0xfc, 0xcd, 0x88,
+	0xab, 0xb1, 0xef, 0xeb, 0x6e, 0x31, 0x69, 0x81, 0x4b, 0xa5, 0x16, 0xe8, 0x9f, 0xdd, 0xea, 0xf4,
+	0xd9, 0x0d, 0xd7, 0xbc, 0x56, 0xba, 0xe6, 0xb3, 0x37, 0x70, 0x79, 0xfe, 0x06, 0xce, 0x35, 0x8b,
+	0xfa, 0xed, 0x66, 0x31, 0xfb, 0x96, 0xaf, 0xcc, 0xbf, 0xe5, 0x14, 0x56, 0x42, 0x33, 0x72, 0x8d,
+	0x3b, 0x2c, 0xc9, 0x13, 0x68, 0x86, 0xae, 0x3e, 0x50, 0x32, 0xc3, 0xf6, 0x5d, 0x8d, 0x1b, 0x1e,
+	0xfb, 0x44, 0xc9, 0xac, 0xdc, 0xf8, 0x8d, 0xa4, 0x30, 0x33, 0x17, 0xfc, 0x5a, 0xda, 0xdc, 0xf9,
+	0x73, 0xe3, 0x1a, 0xb8, 0x5f, 0xd9, 0x1c, 0xa5, 0x22, 0x13, 0x06, 0xdb, 0x76, 0x35, 0x76, 0x0b,
+	0x1b, 0xbd, 0x96, 0xca, 0xf8, 0x56, 0x8d, 0xdf, 0xd1, 0x1b, 0xd8, 0x2e, 0x1f, 0x7e, 0x97, 0xe2,
+	0x90, 0xcc, 0x4a, 0x29, 0x99, 0x04, 0x6a, 0x05, 0x1b, 0x72, 0x5f, 0x24, 0xfc, 0x9e, 0xd2, 0x54,
+	0xcb, 0x34, 0x53, 0xa7, 0x6a, 0x33, 0x4e, 0x05, 0xfa, 0xe5, 0x12, 0xfd, 0x79, 0xb8, 0x7b, 0xaf,
+	0xb2, 0x42, 0x2a, 0x73, 0x21, 0xd3, 0x51, 0x96, 0xe3, 0x0e, 0xf8, 0xe5, 0x3d, 0xf0, 0x2b, 0xcb,
+	0x37, 0x10, 0x3c, 0x4d, 0x7c, 0x95, 0xdd, 0x22, 0xfa, 0x47, 0x05, 0x5a, 0xe5, 0x4d, 0x42, 0x14,
+	0xfb, 0xb0, 0x36, 0x10, 0x61, 0xc0, 0xf1, 0xd3, 0xa3, 0x05, 0xb0, 0x0d, 0xed, 0x41, 0xdd, 0x96,
+	0x8b, 0x4d, 0xa6, 0x47, 0xb7, 0x72, 0xd5, 0xca, 0x0d, 0xcf, 0x5d, 0x50, 0xcd, 0x38, 0x2c, 0xc9,
+	0x19, 0xac, 0x64, 0xac, 0x28, 0x44, 0x3e, 0xa4, 0x35, 0xbc, 0xdc, 0xcf, 0xef, 0xb9, 0x2b, 0xe5,
+	0x70, 0xe2, 0x60, 0x67, 0x33, 0x90, 0xc9, 0x84, 0x87, 0x0c, 0xd8, 0xef, 0x28, 0x83, 0xed, 0xb2,
+	0xc9, 0x0b, 0xa5, 0xa4, 0xc2, 0x41, 0x53, 0x5e, 0xd3, 0x8a, 0x1f, 0x34, 0xe5, 0x75, 0x29, 0x25,
+	0x4b, 0x77, 0xa7, 0xa4, 0x5a, 0x4a, 0x89, 0x8d, 0x22, 0xe3, 0x5a, 0xdb, 0x7a, 0xb9, 0x1a, 0x84,
+	0x65, 0xf4, 0xaf, 0x2a, 0x34, 0xcb, 0x7c, 0xb7, 0x66, 0xe4, 0xd9, 0x11, 0x78, 0x69, 0x7e, 0x04,
+	0x9e, 0x9d, 0x44, 0xab, 0xf3, 0x93, 0xe8, 0xec, 0x1c, 0x5b, 0x9b, 0x9f, 0x63, 0xed, 0x50, 0x29,
+	0x72, 0xa1, 0x2f, 0x9d, 0x7c, 0x19, 0xe5, 0x10, 0xa0, 0xb3, 0xb9, 0x9a, 0xd5, 0xff, 0x67, 0xcd,
+	0x56, 0x66, 0x6a, 0x16, 0xd2, 0xba, 0x3a, 0x4d, 0xab, 0xd5, 0xd5, 0x86, 0x99, 0x91, 0xf6, 0x43,
+	0xb1, 0x5f, 0x59, 0x07, 0xf1, 0x01, 0xed, 0x2a, 0x79, 0xad, 0xc3, 0x85, 0x42, 0x24, 0x96, 0xd7,
+	0xda, 0x5e, 0x49, 0x91, 0x5f, 0xb1, 0x54, 0x24, 0x4e, 0xa1, 0xe1, 0xae, 0xa4, 0xc7, 0x50, 0xe5,
+	0x29, 0xac, 0x0b, 0x4c, 0x1d, 0xf7, 0x3a, 0xee, 0x8e, 0x35, 0x03, 0x88, 0x4a, 0xbf, 0x84, 0x3a,
+	0xb7, 0x95, 0xd4, 0x74, 0x1d, 0xcf, 0xca, 0xb3, 0xb7, 0x9e, 0x15, 0x2c, 0x7c, 0xec, 0xad, 0x6c,
+	0x59, 0xf1, 0xcb, 0x4f, 0xcd, 0x6e, 0x61, 0x9d, 0x1f, 0x15, 0x49, 0xc8, 0xee, 0xa6, 0x73, 0xde,
+	0x23, 0x67, 0x26, 0xfa, 0x73, 0x05, 0x76, 0x66, 0x2f, 0xc2, 0x77, 0x7b, 0x99, 0x3f, 0x9a, 0x69,
+	0x0d, 0x4f, 0xde, 0x1a, 0x82, 0x6b, 0x10, 0xa7, 0x7f, 0x6b, 0xc0, 0x7a, 0x78, 0xb8, 0xd5, 0x95,
+	0x1d, 0x3c, 0x87, 0xd0, 0xbc, 0xc0, 0x33, 0xe0, 0x60, 0x72, 0x5f, 0x97, 0x69, 0x3f, 0xbd, 0x47,
+	0x18, 0x1c, 0x8c, 0x76, 0xff, 0xf8, 0xcf, 0xff, 0xfc, 0x65, 0x69, 0x33, 0x82, 0x93, 0xab, 0x9f,
+	0xf8, 0x7f, 0x56, 0x7f, 0x56, 0x39, 0x26, 0x29, 0x34, 0xbf, 0xc6, 0x74, 0xbc, 0x33, 0xa2, 0x36,
+	0x12, 0xed, 0x44, 0x9b, 0x53, 0xa2, 0x93, 0xdf, 0x8a, 0xe4, 0x8d, 0x65, 0x53, 0xd0, 0x78, 0xc9,
+	0x4d, 0xe8, 0x83, 0xe4, 0xd9, 0xbd, 0x43, 0xcd, 0xe4, 0x75, 0x6d, 0x3f, 0x7f, 0xab, 0x9e, 0xe7,
+	0x26, 0xc8, 0xdd, 0x24, 0xa5, 0x20, 0x89, 0x84, 0xcd, 0x97, 0xdc, 0x7c, 0x8a, 0xff, 0x65, 0xfa,
+	0x20, 0x1f, 0xdd, 0x59, 0xcf, 0x57, 0x1f, 0x07, 0xbe, 0x85, 0xe2, 0x7c, 0x80, 0x5c, 0xdb, 0x64,
+	0x3e, 0x4e, 0xf2, 0x06, 0x48, 0x08, 0x72, 0x1c, 0x73, 0x3b, 0x84, 0xcb, 0x5c, 0x93, 0xc3, 0x3b,
+	0x39, 0xbf, 0x55, 0x94, 0x07, 0xc8, 0xbc, 0x47, 0x76, 0xa6, 0xcc, 0x5d, 0xc5, 0xaf, 0xb4, 0xa3,
+	0x4f, 0xa1, 0xe9, 0xc6, 0xbc, 0xef, 0x21, 0xd8, 0xe3, 0x5b, 0xc1, 0xde, 0xc0, 0x7a, 0xcc, 0xb5,
+	0x91, 0xea, 0x9d, 0xd2, 0x45, 0x48, 0x77, 0x10, 0x3d, 0x98, 0xa3, 0x3b, 0x51, 0x8e, 0xcb, 0x9e,
+	0xa5, 0x3f, 0x54, 0xa0, 0x65, 0xd3, 0xe2, 0x67, 0xda, 0xef, 0xef, 0x50, 0x51, 0x74, 0x86, 0x90,
+	0xad, 0x52, 0xba, 0x8d, 0x62, 0xfa, 0x92, 0x18, 0xd8, 0xc1, 0xc1, 0x6e, 0xde, 0x85, 0xbd, 0x8e,
+	0xfb, 0xc5, 0xa6, 0x13, 0x7e, 0xb1, 0xe9, 0xbc, 0xb0, 0xbf, 0xd8, 0xb4, 0x8f, 0xef, 0xa4, 0xbc,
+	0x73, 0x36, 0x0c, 0xac, 0xc7, 0xb7, 0x59, 0x7f, 0x0f, 0x1b, 0x6e, 0xbe, 0x9b, 0xf0, 0x1d, 0xdd,
+	0x13, 0xca, 0xcc, 0x28, 0xb8, 0x78, 0xd0, 0x0f, 0x91, 0xbe, 0x45, 0xb6, 0x4b, 0xf4, 0x1a, 0xb7,
+	0x22, 0x7f, 0xaa, 0xc0, 0x86, 0x7f, 0x81, 0x17, 0x71, 0x60, 0x66, 0xc4, 0x68, 0x7f, 0xb0, 0x80,
+	0xe6, 0xec, 0x31, 0x8f, 0xca, 0x2e, 0xb8, 0x96, 0x61, 0xcb, 0xff, 0x3b, 0xbc, 0xd6, 0x65, 0xc3,
+	0xb7, 0x1e, 0xbd, 0xff, 0x83, 0xfb, 0x11, 0x72, 0x53, 0xb2, 0x77, 0x8b, 0x1b, 0xcf, 0xe1, 0xf9,
+	0xea, 0x6f, 0xea, 0x0e, 0xed, 0xd5, 0xb1, 0xc6, 0x3f, 0xfd, 0xef, 0x00, 0x82, 0xae, 0x6b, 0x06,
+	0x0e, 0x14, 0x00, 0x00,
 }
@@ -13,7 +13,26 @@ It has these top-level messages:
 	UserListResponse
 	UserResponse
 	LoginRequest
+	TOTPEnrollmentResponse
+	TOTPConfirmationRequest
+	PreferredFactorRequest
+	PasswordResetRequest
+	ResetPasswordRequest
+	ResetPasswordResponse
+	ServiceAccountRequest
+	APIKey
+	CreateAPIKeyRequest
+	APIKeyResponse
+	APIKeyListResponse
 	SMSConfirmationRequest
+	RefreshRequest
+	InvitationRequest
+	Invitation
+	InvitationListResponse
+	ImpersonateRequest
+	Session
+	SessionListResponse
+	UserSessionRequest
 	User
 */
 package user
@@ -43,8 +62,11 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type LoginResponse struct {
-	Meta  *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
-	Token string                            `protobuf:"bytes,2,opt,name=token" json:"token"`
+	Meta         *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Token        string                            `protobuf:"bytes,2,opt,name=token" json:"token"`
+	RefreshToken string                            `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken" json:"refresh_token"`
+	ExpiresIn    int64                             `protobuf:"varint,4,opt,name=expires_in,json=expiresIn" json:"expires_in"`
+	SecondFactor string                            `protobuf:"bytes,5,opt,name=second_factor,json=secondFactor" json:"second_factor"`
 }
 
 func (m *LoginResponse) Reset()                    { *m = LoginResponse{} }
@@ -66,9 +88,32 @@ func (m *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (m *LoginResponse) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *LoginResponse) GetSecondFactor() string {
+	if m != nil {
+		return m.SecondFactor
+	}
+	return ""
+}
+
 type UserListResponse struct {
-	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
-	Data []*User                           `protobuf:"bytes,2,rep,name=data" json:"data"`
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*User                           `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
 }
 
 func (m *UserListResponse) Reset()                    { *m = UserListResponse{} }
@@ -90,6 +135,20 @@ func (m *UserListResponse) GetData() []*User {
 	return nil
 }
 
+func (m *UserListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *UserListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
 type UserResponse struct {
 	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
 	Data *User                             `protobuf:"bytes,2,opt,name=data" json:"data"`
@@ -118,6 +177,7 @@ type LoginRequest struct {
 	Email    string `protobuf:"bytes,1,opt,name=email" json:"email"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password"`
 	SmsCode  string `protobuf:"bytes,3,opt,name=sms_code,json=smsCode" json:"sms_code"`
+	TotpCode string `protobuf:"bytes,4,opt,name=totp_code,json=totpCode" json:"totp_code"`
 }
 
 func (m *LoginRequest) Reset()                    { *m = LoginRequest{} }
@@ -146,286 +206,1426 @@ func (m *LoginRequest) GetSmsCode() string {
 	return ""
 }
 
-type SMSConfirmationRequest struct {
-	Code string `protobuf:"bytes,1,opt,name=code" json:"code"`
-}
-
-func (m *SMSConfirmationRequest) Reset()                    { *m = SMSConfirmationRequest{} }
-func (m *SMSConfirmationRequest) String() string            { return proto.CompactTextString(m) }
-func (*SMSConfirmationRequest) ProtoMessage()               {}
-func (*SMSConfirmationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
-
-func (m *SMSConfirmationRequest) GetCode() string {
+func (m *LoginRequest) GetTotpCode() string {
 	if m != nil {
-		return m.Code
+		return m.TotpCode
 	}
 	return ""
 }
 
-type User struct {
-	Id          string                      `protobuf:"bytes,1,opt,name=id" json:"id"`
-	CompanyId   string                      `protobuf:"bytes,2,opt,name=company_id,json=companyId" json:"company_id"`
-	Name        string                      `protobuf:"bytes,4,opt,name=name" json:"name"`
-	Email       string                      `protobuf:"bytes,5,opt,name=email" json:"email"`
-	Password    string                      `protobuf:"bytes,6,opt,name=password" json:"password"`
-	Phone       string                      `protobuf:"bytes,7,opt,name=phone" json:"phone"`
-	IsAdmin     bool                        `protobuf:"varint,3,opt,name=is_admin,json=isAdmin" json:"is_admin"`
-	IsEnabled   bool                        `protobuf:"varint,8,opt,name=is_enabled,json=isEnabled" json:"is_enabled"`
-	IsConfirmed bool                        `protobuf:"varint,9,opt,name=is_confirmed,json=isConfirmed" json:"is_confirmed"`
-	EmailCode   string                      `protobuf:"bytes,10,opt,name=email_code,json=emailCode" json:"email_code"`
-	SmsCode     string                      `protobuf:"bytes,11,opt,name=sms_code,json=smsCode" json:"sms_code"`
-	EmailSentAt *google_protobuf2.Timestamp `protobuf:"bytes,12,opt,name=email_sent_at,json=emailSentAt" json:"email_sent_at"`
-	SmsSentAt   *google_protobuf2.Timestamp `protobuf:"bytes,13,opt,name=sms_sent_at,json=smsSentAt" json:"sms_sent_at"`
+type TOTPEnrollmentResponse struct {
+	Meta   *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Secret string                            `protobuf:"bytes,2,opt,name=secret" json:"secret"`
+	Uri    string                            `protobuf:"bytes,3,opt,name=uri" json:"uri"`
 }
 
-func (m *User) Reset()                    { *m = User{} }
-func (m *User) String() string            { return proto.CompactTextString(m) }
-func (*User) ProtoMessage()               {}
-func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *TOTPEnrollmentResponse) Reset()                    { *m = TOTPEnrollmentResponse{} }
+func (m *TOTPEnrollmentResponse) String() string            { return proto.CompactTextString(m) }
+func (*TOTPEnrollmentResponse) ProtoMessage()               {}
+func (*TOTPEnrollmentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
-func (m *User) GetId() string {
+func (m *TOTPEnrollmentResponse) GetMeta() *grpc_gateway_common.MetaResponse {
 	if m != nil {
-		return m.Id
+		return m.Meta
 	}
-	return ""
+	return nil
 }
 
-func (m *User) GetCompanyId() string {
+func (m *TOTPEnrollmentResponse) GetSecret() string {
 	if m != nil {
-		return m.CompanyId
+		return m.Secret
 	}
 	return ""
 }
 
-func (m *User) GetName() string {
+func (m *TOTPEnrollmentResponse) GetUri() string {
 	if m != nil {
-		return m.Name
+		return m.Uri
 	}
 	return ""
 }
 
-func (m *User) GetEmail() string {
+type TOTPConfirmationRequest struct {
+	Code string `protobuf:"bytes,1,opt,name=code" json:"code"`
+}
+
+func (m *TOTPConfirmationRequest) Reset()                    { *m = TOTPConfirmationRequest{} }
+func (m *TOTPConfirmationRequest) String() string            { return proto.CompactTextString(m) }
+func (*TOTPConfirmationRequest) ProtoMessage()               {}
+func (*TOTPConfirmationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *TOTPConfirmationRequest) GetCode() string {
 	if m != nil {
-		return m.Email
+		return m.Code
 	}
 	return ""
 }
 
-func (m *User) GetPassword() string {
+type PreferredFactorRequest struct {
+	Factor string `protobuf:"bytes,1,opt,name=factor" json:"factor"`
+}
+
+func (m *PreferredFactorRequest) Reset()                    { *m = PreferredFactorRequest{} }
+func (m *PreferredFactorRequest) String() string            { return proto.CompactTextString(m) }
+func (*PreferredFactorRequest) ProtoMessage()               {}
+func (*PreferredFactorRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *PreferredFactorRequest) GetFactor() string {
 	if m != nil {
-		return m.Password
+		return m.Factor
 	}
 	return ""
 }
 
-func (m *User) GetPhone() string {
+type PasswordResetRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email"`
+}
+
+func (m *PasswordResetRequest) Reset()                    { *m = PasswordResetRequest{} }
+func (m *PasswordResetRequest) String() string            { return proto.CompactTextString(m) }
+func (*PasswordResetRequest) ProtoMessage()               {}
+func (*PasswordResetRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *PasswordResetRequest) GetEmail() string {
 	if m != nil {
-		return m.Phone
+		return m.Email
 	}
 	return ""
 }
 
-func (m *User) GetIsAdmin() bool {
-	if m != nil {
-		return m.IsAdmin
-	}
-	return false
+type ResetPasswordRequest struct {
+	Token    string `protobuf:"bytes,1,opt,name=token" json:"token"`
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password"`
+	SmsCode  string `protobuf:"bytes,3,opt,name=sms_code,json=smsCode" json:"sms_code"`
+	TotpCode string `protobuf:"bytes,4,opt,name=totp_code,json=totpCode" json:"totp_code"`
 }
 
-func (m *User) GetIsEnabled() bool {
+func (m *ResetPasswordRequest) Reset()                    { *m = ResetPasswordRequest{} }
+func (m *ResetPasswordRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetPasswordRequest) ProtoMessage()               {}
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *ResetPasswordRequest) GetToken() string {
 	if m != nil {
-		return m.IsEnabled
+		return m.Token
 	}
-	return false
+	return ""
 }
 
-func (m *User) GetIsConfirmed() bool {
+func (m *ResetPasswordRequest) GetPassword() string {
 	if m != nil {
-		return m.IsConfirmed
+		return m.Password
 	}
-	return false
+	return ""
 }
 
-func (m *User) GetEmailCode() string {
+func (m *ResetPasswordRequest) GetSmsCode() string {
 	if m != nil {
-		return m.EmailCode
+		return m.SmsCode
 	}
 	return ""
 }
 
-func (m *User) GetSmsCode() string {
+func (m *ResetPasswordRequest) GetTotpCode() string {
 	if m != nil {
-		return m.SmsCode
+		return m.TotpCode
 	}
 	return ""
 }
 
-func (m *User) GetEmailSentAt() *google_protobuf2.Timestamp {
+type ResetPasswordResponse struct {
+	Meta         *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	SecondFactor string                            `protobuf:"bytes,2,opt,name=second_factor,json=secondFactor" json:"second_factor"`
+}
+
+func (m *ResetPasswordResponse) Reset()                    { *m = ResetPasswordResponse{} }
+func (m *ResetPasswordResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResetPasswordResponse) ProtoMessage()               {}
+func (*ResetPasswordResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *ResetPasswordResponse) GetMeta() *grpc_gateway_common.MetaResponse {
 	if m != nil {
-		return m.EmailSentAt
+		return m.Meta
 	}
 	return nil
 }
 
-func (m *User) GetSmsSentAt() *google_protobuf2.Timestamp {
+func (m *ResetPasswordResponse) GetSecondFactor() string {
 	if m != nil {
-		return m.SmsSentAt
+		return m.SecondFactor
 	}
-	return nil
+	return ""
 }
 
-func init() {
-	proto.RegisterType((*LoginResponse)(nil), "grpc.gateway.user.LoginResponse")
-	proto.RegisterType((*UserListResponse)(nil), "grpc.gateway.user.UserListResponse")
-	proto.RegisterType((*UserResponse)(nil), "grpc.gateway.user.UserResponse")
-	proto.RegisterType((*LoginRequest)(nil), "grpc.gateway.user.LoginRequest")
-	proto.RegisterType((*SMSConfirmationRequest)(nil), "grpc.gateway.user.SMSConfirmationRequest")
-	proto.RegisterType((*User)(nil), "grpc.gateway.user.User")
+type ServiceAccountRequest struct {
+	Name      string `protobuf:"bytes,1,opt,name=name" json:"name"`
+	CompanyId string `protobuf:"bytes,2,opt,name=company_id,json=companyId" json:"company_id"`
+	Role      string `protobuf:"bytes,3,opt,name=role" json:"role"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ServiceAccountRequest) Reset()                    { *m = ServiceAccountRequest{} }
+func (m *ServiceAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*ServiceAccountRequest) ProtoMessage()               {}
+func (*ServiceAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
-// Client API for UserService service
+func (m *ServiceAccountRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
 
-type UserServiceClient interface {
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
-	ConfirmEmail(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
-	UpdateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
-	GetUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserResponse, error)
-	DeleteUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
-	GetUsers(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*UserListResponse, error)
-	GetUserByCompany(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserListResponse, error)
+func (m *ServiceAccountRequest) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
 }
 
-type userServiceClient struct {
-	cc *grpc.ClientConn
+func (m *ServiceAccountRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
 }
 
-func NewUserServiceClient(cc *grpc.ClientConn) UserServiceClient {
-	return &userServiceClient{cc}
+type APIKey struct {
+	Id               string `protobuf:"bytes,1,opt,name=id" json:"id"`
+	ServiceAccountId string `protobuf:"bytes,2,opt,name=service_account_id,json=serviceAccountId" json:"service_account_id"`
+	CompanyId        string `protobuf:"bytes,3,opt,name=company_id,json=companyId" json:"company_id"`
+	Name             string `protobuf:"bytes,4,opt,name=name" json:"name"`
+	KeyHash          string `protobuf:"bytes,5,opt,name=key_hash,json=keyHash" json:"key_hash"`
+	CreatedAt        int64  `protobuf:"varint,6,opt,name=created_at,json=createdAt" json:"created_at"`
+	ExpiresAt        int64  `protobuf:"varint,7,opt,name=expires_at,json=expiresAt" json:"expires_at"`
+	LastUsedAt       int64  `protobuf:"varint,8,opt,name=last_used_at,json=lastUsedAt" json:"last_used_at"`
+	IsRevoked        bool   `protobuf:"varint,9,opt,name=is_revoked,json=isRevoked" json:"is_revoked"`
 }
 
-func (c *userServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/Login", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) Reset()                    { *m = APIKey{} }
+func (m *APIKey) String() string            { return proto.CompactTextString(m) }
+func (*APIKey) ProtoMessage()               {}
+func (*APIKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *APIKey) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *userServiceClient) CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/CreateUser", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetServiceAccountId() string {
+	if m != nil {
+		return m.ServiceAccountId
 	}
-	return out, nil
+	return ""
 }
 
-func (c *userServiceClient) ConfirmEmail(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ConfirmEmail", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
 	}
-	return out, nil
+	return ""
 }
 
-func (c *userServiceClient) UpdateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/UpdateUser", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *userServiceClient) GetUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserResponse, error) {
-	out := new(UserResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUser", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetKeyHash() string {
+	if m != nil {
+		return m.KeyHash
 	}
-	return out, nil
+	return ""
 }
 
-func (c *userServiceClient) DeleteUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
-	out := new(grpc_gateway_common.CommonResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/DeleteUser", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
 	}
-	return out, nil
+	return 0
 }
 
-func (c *userServiceClient) GetUsers(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*UserListResponse, error) {
-	out := new(UserListResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUsers", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
 	}
-	return out, nil
+	return 0
 }
 
-func (c *userServiceClient) GetUserByCompany(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserListResponse, error) {
-	out := new(UserListResponse)
-	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUserByCompany", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *APIKey) GetLastUsedAt() int64 {
+	if m != nil {
+		return m.LastUsedAt
 	}
-	return out, nil
+	return 0
 }
 
-// Server API for UserService service
+func (m *APIKey) GetIsRevoked() bool {
+	if m != nil {
+		return m.IsRevoked
+	}
+	return false
+}
 
-type UserServiceServer interface {
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	CreateUser(context.Context, *User) (*UserResponse, error)
-	ConfirmEmail(context.Context, *User) (*UserResponse, error)
-	UpdateUser(context.Context, *User) (*UserResponse, error)
-	GetUser(context.Context, *grpc_gateway_common.IDRequest) (*UserResponse, error)
-	DeleteUser(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
-	GetUsers(context.Context, *google_protobuf1.Empty) (*UserListResponse, error)
-	GetUserByCompany(context.Context, *grpc_gateway_common.IDRequest) (*UserListResponse, error)
+type CreateAPIKeyRequest struct {
+	ServiceAccountId string `protobuf:"bytes,1,opt,name=service_account_id,json=serviceAccountId" json:"service_account_id"`
+	Name             string `protobuf:"bytes,2,opt,name=name" json:"name"`
+	ExpiresAt        int64  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt" json:"expires_at"`
 }
 
-func RegisterUserServiceServer(s *grpc.Server, srv UserServiceServer) {
-	s.RegisterService(&_UserService_serviceDesc, srv)
+func (m *CreateAPIKeyRequest) Reset()                    { *m = CreateAPIKeyRequest{} }
+func (m *CreateAPIKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateAPIKeyRequest) ProtoMessage()               {}
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *CreateAPIKeyRequest) GetServiceAccountId() string {
+	if m != nil {
+		return m.ServiceAccountId
+	}
+	return ""
 }
 
-func _UserService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *CreateAPIKeyRequest) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	if interceptor == nil {
-		return srv.(UserServiceServer).Login(ctx, in)
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/grpc.gateway.user.UserService/Login",
+	return 0
+}
+
+type APIKeyResponse struct {
+	Meta *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data *APIKey                           `protobuf:"bytes,2,opt,name=data" json:"data"`
+	Key  string                            `protobuf:"bytes,3,opt,name=key" json:"key"`
+}
+
+func (m *APIKeyResponse) Reset()                    { *m = APIKeyResponse{} }
+func (m *APIKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*APIKeyResponse) ProtoMessage()               {}
+func (*APIKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *APIKeyResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).Login(ctx, req.(*LoginRequest))
+	return nil
+}
+
+func (m *APIKeyResponse) GetData() *APIKey {
+	if m != nil {
+		return m.Data
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(User)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *APIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
 	}
-	if interceptor == nil {
-		return srv.(UserServiceServer).CreateUser(ctx, in)
+	return ""
+}
+
+type APIKeyListResponse struct {
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*APIKey                         `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
+}
+
+func (m *APIKeyListResponse) Reset()                    { *m = APIKeyListResponse{} }
+func (m *APIKeyListResponse) String() string            { return proto.CompactTextString(m) }
+func (*APIKeyListResponse) ProtoMessage()               {}
+func (*APIKeyListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *APIKeyListResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *APIKeyListResponse) GetData() []*APIKey {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *APIKeyListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *APIKeyListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type SMSConfirmationRequest struct {
+	Code string `protobuf:"bytes,1,opt,name=code" json:"code"`
+}
+
+func (m *SMSConfirmationRequest) Reset()                    { *m = SMSConfirmationRequest{} }
+func (m *SMSConfirmationRequest) String() string            { return proto.CompactTextString(m) }
+func (*SMSConfirmationRequest) ProtoMessage()               {}
+func (*SMSConfirmationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *SMSConfirmationRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type RefreshRequest struct {
+	RefreshToken string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken" json:"refresh_token"`
+}
+
+func (m *RefreshRequest) Reset()                    { *m = RefreshRequest{} }
+func (m *RefreshRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefreshRequest) ProtoMessage()               {}
+func (*RefreshRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *RefreshRequest) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+type InvitationRequest struct {
+	EmailCode string `protobuf:"bytes,1,opt,name=email_code,json=emailCode" json:"email_code"`
+}
+
+func (m *InvitationRequest) Reset()                    { *m = InvitationRequest{} }
+func (m *InvitationRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvitationRequest) ProtoMessage()               {}
+func (*InvitationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *InvitationRequest) GetEmailCode() string {
+	if m != nil {
+		return m.EmailCode
+	}
+	return ""
+}
+
+type Invitation struct {
+	UserId    string `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id"`
+	Email     string `protobuf:"bytes,2,opt,name=email" json:"email"`
+	Name      string `protobuf:"bytes,3,opt,name=name" json:"name"`
+	CompanyId string `protobuf:"bytes,4,opt,name=company_id,json=companyId" json:"company_id"`
+	Role      string `protobuf:"bytes,5,opt,name=role" json:"role"`
+	SentAt    int64  `protobuf:"varint,6,opt,name=sent_at,json=sentAt" json:"sent_at"`
+	ExpiresAt int64  `protobuf:"varint,7,opt,name=expires_at,json=expiresAt" json:"expires_at"`
+	IsExpired bool   `protobuf:"varint,8,opt,name=is_expired,json=isExpired" json:"is_expired"`
+}
+
+func (m *Invitation) Reset()                    { *m = Invitation{} }
+func (m *Invitation) String() string            { return proto.CompactTextString(m) }
+func (*Invitation) ProtoMessage()               {}
+func (*Invitation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *Invitation) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Invitation) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *Invitation) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Invitation) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *Invitation) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *Invitation) GetSentAt() int64 {
+	if m != nil {
+		return m.SentAt
+	}
+	return 0
+}
+
+func (m *Invitation) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Invitation) GetIsExpired() bool {
+	if m != nil {
+		return m.IsExpired
+	}
+	return false
+}
+
+type InvitationListResponse struct {
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*Invitation                     `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
+}
+
+func (m *InvitationListResponse) Reset()                    { *m = InvitationListResponse{} }
+func (m *InvitationListResponse) String() string            { return proto.CompactTextString(m) }
+func (*InvitationListResponse) ProtoMessage()               {}
+func (*InvitationListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *InvitationListResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *InvitationListResponse) GetData() []*Invitation {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *InvitationListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *InvitationListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type ImpersonateRequest struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id"`
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason"`
+}
+
+func (m *ImpersonateRequest) Reset()                    { *m = ImpersonateRequest{} }
+func (m *ImpersonateRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImpersonateRequest) ProtoMessage()               {}
+func (*ImpersonateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ImpersonateRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ImpersonateRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type Session struct {
+	Id                   string `protobuf:"bytes,1,opt,name=id" json:"id"`
+	UserId               string `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"user_id"`
+	RefreshToken         string `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken" json:"refresh_token"`
+	PreviousRefreshToken string `protobuf:"bytes,4,opt,name=previous_refresh_token,json=previousRefreshToken" json:"previous_refresh_token"`
+	CreatedAt            int64  `protobuf:"varint,5,opt,name=created_at,json=createdAt" json:"created_at"`
+	ExpiresAt            int64  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt" json:"expires_at"`
+	IsRevoked            bool   `protobuf:"varint,7,opt,name=is_revoked,json=isRevoked" json:"is_revoked"`
+	UserAgent            string `protobuf:"bytes,8,opt,name=user_agent,json=userAgent" json:"user_agent"`
+	IpAddress            string `protobuf:"bytes,9,opt,name=ip_address,json=ipAddress" json:"ip_address"`
+	LastSeenAt           int64  `protobuf:"varint,10,opt,name=last_seen_at,json=lastSeenAt" json:"last_seen_at"`
+	IsCurrent            bool   `protobuf:"varint,11,opt,name=is_current,json=isCurrent" json:"is_current"`
+	ImpersonatorId       string `protobuf:"bytes,12,opt,name=impersonator_id,json=impersonatorId" json:"impersonator_id"`
+}
+
+func (m *Session) Reset()                    { *m = Session{} }
+func (m *Session) String() string            { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()               {}
+func (*Session) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Session) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Session) GetRefreshToken() string {
+	if m != nil {
+		return m.RefreshToken
+	}
+	return ""
+}
+
+func (m *Session) GetPreviousRefreshToken() string {
+	if m != nil {
+		return m.PreviousRefreshToken
+	}
+	return ""
+}
+
+func (m *Session) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Session) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *Session) GetIsRevoked() bool {
+	if m != nil {
+		return m.IsRevoked
+	}
+	return false
+}
+
+func (m *Session) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *Session) GetIpAddress() string {
+	if m != nil {
+		return m.IpAddress
+	}
+	return ""
+}
+
+func (m *Session) GetLastSeenAt() int64 {
+	if m != nil {
+		return m.LastSeenAt
+	}
+	return 0
+}
+
+func (m *Session) GetIsCurrent() bool {
+	if m != nil {
+		return m.IsCurrent
+	}
+	return false
+}
+
+func (m *Session) GetImpersonatorId() string {
+	if m != nil {
+		return m.ImpersonatorId
+	}
+	return ""
+}
+
+type SessionListResponse struct {
+	Meta       *grpc_gateway_common.MetaResponse `protobuf:"bytes,1,opt,name=meta" json:"meta"`
+	Data       []*Session                        `protobuf:"bytes,2,rep,name=data" json:"data"`
+	NextCursor string                            `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor" json:"next_cursor"`
+	Total      int64                             `protobuf:"varint,4,opt,name=total" json:"total"`
+}
+
+func (m *SessionListResponse) Reset()                    { *m = SessionListResponse{} }
+func (m *SessionListResponse) String() string            { return proto.CompactTextString(m) }
+func (*SessionListResponse) ProtoMessage()               {}
+func (*SessionListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *SessionListResponse) GetMeta() *grpc_gateway_common.MetaResponse {
+	if m != nil {
+		return m.Meta
+	}
+	return nil
+}
+
+func (m *SessionListResponse) GetData() []*Session {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *SessionListResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *SessionListResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+type UserSessionRequest struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId" json:"user_id"`
+	Id     string `protobuf:"bytes,2,opt,name=id" json:"id"`
+}
+
+func (m *UserSessionRequest) Reset()                    { *m = UserSessionRequest{} }
+func (m *UserSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*UserSessionRequest) ProtoMessage()               {}
+func (*UserSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *UserSessionRequest) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *UserSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type User struct {
+	Id                  string                      `protobuf:"bytes,1,opt,name=id" json:"id"`
+	CompanyId           string                      `protobuf:"bytes,2,opt,name=company_id,json=companyId" json:"company_id"`
+	Name                string                      `protobuf:"bytes,4,opt,name=name" json:"name"`
+	Email               string                      `protobuf:"bytes,5,opt,name=email" json:"email"`
+	Password            string                      `protobuf:"bytes,6,opt,name=password" json:"password"`
+	Phone               string                      `protobuf:"bytes,7,opt,name=phone" json:"phone"`
+	IsAdmin             bool                        `protobuf:"varint,3,opt,name=is_admin,json=isAdmin" json:"is_admin"`
+	IsEnabled           bool                        `protobuf:"varint,8,opt,name=is_enabled,json=isEnabled" json:"is_enabled"`
+	IsConfirmed         bool                        `protobuf:"varint,9,opt,name=is_confirmed,json=isConfirmed" json:"is_confirmed"`
+	EmailCode           string                      `protobuf:"bytes,10,opt,name=email_code,json=emailCode" json:"email_code"`
+	SmsCode             string                      `protobuf:"bytes,11,opt,name=sms_code,json=smsCode" json:"sms_code"`
+	EmailSentAt         *google_protobuf2.Timestamp `protobuf:"bytes,12,opt,name=email_sent_at,json=emailSentAt" json:"email_sent_at"`
+	SmsSentAt           *google_protobuf2.Timestamp `protobuf:"bytes,13,opt,name=sms_sent_at,json=smsSentAt" json:"sms_sent_at"`
+	PreferredFactor     string                      `protobuf:"bytes,14,opt,name=preferred_factor,json=preferredFactor" json:"preferred_factor"`
+	TotpEnabled         bool                        `protobuf:"varint,15,opt,name=totp_enabled,json=totpEnabled" json:"totp_enabled"`
+	TotpSecret          string                      `protobuf:"bytes,16,opt,name=totp_secret,json=totpSecret" json:"totp_secret"`
+	TotpPendingSecret   string                      `protobuf:"bytes,17,opt,name=totp_pending_secret,json=totpPendingSecret" json:"totp_pending_secret"`
+	TotpLastStep        int64                       `protobuf:"varint,18,opt,name=totp_last_step,json=totpLastStep" json:"totp_last_step"`
+	PasswordResetToken  string                      `protobuf:"bytes,19,opt,name=password_reset_token,json=passwordResetToken" json:"password_reset_token"`
+	PasswordResetSentAt *google_protobuf2.Timestamp `protobuf:"bytes,20,opt,name=password_reset_sent_at,json=passwordResetSentAt" json:"password_reset_sent_at"`
+	Role                string                      `protobuf:"bytes,21,opt,name=role" json:"role"`
+	IsServiceAccount    bool                        `protobuf:"varint,22,opt,name=is_service_account,json=isServiceAccount" json:"is_service_account"`
+}
+
+func (m *User) Reset()                    { *m = User{} }
+func (m *User) String() string            { return proto.CompactTextString(m) }
+func (*User) ProtoMessage()               {}
+func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *User) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *User) GetCompanyId() string {
+	if m != nil {
+		return m.CompanyId
+	}
+	return ""
+}
+
+func (m *User) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *User) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *User) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *User) GetPhone() string {
+	if m != nil {
+		return m.Phone
+	}
+	return ""
+}
+
+func (m *User) GetIsAdmin() bool {
+	if m != nil {
+		return m.IsAdmin
+	}
+	return false
+}
+
+func (m *User) GetIsEnabled() bool {
+	if m != nil {
+		return m.IsEnabled
+	}
+	return false
+}
+
+func (m *User) GetIsConfirmed() bool {
+	if m != nil {
+		return m.IsConfirmed
+	}
+	return false
+}
+
+func (m *User) GetEmailCode() string {
+	if m != nil {
+		return m.EmailCode
+	}
+	return ""
+}
+
+func (m *User) GetSmsCode() string {
+	if m != nil {
+		return m.SmsCode
+	}
+	return ""
+}
+
+func (m *User) GetEmailSentAt() *google_protobuf2.Timestamp {
+	if m != nil {
+		return m.EmailSentAt
+	}
+	return nil
+}
+
+func (m *User) GetSmsSentAt() *google_protobuf2.Timestamp {
+	if m != nil {
+		return m.SmsSentAt
+	}
+	return nil
+}
+
+func (m *User) GetPreferredFactor() string {
+	if m != nil {
+		return m.PreferredFactor
+	}
+	return ""
+}
+
+func (m *User) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
+	}
+	return false
+}
+
+func (m *User) GetTotpSecret() string {
+	if m != nil {
+		return m.TotpSecret
+	}
+	return ""
+}
+
+func (m *User) GetTotpPendingSecret() string {
+	if m != nil {
+		return m.TotpPendingSecret
+	}
+	return ""
+}
+
+func (m *User) GetTotpLastStep() int64 {
+	if m != nil {
+		return m.TotpLastStep
+	}
+	return 0
+}
+
+func (m *User) GetPasswordResetToken() string {
+	if m != nil {
+		return m.PasswordResetToken
+	}
+	return ""
+}
+
+func (m *User) GetPasswordResetSentAt() *google_protobuf2.Timestamp {
+	if m != nil {
+		return m.PasswordResetSentAt
+	}
+	return nil
+}
+
+func (m *User) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *User) GetIsServiceAccount() bool {
+	if m != nil {
+		return m.IsServiceAccount
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*LoginResponse)(nil), "grpc.gateway.user.LoginResponse")
+	proto.RegisterType((*UserListResponse)(nil), "grpc.gateway.user.UserListResponse")
+	proto.RegisterType((*UserResponse)(nil), "grpc.gateway.user.UserResponse")
+	proto.RegisterType((*LoginRequest)(nil), "grpc.gateway.user.LoginRequest")
+	proto.RegisterType((*TOTPEnrollmentResponse)(nil), "grpc.gateway.user.TOTPEnrollmentResponse")
+	proto.RegisterType((*TOTPConfirmationRequest)(nil), "grpc.gateway.user.TOTPConfirmationRequest")
+	proto.RegisterType((*PreferredFactorRequest)(nil), "grpc.gateway.user.PreferredFactorRequest")
+	proto.RegisterType((*PasswordResetRequest)(nil), "grpc.gateway.user.PasswordResetRequest")
+	proto.RegisterType((*ResetPasswordRequest)(nil), "grpc.gateway.user.ResetPasswordRequest")
+	proto.RegisterType((*ResetPasswordResponse)(nil), "grpc.gateway.user.ResetPasswordResponse")
+	proto.RegisterType((*ServiceAccountRequest)(nil), "grpc.gateway.user.ServiceAccountRequest")
+	proto.RegisterType((*APIKey)(nil), "grpc.gateway.user.APIKey")
+	proto.RegisterType((*CreateAPIKeyRequest)(nil), "grpc.gateway.user.CreateAPIKeyRequest")
+	proto.RegisterType((*APIKeyResponse)(nil), "grpc.gateway.user.APIKeyResponse")
+	proto.RegisterType((*APIKeyListResponse)(nil), "grpc.gateway.user.APIKeyListResponse")
+	proto.RegisterType((*SMSConfirmationRequest)(nil), "grpc.gateway.user.SMSConfirmationRequest")
+	proto.RegisterType((*RefreshRequest)(nil), "grpc.gateway.user.RefreshRequest")
+	proto.RegisterType((*InvitationRequest)(nil), "grpc.gateway.user.InvitationRequest")
+	proto.RegisterType((*Invitation)(nil), "grpc.gateway.user.Invitation")
+	proto.RegisterType((*InvitationListResponse)(nil), "grpc.gateway.user.InvitationListResponse")
+	proto.RegisterType((*ImpersonateRequest)(nil), "grpc.gateway.user.ImpersonateRequest")
+	proto.RegisterType((*Session)(nil), "grpc.gateway.user.Session")
+	proto.RegisterType((*SessionListResponse)(nil), "grpc.gateway.user.SessionListResponse")
+	proto.RegisterType((*UserSessionRequest)(nil), "grpc.gateway.user.UserSessionRequest")
+	proto.RegisterType((*User)(nil), "grpc.gateway.user.User")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for UserService service
+
+type UserServiceClient interface {
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Logout(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error)
+	EnrollTOTP(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*TOTPEnrollmentResponse, error)
+	ConfirmTOTP(ctx context.Context, in *TOTPConfirmationRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	SetPreferredFactor(ctx context.Context, in *PreferredFactorRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
+	ConfirmEmail(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
+	RenewInvitation(ctx context.Context, in *InvitationRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	ResendInvitation(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	ListPendingInvitations(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*InvitationListResponse, error)
+	UpdateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error)
+	GetUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	DeleteUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	UnlockUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	ListSessions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*SessionListResponse, error)
+	RevokeSession(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	ListUserSessions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*SessionListResponse, error)
+	RevokeUserSession(ctx context.Context, in *UserSessionRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	CreateServiceAccount(ctx context.Context, in *ServiceAccountRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*APIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*APIKeyListResponse, error)
+	RevokeAPIKey(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error)
+	GetUsers(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*UserListResponse, error)
+	GetUserByCompany(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*UserListResponse, error)
+}
+
+type userServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewUserServiceClient(cc *grpc.ClientConn) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/Login", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/Refresh", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Logout(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/Logout", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/RequestPasswordReset", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error) {
+	out := new(ResetPasswordResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ResetPassword", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) EnrollTOTP(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*TOTPEnrollmentResponse, error) {
+	out := new(TOTPEnrollmentResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/EnrollTOTP", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmTOTP(ctx context.Context, in *TOTPConfirmationRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ConfirmTOTP", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) SetPreferredFactor(ctx context.Context, in *PreferredFactorRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/SetPreferredFactor", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/CreateUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ConfirmEmail(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ConfirmEmail", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RenewInvitation(ctx context.Context, in *InvitationRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/RenewInvitation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ResendInvitation(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ResendInvitation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListPendingInvitations(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*InvitationListResponse, error) {
+	out := new(InvitationListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ListPendingInvitations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/UpdateUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/DeleteUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UnlockUser(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/UnlockUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/Impersonate", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListSessions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*SessionListResponse, error) {
+	out := new(SessionListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ListSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeSession(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/RevokeSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListUserSessions(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*SessionListResponse, error) {
+	out := new(SessionListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ListUserSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeUserSession(ctx context.Context, in *UserSessionRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/RevokeUserSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateServiceAccount(ctx context.Context, in *ServiceAccountRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/CreateServiceAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*APIKeyResponse, error) {
+	out := new(APIKeyResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/CreateAPIKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) ListAPIKeys(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*APIKeyListResponse, error) {
+	out := new(APIKeyListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/ListAPIKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) RevokeAPIKey(ctx context.Context, in *grpc_gateway_common.IDRequest, opts ...grpc.CallOption) (*grpc_gateway_common.CommonResponse, error) {
+	out := new(grpc_gateway_common.CommonResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/RevokeAPIKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUsers(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*UserListResponse, error) {
+	out := new(UserListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUsers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUserByCompany(ctx context.Context, in *grpc_gateway_common.ListRequest, opts ...grpc.CallOption) (*UserListResponse, error) {
+	out := new(UserListResponse)
+	err := grpc.Invoke(ctx, "/grpc.gateway.user.UserService/GetUserByCompany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for UserService service
+
+type UserServiceServer interface {
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	Refresh(context.Context, *RefreshRequest) (*LoginResponse, error)
+	Logout(context.Context, *google_protobuf1.Empty) (*grpc_gateway_common.CommonResponse, error)
+	RequestPasswordReset(context.Context, *PasswordResetRequest) (*grpc_gateway_common.CommonResponse, error)
+	ResetPassword(context.Context, *ResetPasswordRequest) (*ResetPasswordResponse, error)
+	EnrollTOTP(context.Context, *google_protobuf1.Empty) (*TOTPEnrollmentResponse, error)
+	ConfirmTOTP(context.Context, *TOTPConfirmationRequest) (*grpc_gateway_common.CommonResponse, error)
+	SetPreferredFactor(context.Context, *PreferredFactorRequest) (*grpc_gateway_common.CommonResponse, error)
+	CreateUser(context.Context, *User) (*UserResponse, error)
+	ConfirmEmail(context.Context, *User) (*UserResponse, error)
+	RenewInvitation(context.Context, *InvitationRequest) (*grpc_gateway_common.CommonResponse, error)
+	ResendInvitation(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	ListPendingInvitations(context.Context, *grpc_gateway_common.ListRequest) (*InvitationListResponse, error)
+	UpdateUser(context.Context, *User) (*UserResponse, error)
+	GetUser(context.Context, *grpc_gateway_common.IDRequest) (*UserResponse, error)
+	DeleteUser(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	UnlockUser(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	Impersonate(context.Context, *ImpersonateRequest) (*LoginResponse, error)
+	ListSessions(context.Context, *grpc_gateway_common.ListRequest) (*SessionListResponse, error)
+	RevokeSession(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	ListUserSessions(context.Context, *grpc_gateway_common.ListRequest) (*SessionListResponse, error)
+	RevokeUserSession(context.Context, *UserSessionRequest) (*grpc_gateway_common.CommonResponse, error)
+	CreateServiceAccount(context.Context, *ServiceAccountRequest) (*UserResponse, error)
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*APIKeyResponse, error)
+	ListAPIKeys(context.Context, *grpc_gateway_common.ListRequest) (*APIKeyListResponse, error)
+	RevokeAPIKey(context.Context, *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error)
+	GetUsers(context.Context, *grpc_gateway_common.ListRequest) (*UserListResponse, error)
+	GetUserByCompany(context.Context, *grpc_gateway_common.ListRequest) (*UserListResponse, error)
+}
+
+func RegisterUserServiceServer(s *grpc.Server, srv UserServiceServer) {
+	s.RegisterService(&_UserService_serviceDesc, srv)
+}
+
+func _UserService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Logout(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/RequestPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RequestPasswordReset(ctx, req.(*PasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).EnrollTOTP(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TOTPConfirmationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ConfirmTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ConfirmTOTP(ctx, req.(*TOTPConfirmationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_SetPreferredFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreferredFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetPreferredFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/SetPreferredFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetPreferredFactor(ctx, req.(*PreferredFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
@@ -455,6 +1655,60 @@ func _UserService_ConfirmEmail_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_RenewInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RenewInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/RenewInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RenewInvitation(ctx, req.(*InvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ResendInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ResendInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ResendInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ResendInvitation(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListPendingInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListPendingInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ListPendingInvitations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListPendingInvitations(ctx, req.(*grpc_gateway_common.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(User)
 	if err := dec(in); err != nil {
@@ -509,8 +1763,188 @@ func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/UnlockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UnlockUser(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_Impersonate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpersonateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).Impersonate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/Impersonate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).Impersonate(ctx, req.(*ImpersonateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListSessions(ctx, req.(*grpc_gateway_common.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeSession(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ListUserSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUserSessions(ctx, req.(*grpc_gateway_common.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeUserSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeUserSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/RevokeUserSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeUserSession(ctx, req.(*UserSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateServiceAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServiceAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateServiceAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/CreateServiceAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateServiceAccount(ctx, req.(*ServiceAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/CreateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/ListAPIKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListAPIKeys(ctx, req.(*grpc_gateway_common.ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(grpc_gateway_common.IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.gateway.user.UserService/RevokeAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RevokeAPIKey(ctx, req.(*grpc_gateway_common.IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _UserService_GetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf1.Empty)
+	in := new(grpc_gateway_common.ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -522,13 +1956,13 @@ func _UserService_GetUsers_Handler(srv interface{}, ctx context.Context, dec fun
 		FullMethod: "/grpc.gateway.user.UserService/GetUsers",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).GetUsers(ctx, req.(*google_protobuf1.Empty))
+		return srv.(UserServiceServer).GetUsers(ctx, req.(*grpc_gateway_common.ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _UserService_GetUserByCompany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(grpc_gateway_common.IDRequest)
+	in := new(grpc_gateway_common.ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -540,7 +1974,7 @@ func _UserService_GetUserByCompany_Handler(srv interface{}, ctx context.Context,
 		FullMethod: "/grpc.gateway.user.UserService/GetUserByCompany",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).GetUserByCompany(ctx, req.(*grpc_gateway_common.IDRequest))
+		return srv.(UserServiceServer).GetUserByCompany(ctx, req.(*grpc_gateway_common.ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -553,6 +1987,34 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Login",
 			Handler:    _UserService_Login_Handler,
 		},
+		{
+			MethodName: "Refresh",
+			Handler:    _UserService_Refresh_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _UserService_Logout_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _UserService_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _UserService_ResetPassword_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _UserService_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _UserService_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "SetPreferredFactor",
+			Handler:    _UserService_SetPreferredFactor_Handler,
+		},
 		{
 			MethodName: "CreateUser",
 			Handler:    _UserService_CreateUser_Handler,
@@ -561,6 +2023,18 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ConfirmEmail",
 			Handler:    _UserService_ConfirmEmail_Handler,
 		},
+		{
+			MethodName: "RenewInvitation",
+			Handler:    _UserService_RenewInvitation_Handler,
+		},
+		{
+			MethodName: "ResendInvitation",
+			Handler:    _UserService_ResendInvitation_Handler,
+		},
+		{
+			MethodName: "ListPendingInvitations",
+			Handler:    _UserService_ListPendingInvitations_Handler,
+		},
 		{
 			MethodName: "UpdateUser",
 			Handler:    _UserService_UpdateUser_Handler,
@@ -573,6 +2047,46 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUser",
 			Handler:    _UserService_DeleteUser_Handler,
 		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _UserService_UnlockUser_Handler,
+		},
+		{
+			MethodName: "Impersonate",
+			Handler:    _UserService_Impersonate_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _UserService_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _UserService_RevokeSession_Handler,
+		},
+		{
+			MethodName: "ListUserSessions",
+			Handler:    _UserService_ListUserSessions_Handler,
+		},
+		{
+			MethodName: "RevokeUserSession",
+			Handler:    _UserService_RevokeUserSession_Handler,
+		},
+		{
+			MethodName: "CreateServiceAccount",
+			Handler:    _UserService_CreateServiceAccount_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _UserService_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _UserService_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _UserService_RevokeAPIKey_Handler,
+		},
 		{
 			MethodName: "GetUsers",
 			Handler:    _UserService_GetUsers_Handler,
@@ -589,52 +2103,140 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("proto/user/user.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 748 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdd, 0x4e, 0x1b, 0x39,
-	0x14, 0x56, 0x42, 0x7e, 0x4f, 0x12, 0x96, 0xf5, 0x02, 0x3b, 0x64, 0x77, 0x21, 0x0c, 0x37, 0x2c,
-	0xbb, 0x9d, 0x51, 0xa9, 0x7a, 0xc3, 0x45, 0x25, 0x08, 0xa8, 0x42, 0x82, 0x9b, 0xa4, 0x5c, 0xb4,
-	0x54, 0x0a, 0x4e, 0xc6, 0x04, 0xab, 0x99, 0xf1, 0x74, 0x6c, 0x40, 0x11, 0xea, 0x4d, 0x5f, 0xa1,
-	0xaf, 0xd0, 0x67, 0xe9, 0x0b, 0xf4, 0x15, 0xfa, 0x20, 0x95, 0x8f, 0x3d, 0x40, 0x42, 0xa2, 0xa8,
-	0xa5, 0x37, 0x99, 0x39, 0x3e, 0x3e, 0xdf, 0xe7, 0xef, 0x9c, 0x6f, 0x1c, 0x58, 0x8a, 0x13, 0xa1,
-	0x84, 0x7f, 0x29, 0x59, 0x82, 0x3f, 0x1e, 0xc6, 0xe4, 0xf7, 0x7e, 0x12, 0xf7, 0xbc, 0x3e, 0x55,
-	0xec, 0x9a, 0x0e, 0x3d, 0x9d, 0xa8, 0xff, 0xdd, 0x17, 0xa2, 0x3f, 0x60, 0x3e, 0x8d, 0xb9, 0x4f,
-	0xa3, 0x48, 0x28, 0xaa, 0xb8, 0x88, 0xa4, 0x29, 0xa8, 0xaf, 0x18, 0x9c, 0x9e, 0x08, 0x43, 0x11,
-	0xd9, 0x87, 0x4d, 0xfd, 0x65, 0x0b, 0x31, 0xea, 0x5e, 0x9e, 0xfb, 0x2c, 0x8c, 0xd5, 0xd0, 0x26,
-	0xd7, 0xc6, 0x93, 0x8a, 0x87, 0x4c, 0x2a, 0x1a, 0xc6, 0x66, 0x83, 0xfb, 0x16, 0x6a, 0x47, 0xa2,
-	0xcf, 0xa3, 0x16, 0x93, 0xb1, 0x88, 0x24, 0x23, 0xcf, 0x21, 0x17, 0x32, 0x45, 0x9d, 0x4c, 0x23,
-	0xb3, 0x59, 0xd9, 0x5e, 0xf7, 0x46, 0x4e, 0x6a, 0x89, 0x8f, 0x99, 0xa2, 0x69, 0x41, 0x0b, 0xb7,
-	0x93, 0x45, 0xc8, 0x2b, 0xf1, 0x8e, 0x45, 0x4e, 0xb6, 0x91, 0xd9, 0x2c, 0xb7, 0x4c, 0xe0, 0x5e,
-	0xc1, 0xc2, 0x89, 0x64, 0xc9, 0x11, 0x97, 0xea, 0xb1, 0x04, 0xff, 0x41, 0x2e, 0xa0, 0x8a, 0x3a,
-	0xd9, 0xc6, 0xdc, 0x66, 0x65, 0xfb, 0x4f, 0xef, 0x41, 0x07, 0x3d, 0xcd, 0xd4, 0xc2, 0x4d, 0x6e,
-	0x02, 0x55, 0x8c, 0x7e, 0x19, 0x67, 0x66, 0x36, 0xe7, 0x29, 0x54, 0x6d, 0x27, 0xdf, 0x5f, 0x32,
-	0xa9, 0x74, 0x47, 0x58, 0x48, 0xf9, 0x00, 0x49, 0xcb, 0x2d, 0x13, 0x90, 0x3a, 0x94, 0x62, 0x2a,
-	0xe5, 0xb5, 0x48, 0x02, 0xdb, 0xaa, 0xdb, 0x98, 0xac, 0x40, 0x49, 0x86, 0xb2, 0xd3, 0x13, 0x01,
-	0x73, 0xe6, 0x30, 0x57, 0x94, 0xa1, 0x6c, 0x8a, 0x80, 0xb9, 0xff, 0xc3, 0x72, 0xfb, 0xb8, 0xdd,
-	0x14, 0xd1, 0x39, 0x4f, 0x42, 0x74, 0x46, 0x4a, 0x43, 0x20, 0x87, 0x05, 0x86, 0x05, 0xdf, 0xdd,
-	0xcf, 0x73, 0x90, 0xd3, 0x27, 0x23, 0xf3, 0x90, 0xe5, 0x81, 0x4d, 0x65, 0x79, 0x40, 0xfe, 0x01,
-	0xe8, 0x89, 0x30, 0xa6, 0xd1, 0xb0, 0xc3, 0x53, 0xfe, 0xb2, 0x5d, 0x39, 0x0c, 0x34, 0x56, 0x44,
-	0x43, 0xe6, 0xe4, 0x0c, 0x96, 0x7e, 0xbf, 0x93, 0x91, 0x9f, 0x26, 0xa3, 0x30, 0x26, 0x63, 0x11,
-	0xf2, 0xf1, 0x85, 0x88, 0x98, 0x53, 0x34, 0x15, 0x18, 0x68, 0x71, 0x5c, 0x76, 0x68, 0x10, 0xf2,
-	0x08, 0xc5, 0x95, 0x5a, 0x45, 0x2e, 0x77, 0x75, 0xa8, 0x4f, 0xc5, 0x65, 0x87, 0x45, 0xb4, 0x3b,
-	0x60, 0x81, 0x53, 0xc2, 0x64, 0x99, 0xcb, 0x03, 0xb3, 0x40, 0xd6, 0xa1, 0xca, 0x75, 0x57, 0x50,
-	0x3b, 0x0b, 0x9c, 0x32, 0x6e, 0xa8, 0x70, 0xd9, 0x4c, 0x97, 0x34, 0x02, 0x9e, 0xcb, 0xf4, 0x0e,
-	0x8c, 0x2e, 0x5c, 0xd1, 0xdd, 0x1b, 0x69, 0x6c, 0x65, 0xa4, 0xb1, 0xe4, 0x05, 0xd4, 0x4c, 0xa5,
-	0x64, 0x91, 0xea, 0x50, 0xe5, 0x54, 0x71, 0xd6, 0x75, 0xcf, 0x7c, 0x38, 0x5e, 0xfa, 0xe1, 0x78,
-	0xaf, 0xd2, 0x0f, 0xa7, 0x55, 0xc1, 0x82, 0x36, 0x8b, 0xd4, 0xae, 0x22, 0x3b, 0x50, 0xd1, 0xd0,
-	0x69, 0x75, 0x6d, 0x66, 0x75, 0x59, 0x86, 0xd2, 0xd4, 0x6e, 0x7f, 0x29, 0x40, 0x45, 0x8f, 0xa9,
-	0xcd, 0x92, 0x2b, 0xde, 0x63, 0xe4, 0x0c, 0xf2, 0xe8, 0x20, 0xb2, 0x36, 0xc1, 0x69, 0xf7, 0xbd,
-	0x55, 0x6f, 0x4c, 0xdf, 0x60, 0x0c, 0xec, 0x2e, 0x7e, 0xfc, 0xfa, 0xed, 0x53, 0x76, 0xde, 0x2d,
-	0xfb, 0x57, 0x4f, 0xfd, 0x81, 0x4e, 0xed, 0x64, 0xb6, 0xc8, 0x29, 0x40, 0x33, 0x61, 0x54, 0x31,
-	0x74, 0xc7, 0x34, 0x43, 0xd7, 0xd7, 0xa6, 0x39, 0x3d, 0x45, 0xff, 0x03, 0xd1, 0x6b, 0x6e, 0x49,
-	0xa3, 0xeb, 0xb4, 0x06, 0x97, 0x50, 0xb5, 0x13, 0x39, 0x40, 0x8f, 0xfc, 0x3c, 0xfc, 0xbf, 0x08,
-	0xbf, 0xe1, 0xae, 0x6a, 0x78, 0x3b, 0xf8, 0x27, 0xd8, 0x75, 0xff, 0xe6, 0x6e, 0xce, 0x1f, 0x34,
-	0xe9, 0x19, 0xc0, 0x49, 0x1c, 0x3c, 0x5e, 0x91, 0x83, 0x94, 0xc4, 0xad, 0xa5, 0x8a, 0xfc, 0x1b,
-	0x1e, 0x20, 0x03, 0x85, 0xe2, 0x4b, 0xa6, 0x10, 0x7e, 0x75, 0xe2, 0xc5, 0x71, 0xb8, 0x9f, 0x8e,
-	0x65, 0x26, 0xcb, 0x12, 0xb2, 0xfc, 0x46, 0x46, 0x59, 0xc8, 0x05, 0xc0, 0x3e, 0x1b, 0x30, 0x2b,
-	0x62, 0x16, 0xcb, 0xc6, 0xc4, 0x7c, 0x13, 0x1f, 0xe3, 0x4c, 0x5b, 0x63, 0x4c, 0xaf, 0xa1, 0x64,
-	0xc5, 0x48, 0xb2, 0xfc, 0xc0, 0xa5, 0x07, 0xfa, 0x9f, 0x63, 0x1c, 0xff, 0x56, 0xc5, 0xfd, 0x5b,
-	0xdc, 0x5d, 0x40, 0x7c, 0x20, 0xb7, 0x0e, 0x20, 0x43, 0x58, 0xb0, 0xd0, 0x7b, 0xc3, 0xa6, 0xb9,
-	0x52, 0x7e, 0x54, 0xca, 0x64, 0xaa, 0x06, 0x52, 0xd5, 0x89, 0x93, 0x52, 0x75, 0xba, 0xc3, 0x8e,
-	0xbd, 0xb4, 0x50, 0xd5, 0x5e, 0xe1, 0x4d, 0x4e, 0xaf, 0x77, 0x0b, 0xa8, 0xe4, 0xd9, 0xf7, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xdf, 0x5b, 0x02, 0x38, 0x76, 0x07, 0x00, 0x00,
+	// 2156 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4f, 0x6f, 0x23, 0x49,
+	0x15, 0x57, 0x3b, 0x89, 0x13, 0x3f, 0x3b, 0x89, 0x53, 0x71, 0x3c, 0x8e, 0x97, 0x99, 0x38, 0x95,
+	0x99, 0x9d, 0x24, 0x4c, 0xec, 0xd9, 0x81, 0xb9, 0x2c, 0x02, 0x29, 0x9b, 0x0d, 0x10, 0x31, 0xab,
+	0x8d, 0x9c, 0x99, 0x0b, 0x20, 0x7a, 0x7b, 0xdc, 0x95, 0xa4, 0x15, 0xbb, 0xbb, 0xe9, 0x2a, 0x67,
+	0xc6, 0x0a, 0x23, 0xed, 0x82, 0x90, 0x96, 0x03, 0x17, 0x56, 0xe2, 0x33, 0x70, 0x01, 0x09, 0x71,
+	0xe2, 0x03, 0xf0, 0x09, 0x38, 0x71, 0xe7, 0x83, 0xa0, 0x7a, 0x55, 0x65, 0x77, 0xdb, 0xed, 0xd8,
+	0xb3, 0x31, 0x97, 0x89, 0xeb, 0x55, 0xd5, 0xfb, 0xbd, 0x7a, 0xff, 0x5f, 0x0f, 0x6c, 0x84, 0x51,
+	0x20, 0x82, 0x46, 0x97, 0xb3, 0x08, 0xff, 0xa9, 0xe3, 0x9a, 0xac, 0x5d, 0x44, 0x61, 0xab, 0x7e,
+	0xe1, 0x08, 0xf6, 0xc6, 0xe9, 0xd5, 0xe5, 0x46, 0xf5, 0x3b, 0x17, 0x41, 0x70, 0xd1, 0x66, 0x0d,
+	0x27, 0xf4, 0x1a, 0x8e, 0xef, 0x07, 0xc2, 0x11, 0x5e, 0xe0, 0x73, 0x75, 0xa1, 0xba, 0xa9, 0xf8,
+	0xb4, 0x82, 0x4e, 0x27, 0xf0, 0xf5, 0x1f, 0xbd, 0xf5, 0x81, 0xbe, 0x88, 0xab, 0xd7, 0xdd, 0xf3,
+	0x06, 0xeb, 0x84, 0xa2, 0xa7, 0x37, 0xb7, 0x86, 0x37, 0x85, 0xd7, 0x61, 0x5c, 0x38, 0x9d, 0x50,
+	0x1d, 0xa0, 0xff, 0xb2, 0x60, 0xf9, 0x45, 0x70, 0xe1, 0xf9, 0x4d, 0xc6, 0xc3, 0xc0, 0xe7, 0x8c,
+	0x3c, 0x87, 0xf9, 0x0e, 0x13, 0x4e, 0xc5, 0xaa, 0x59, 0xbb, 0xf9, 0x67, 0xdb, 0xf5, 0x84, 0xa8,
+	0x1a, 0xf9, 0x33, 0x26, 0x1c, 0x73, 0xa1, 0x89, 0xc7, 0x49, 0x09, 0x16, 0x44, 0x70, 0xc5, 0xfc,
+	0x4a, 0xa6, 0x66, 0xed, 0xe6, 0x9a, 0x6a, 0x41, 0x76, 0x60, 0x39, 0x62, 0xe7, 0x11, 0xe3, 0x97,
+	0xb6, 0xda, 0x9d, 0xc3, 0xdd, 0x82, 0x26, 0xbe, 0xc4, 0x43, 0xf7, 0x01, 0xd8, 0xdb, 0xd0, 0x8b,
+	0x18, 0xb7, 0x3d, 0xbf, 0x32, 0x5f, 0xb3, 0x76, 0xe7, 0x9a, 0x39, 0x4d, 0x39, 0x41, 0x1e, 0x9c,
+	0xb5, 0x02, 0xdf, 0xb5, 0xcf, 0x9d, 0x96, 0x08, 0xa2, 0xca, 0x82, 0xe2, 0xa1, 0x88, 0x3f, 0x46,
+	0x1a, 0xfd, 0xab, 0x05, 0xc5, 0x57, 0x9c, 0x45, 0x2f, 0x3c, 0x2e, 0xee, 0xfa, 0x94, 0xef, 0xc2,
+	0xbc, 0xeb, 0x08, 0xa7, 0x92, 0xa9, 0xcd, 0xed, 0xe6, 0x9f, 0xdd, 0xab, 0x8f, 0x18, 0xab, 0x2e,
+	0x91, 0x9a, 0x78, 0x88, 0x6c, 0x41, 0xde, 0x67, 0x6f, 0x85, 0xdd, 0xea, 0x46, 0x3c, 0x88, 0xf4,
+	0xfb, 0x40, 0x92, 0x8e, 0x90, 0xa2, 0x14, 0x23, 0x9c, 0xb6, 0x7e, 0x98, 0x5a, 0xd0, 0x08, 0x0a,
+	0xc8, 0x64, 0x66, 0xa2, 0x5a, 0x13, 0x45, 0xa5, 0x6f, 0xa1, 0xa0, 0x4d, 0xfd, 0xeb, 0x2e, 0xe3,
+	0x42, 0x4a, 0xc6, 0x3a, 0x8e, 0xd7, 0x46, 0xd0, 0x5c, 0x53, 0x2d, 0x48, 0x15, 0x96, 0x42, 0x87,
+	0xf3, 0x37, 0x41, 0xe4, 0x6a, 0x5b, 0xf6, 0xd7, 0x64, 0x13, 0x96, 0x78, 0x87, 0xdb, 0xad, 0xc0,
+	0x65, 0xfa, 0xa5, 0x8b, 0xbc, 0xc3, 0x8f, 0x02, 0x97, 0x91, 0x0f, 0x20, 0x27, 0x02, 0x11, 0xaa,
+	0xbd, 0x79, 0x75, 0x4f, 0x12, 0xe4, 0x26, 0xed, 0x41, 0xf9, 0xe5, 0xe7, 0x2f, 0x4f, 0x8f, 0xfd,
+	0x28, 0x68, 0xb7, 0x3b, 0xcc, 0xbf, 0xb3, 0x89, 0xca, 0x90, 0xe5, 0xac, 0x15, 0x31, 0xa1, 0x45,
+	0xd4, 0x2b, 0x52, 0x84, 0xb9, 0x6e, 0xe4, 0x69, 0xd9, 0xe4, 0x4f, 0x7a, 0x00, 0xf7, 0x24, 0xf4,
+	0x51, 0xe0, 0x9f, 0x7b, 0x51, 0x07, 0x83, 0xca, 0xbc, 0x9f, 0xc0, 0x3c, 0x4a, 0xab, 0x9e, 0x8f,
+	0xbf, 0xe9, 0x53, 0x28, 0x9f, 0x46, 0xec, 0x9c, 0x45, 0x11, 0xd3, 0xae, 0x65, 0x4e, 0x97, 0x21,
+	0xab, 0xfd, 0x4f, 0x9d, 0xd7, 0x2b, 0xfa, 0x04, 0x4a, 0xa7, 0x5a, 0x3f, 0x4d, 0xc6, 0x99, 0xb8,
+	0x55, 0xbb, 0xf4, 0x4b, 0x0b, 0x4a, 0x78, 0x6c, 0x70, 0xa7, 0x7f, 0x5c, 0x45, 0x88, 0x15, 0x8f,
+	0x9f, 0xff, 0x87, 0x31, 0x38, 0x6c, 0x0c, 0x49, 0x70, 0x37, 0x5b, 0x8c, 0xc4, 0x67, 0x26, 0x25,
+	0x3e, 0x7f, 0x05, 0x1b, 0x67, 0x2c, 0xba, 0xf6, 0x5a, 0xec, 0xb0, 0xd5, 0x0a, 0xba, 0xbe, 0x88,
+	0x19, 0xc1, 0x77, 0x3a, 0x7d, 0x23, 0xc8, 0xdf, 0x32, 0x21, 0xb4, 0x82, 0x4e, 0xe8, 0xf8, 0x3d,
+	0xdb, 0x33, 0xef, 0xce, 0x69, 0xca, 0x89, 0x2b, 0xaf, 0x44, 0x41, 0xdb, 0x3c, 0x1a, 0x7f, 0xd3,
+	0x6f, 0x32, 0x90, 0x3d, 0x3c, 0x3d, 0xf9, 0x19, 0xeb, 0x91, 0x15, 0xc8, 0x78, 0xae, 0xe6, 0x97,
+	0xf1, 0x5c, 0xf2, 0x04, 0x08, 0x57, 0xd0, 0xb6, 0xa3, 0xb0, 0x07, 0x5c, 0x8b, 0x3c, 0x21, 0xd4,
+	0x89, 0x3b, 0x84, 0x3d, 0x97, 0x82, 0x8d, 0xe2, 0xce, 0xc7, 0xc4, 0xdd, 0x84, 0xa5, 0x2b, 0xd6,
+	0xb3, 0x2f, 0x1d, 0x7e, 0xa9, 0x73, 0xd3, 0xe2, 0x15, 0xeb, 0xfd, 0xd4, 0xe1, 0x97, 0xc8, 0x2d,
+	0x62, 0x8e, 0x60, 0xae, 0xed, 0x88, 0x4a, 0x56, 0xa5, 0x36, 0x4d, 0x39, 0x14, 0xf1, 0xcc, 0xe7,
+	0x88, 0xca, 0x62, 0x22, 0xf3, 0x1d, 0x0a, 0x52, 0x83, 0x42, 0xdb, 0xe1, 0xc2, 0xee, 0x72, 0x75,
+	0x7f, 0x09, 0x0f, 0x80, 0xa4, 0xbd, 0xe2, 0x86, 0x81, 0xc7, 0xed, 0x88, 0x5d, 0x07, 0x57, 0xcc,
+	0xad, 0xe4, 0x6a, 0xd6, 0xee, 0x52, 0x33, 0xe7, 0xf1, 0xa6, 0x22, 0xd0, 0x6b, 0x58, 0x3f, 0x42,
+	0x30, 0xa5, 0x1a, 0xa3, 0xf3, 0x74, 0x8d, 0x58, 0x63, 0x34, 0x62, 0x9e, 0x9c, 0x49, 0x5a, 0x28,
+	0x26, 0xf8, 0xdc, 0x90, 0xe0, 0xf4, 0x6b, 0x0b, 0x56, 0x0c, 0xe4, 0xdd, 0x9c, 0xeb, 0x20, 0x91,
+	0xe0, 0x36, 0x53, 0x12, 0x9c, 0xc6, 0xc1, 0x63, 0x32, 0xfe, 0xaf, 0x58, 0xcf, 0xc4, 0xff, 0x15,
+	0xeb, 0xd1, 0xbf, 0x5b, 0x40, 0xd4, 0x91, 0x59, 0x94, 0x86, 0x83, 0x44, 0x69, 0x98, 0x28, 0xce,
+	0xb7, 0x2c, 0x0e, 0x4f, 0xa0, 0x7c, 0xf6, 0xd9, 0xd9, 0xb4, 0x29, 0xeb, 0x39, 0xac, 0x34, 0x55,
+	0x39, 0x35, 0xa7, 0x46, 0xaa, 0xae, 0x35, 0x5a, 0x75, 0xe9, 0x33, 0x58, 0x3b, 0xf1, 0xaf, 0x3d,
+	0x91, 0xe0, 0x2f, 0xed, 0x2a, 0xf3, 0x94, 0x1d, 0x43, 0xc9, 0x21, 0x05, 0x53, 0xc7, 0x7f, 0x2c,
+	0x80, 0xc1, 0x25, 0x72, 0x0f, 0x16, 0xe5, 0x9b, 0x07, 0xce, 0x93, 0x95, 0xcb, 0x13, 0x77, 0x90,
+	0xfb, 0x32, 0xf1, 0xca, 0x62, 0x1c, 0x69, 0x6e, 0x6c, 0xa8, 0xcf, 0x8f, 0x0b, 0xf5, 0x85, 0x41,
+	0xa8, 0x4b, 0x54, 0xce, 0x7c, 0x31, 0x08, 0xa8, 0xac, 0x5c, 0x4e, 0x8e, 0x26, 0x15, 0x2b, 0x6a,
+	0xed, 0x56, 0x96, 0x4c, 0xac, 0x1c, 0x2b, 0x02, 0xfd, 0xa7, 0x05, 0xe5, 0xc1, 0xdb, 0x66, 0xe1,
+	0x2c, 0x1f, 0x25, 0x9c, 0xe5, 0x7e, 0x8a, 0xb3, 0xc4, 0x0c, 0x70, 0x27, 0x87, 0x39, 0x06, 0x72,
+	0xd2, 0x09, 0x59, 0xc4, 0x03, 0xdf, 0x11, 0xcc, 0x18, 0x73, 0xac, 0x79, 0xca, 0x90, 0x8d, 0x98,
+	0xc3, 0x03, 0xd3, 0xac, 0xe9, 0x15, 0xfd, 0xd3, 0x1c, 0x2c, 0x9e, 0x31, 0xce, 0xa5, 0x6d, 0x87,
+	0xb3, 0x68, 0x8c, 0x59, 0x26, 0xc1, 0x6c, 0xaa, 0x16, 0xef, 0xfb, 0x50, 0x0e, 0x23, 0x76, 0xed,
+	0x05, 0x5d, 0x6e, 0xeb, 0x0d, 0x7d, 0x5a, 0x99, 0xbc, 0x64, 0x76, 0x9b, 0x43, 0x8d, 0x61, 0x2c,
+	0x7b, 0x2e, 0xdc, 0x9e, 0x3d, 0xb3, 0xe9, 0xf6, 0x36, 0xb9, 0x71, 0x71, 0x28, 0x37, 0xca, 0x6d,
+	0x7c, 0x90, 0x73, 0xc1, 0x7c, 0x95, 0x5a, 0x73, 0xcd, 0x9c, 0xa4, 0x1c, 0x4a, 0x02, 0xde, 0x0e,
+	0x6d, 0xc7, 0x75, 0x23, 0xc6, 0x39, 0x66, 0xd6, 0x5c, 0x33, 0xe7, 0x85, 0x87, 0x8a, 0xd0, 0x4f,
+	0xcd, 0x9c, 0x31, 0x5f, 0xa2, 0xc3, 0x20, 0x35, 0x9f, 0x31, 0xe6, 0xf7, 0xe1, 0x5b, 0xdd, 0x28,
+	0x92, 0xfc, 0xf3, 0x06, 0xfe, 0x48, 0x11, 0xc8, 0x63, 0x58, 0xf5, 0xfa, 0x26, 0x0b, 0x50, 0xaf,
+	0x05, 0x04, 0x59, 0x89, 0x93, 0x4f, 0x5c, 0xfa, 0x0f, 0x0b, 0xd6, 0xb5, 0x51, 0x66, 0xe1, 0x94,
+	0xf5, 0x84, 0x53, 0x56, 0x53, 0x9c, 0x52, 0x83, 0xdd, 0xcd, 0x23, 0x7f, 0x08, 0x44, 0x76, 0x9e,
+	0x86, 0xd7, 0x24, 0x8f, 0x54, 0xde, 0x96, 0x31, 0xde, 0x46, 0xff, 0x96, 0x85, 0x79, 0x79, 0x7f,
+	0xc4, 0x0d, 0x27, 0xb7, 0x06, 0x23, 0xe5, 0xb9, 0x9f, 0x8c, 0x16, 0xc6, 0xb5, 0xb9, 0xd9, 0xa1,
+	0xce, 0xaa, 0x04, 0x0b, 0xe1, 0x65, 0xe0, 0x33, 0x74, 0x9a, 0x5c, 0x53, 0x2d, 0x64, 0x99, 0xf7,
+	0xb8, 0xed, 0xb8, 0x1d, 0x4f, 0xf9, 0xf8, 0x52, 0x73, 0xd1, 0xe3, 0x87, 0x72, 0x69, 0x52, 0x8b,
+	0xef, 0xbc, 0x6e, 0x27, 0x52, 0x8b, 0x22, 0x90, 0x6d, 0x28, 0x48, 0x57, 0x50, 0xf9, 0xbc, 0x5f,
+	0xa7, 0xf3, 0x1e, 0x3f, 0x32, 0xa4, 0xa1, 0xc4, 0x0b, 0x43, 0x89, 0x37, 0xd1, 0xeb, 0xe5, 0x93,
+	0xbd, 0xde, 0x8f, 0x60, 0x59, 0xdd, 0x34, 0x49, 0xb1, 0x80, 0x0e, 0x51, 0xad, 0xab, 0xd1, 0xaf,
+	0x6e, 0x46, 0xbf, 0xfa, 0x4b, 0x33, 0xfa, 0x35, 0xf3, 0x78, 0xe1, 0x4c, 0x65, 0xcd, 0x8f, 0x21,
+	0x2f, 0x59, 0x9b, 0xdb, 0xcb, 0x13, 0x6f, 0xe7, 0x78, 0x87, 0xeb, 0xbb, 0x7b, 0x50, 0x0c, 0x4d,
+	0xb7, 0x6c, 0xba, 0xbf, 0x15, 0x14, 0x6f, 0x35, 0x4c, 0x76, 0xd1, 0x52, 0x07, 0xd8, 0x92, 0x1a,
+	0x25, 0xad, 0x2a, 0x1d, 0x48, 0x9a, 0x51, 0xd3, 0x16, 0xe0, 0xd2, 0xd6, 0x9d, 0x7d, 0x51, 0xb9,
+	0x9a, 0x24, 0x9d, 0x21, 0x85, 0xd4, 0x61, 0x1d, 0x0f, 0x84, 0xcc, 0x77, 0x3d, 0xff, 0xc2, 0x1c,
+	0x5c, 0xc3, 0x83, 0x6b, 0x72, 0xeb, 0x54, 0xed, 0xe8, 0xf3, 0x0f, 0x61, 0x05, 0xcf, 0xab, 0x48,
+	0x15, 0x2c, 0xac, 0x10, 0xf4, 0x51, 0x94, 0xe4, 0x85, 0x0c, 0x55, 0xc1, 0x42, 0xf2, 0x14, 0x4a,
+	0xc6, 0xf2, 0x76, 0xc4, 0x38, 0x13, 0x3a, 0x33, 0xad, 0x23, 0x5b, 0x12, 0xc6, 0x9b, 0x7b, 0x95,
+	0x97, 0x3e, 0x87, 0xf2, 0xd0, 0x0d, 0xa3, 0xbd, 0xd2, 0x44, 0xed, 0xad, 0x27, 0xf8, 0x69, 0x3d,
+	0x9a, 0x32, 0xb7, 0x11, 0x2b, 0x73, 0x4f, 0x80, 0x78, 0xdc, 0xd6, 0xdd, 0x98, 0xe9, 0xd3, 0x2a,
+	0x65, 0x54, 0x5b, 0xd1, 0xe3, 0xc9, 0x6e, 0xfa, 0xd9, 0x5f, 0xaa, 0x90, 0x57, 0x01, 0x87, 0x64,
+	0xf2, 0x05, 0x2c, 0xe0, 0xac, 0x47, 0xb6, 0x52, 0x22, 0x3c, 0x3e, 0x05, 0x56, 0x6b, 0xe3, 0x0f,
+	0xa8, 0xc4, 0x41, 0x4b, 0xbf, 0xfd, 0xf7, 0x7f, 0xbf, 0xc9, 0xac, 0xd0, 0x5c, 0xe3, 0xfa, 0xa3,
+	0x46, 0x5b, 0x6e, 0x7d, 0x6c, 0xed, 0x93, 0x73, 0x58, 0xd4, 0xc9, 0x9a, 0x6c, 0xa7, 0xb0, 0x48,
+	0xb6, 0x24, 0x53, 0xa0, 0x94, 0x11, 0xa5, 0x48, 0xf3, 0x12, 0x45, 0xd7, 0x08, 0x89, 0xf3, 0x4b,
+	0xc8, 0xbe, 0x08, 0x2e, 0x82, 0xae, 0x20, 0xe5, 0x11, 0xb5, 0x1e, 0xcb, 0x4f, 0x1d, 0xd5, 0x9d,
+	0xd4, 0xdc, 0x77, 0x84, 0x7f, 0xfa, 0xec, 0x37, 0x90, 0xfd, 0x2a, 0x05, 0xfd, 0x88, 0xa0, 0x2b,
+	0x24, 0xf7, 0xdf, 0xe1, 0x3c, 0x86, 0x32, 0x26, 0xa6, 0x38, 0xf2, 0x38, 0x45, 0xe0, 0xb4, 0x39,
+	0x6f, 0x3a, 0xf4, 0xfb, 0x88, 0x7e, 0x8f, 0x12, 0x89, 0x6e, 0x3c, 0xe0, 0x00, 0x7d, 0x47, 0x4a,
+	0xf1, 0x07, 0x0b, 0x96, 0x13, 0x33, 0x59, 0x2a, 0x7c, 0xda, 0xdc, 0x58, 0xdd, 0x9d, 0x7c, 0x50,
+	0xcb, 0xf0, 0x08, 0x65, 0xd8, 0xa2, 0x55, 0xa5, 0x60, 0xce, 0xc4, 0x81, 0x91, 0xa4, 0x71, 0x83,
+	0x2e, 0xff, 0x4e, 0xca, 0x72, 0x05, 0xa0, 0xe6, 0x74, 0x39, 0x36, 0x8f, 0xd5, 0xf9, 0x5e, 0x0a,
+	0x6c, 0xfa, 0x88, 0x4f, 0xab, 0x88, 0x5b, 0xa2, 0xab, 0x12, 0x57, 0x46, 0x5e, 0x83, 0xe1, 0x21,
+	0x09, 0x76, 0x03, 0x79, 0x9d, 0x03, 0x11, 0x6d, 0x7f, 0x0c, 0xd7, 0x94, 0x56, 0x78, 0x3a, 0xbd,
+	0x7f, 0x80, 0xd8, 0x1b, 0xb4, 0xd8, 0xc7, 0xd6, 0x59, 0x58, 0x82, 0xff, 0xde, 0x02, 0x72, 0xc6,
+	0xc4, 0xd0, 0xbc, 0x4f, 0xd2, 0x9e, 0x96, 0xfe, 0x4d, 0x60, 0x3a, 0x19, 0xb6, 0x50, 0x86, 0x4d,
+	0x5a, 0x42, 0xdb, 0x1b, 0x46, 0x07, 0x2a, 0x5d, 0x4a, 0x39, 0x7e, 0x01, 0xa0, 0xa6, 0x34, 0xac,
+	0x78, 0xe3, 0x3e, 0xe2, 0x54, 0xb7, 0xc6, 0x6c, 0xf4, 0x81, 0xd6, 0x11, 0x68, 0x99, 0x2e, 0x49,
+	0x20, 0xb9, 0x2d, 0x99, 0x73, 0x28, 0x68, 0xed, 0x1d, 0x63, 0xdd, 0xfb, 0xf6, 0xec, 0xf7, 0x90,
+	0xfd, 0x0e, 0x7d, 0x20, 0xd9, 0x6b, 0x35, 0x1e, 0x60, 0x25, 0x69, 0xdc, 0x0c, 0x6a, 0x17, 0xfa,
+	0xd0, 0x1f, 0x2d, 0x58, 0x6d, 0x32, 0x9f, 0xbd, 0x89, 0x0d, 0x0b, 0x0f, 0x6f, 0xef, 0x7f, 0xdf,
+	0x47, 0xa3, 0x0d, 0x94, 0x64, 0x8f, 0x3e, 0xbc, 0x5d, 0x92, 0x46, 0x24, 0x45, 0x90, 0xf2, 0xfc,
+	0x06, 0x8a, 0x32, 0x26, 0x7c, 0x37, 0x26, 0xcf, 0x83, 0x54, 0xa4, 0x93, 0x4f, 0xdf, 0x4b, 0x92,
+	0x1d, 0x94, 0xe4, 0x3e, 0xad, 0x18, 0x95, 0x37, 0x6e, 0x3c, 0xf7, 0x5d, 0xc3, 0xeb, 0xc3, 0x68,
+	0x6d, 0x94, 0x65, 0xeb, 0xa6, 0x8b, 0xd3, 0x40, 0x06, 0x4e, 0x6a, 0xa9, 0x20, 0xaa, 0xcf, 0x53,
+	0x62, 0xec, 0xdd, 0xaa, 0xb6, 0x78, 0x47, 0x48, 0x3f, 0x44, 0x61, 0x6a, 0x04, 0x0d, 0x34, 0x10,
+	0xc1, 0x7e, 0xdd, 0xb3, 0x75, 0x87, 0x84, 0xd2, 0x91, 0x2f, 0x00, 0x5e, 0x85, 0xee, 0xdd, 0xfd,
+	0xad, 0x82, 0x78, 0x84, 0x2e, 0x27, 0x1e, 0x2f, 0x5f, 0xec, 0xc0, 0xe2, 0x4f, 0x98, 0x40, 0xf6,
+	0x93, 0xd4, 0x3c, 0x11, 0x45, 0x27, 0x6e, 0x92, 0x44, 0x21, 0x97, 0x00, 0x9f, 0xb2, 0x36, 0x13,
+	0x6c, 0x2a, 0x94, 0xf7, 0x29, 0x11, 0xfb, 0x43, 0x48, 0x11, 0xc0, 0x2b, 0xbf, 0x1d, 0xb4, 0xae,
+	0x66, 0x87, 0x94, 0x48, 0x09, 0x03, 0xb7, 0xe9, 0x22, 0x8c, 0x54, 0xe0, 0x57, 0x16, 0xe4, 0x63,
+	0x13, 0x1d, 0x79, 0x94, 0xe6, 0x05, 0x23, 0x13, 0xdf, 0x14, 0x55, 0x36, 0x11, 0xc4, 0x0a, 0x59,
+	0x77, 0xe4, 0xef, 0x1a, 0x83, 0xc1, 0x83, 0x49, 0x19, 0x3a, 0x50, 0x90, 0xee, 0xa5, 0x5b, 0xf8,
+	0x69, 0x7c, 0xf5, 0xc3, 0xf1, 0xd3, 0x44, 0xc2, 0x51, 0x75, 0xa2, 0x22, 0x58, 0xea, 0xb9, 0x3a,
+	0x40, 0x7c, 0x58, 0x56, 0xa3, 0x99, 0xbe, 0x31, 0x1b, 0x4d, 0x6b, 0x1f, 0xdd, 0x2f, 0xc6, 0xa0,
+	0x94, 0x59, 0x6f, 0xa0, 0x28, 0x85, 0x8a, 0x4d, 0x29, 0xb3, 0x7c, 0xa2, 0x2e, 0xf8, 0x64, 0x23,
+	0x69, 0x61, 0xf3, 0xd8, 0xaf, 0x2d, 0x58, 0x53, 0xaf, 0x8d, 0xe1, 0xa7, 0x5a, 0x79, 0x74, 0x8a,
+	0x9a, 0xee, 0xe1, 0x8f, 0x51, 0x80, 0xed, 0xfd, 0xad, 0x14, 0x43, 0x27, 0xf4, 0xf0, 0x95, 0x05,
+	0x25, 0x55, 0x7e, 0x92, 0x2d, 0x25, 0xd9, 0x4d, 0x7d, 0x6a, 0xca, 0x37, 0xdc, 0xc9, 0x31, 0xfc,
+	0x00, 0x85, 0xa9, 0xd0, 0x75, 0x65, 0x05, 0xe4, 0x71, 0xa0, 0x5b, 0x5a, 0xe9, 0x6a, 0x7f, 0xb6,
+	0xa0, 0x10, 0xff, 0x50, 0x49, 0xd2, 0xd4, 0x9c, 0xf2, 0x25, 0xb3, 0xba, 0x3d, 0xfe, 0x0b, 0x9c,
+	0xc1, 0xfe, 0x01, 0x62, 0x3f, 0xa7, 0x4f, 0x53, 0xb0, 0x1b, 0x37, 0xa3, 0xdf, 0x41, 0xdf, 0xc9,
+	0xff, 0x82, 0x3b, 0xb8, 0x62, 0x3d, 0x29, 0xd8, 0x97, 0x16, 0xe4, 0xa5, 0x5d, 0x15, 0xcf, 0x69,
+	0x1c, 0xe4, 0xd1, 0x58, 0x89, 0x12, 0xfe, 0xb1, 0x8b, 0x52, 0x51, 0x52, 0x4b, 0x95, 0x2a, 0x26,
+	0x85, 0x0c, 0x43, 0xe5, 0x29, 0x5a, 0x35, 0xb3, 0x0f, 0x0b, 0x8d, 0xa4, 0xdc, 0xa1, 0x05, 0x4b,
+	0x3a, 0x75, 0x4f, 0xf3, 0xda, 0x9d, 0x31, 0x96, 0x4f, 0xbc, 0xb5, 0x88, 0x60, 0x40, 0xfa, 0x7d,
+	0x89, 0x8c, 0x3d, 0x0d, 0xf2, 0x49, 0xef, 0x48, 0x95, 0xa6, 0x59, 0x81, 0xd5, 0x10, 0xac, 0x4a,
+	0xfa, 0x15, 0x79, 0xb8, 0xfc, 0x7d, 0x92, 0xfd, 0xf9, 0xbc, 0xa4, 0xbf, 0xce, 0x62, 0x4b, 0xfb,
+	0xbd, 0xff, 0x0d, 0x00, 0x82, 0xc3, 0xc4, 0x7d, 0xa4, 0x1d, 0x00, 0x00,
 }
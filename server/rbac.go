@@ -0,0 +1,142 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"golang.org/x/net/context"
+)
+
+// Permission - action which can be granted to role
+type Permission string
+
+// PermissionPublic and PermissionAuthenticated are pseudo-permissions: the first one is required by methods available
+// without token, the second one is granted to every authenticated user
+const (
+	PermissionPublic        Permission = "public"
+	PermissionAuthenticated Permission = "authenticated"
+	PermissionCompanyRead   Permission = "company:read"
+	PermissionCompanyManage Permission = "company:manage"
+	PermissionUserRead      Permission = "user:read"
+	PermissionUserManage    Permission = "user:manage"
+	PermissionEntityRead    Permission = "entity:read"
+	PermissionEntityWrite   Permission = "entity:write"
+)
+
+// Roles of users. Platform admin has all permissions in all companies, other roles have permissions only in
+// company of user
+const (
+	RolePlatformAdmin = "platform_admin"
+	RoleCompanyAdmin  = "company_admin"
+	RoleEditor        = "editor"
+	RoleViewer        = "viewer"
+)
+
+// ErrPermissionDenied - error when role of current user doesn't have required permission
+var ErrPermissionDenied = errors.New("you don't have permission for this action")
+
+// ErrUnknownRole - error when unsupported role is assigned
+var ErrUnknownRole = errors.New("unknown role")
+
+var rolePermissions = map[string][]Permission{
+	RolePlatformAdmin: {
+		PermissionAuthenticated,
+		PermissionCompanyRead, PermissionCompanyManage,
+		PermissionUserRead, PermissionUserManage,
+		PermissionEntityRead, PermissionEntityWrite,
+	},
+	RoleCompanyAdmin: {
+		PermissionAuthenticated,
+		PermissionCompanyRead,
+		PermissionUserRead, PermissionUserManage,
+		PermissionEntityRead, PermissionEntityWrite,
+	},
+	RoleEditor: {
+		PermissionAuthenticated,
+		PermissionCompanyRead,
+		PermissionUserRead,
+		PermissionEntityRead, PermissionEntityWrite,
+	},
+	RoleViewer: {
+		PermissionAuthenticated,
+		PermissionCompanyRead,
+		PermissionUserRead,
+		PermissionEntityRead,
+	},
+}
+
+// IsValidRole - check if role is supported
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// UserRole - return role of user. Users which were created before roles get role from is_admin flag
+func UserRole(user *grpc_gateway_user.User) string {
+	if user.Role != "" {
+		return user.Role
+	}
+
+	if user.IsAdmin {
+		return RolePlatformAdmin
+	}
+
+	return RoleEditor
+}
+
+// RoleHasPermission - check if permission is granted to role
+func RoleHasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPlatformAdmin - check if user has access to all companies
+func IsPlatformAdmin(user *grpc_gateway_user.User) bool {
+	return UserRole(user) == RolePlatformAdmin
+}
+
+// HasPermission - check if user has permission in company. Empty companyID means platform-wide action,
+// which is allowed only for platform admins
+func HasPermission(user *grpc_gateway_user.User, perm Permission, companyID string) bool {
+	role := UserRole(user)
+	if !RoleHasPermission(role, perm) {
+		return false
+	}
+
+	if role == RolePlatformAdmin {
+		return true
+	}
+
+	return companyID != "" && user.CompanyId == companyID
+}
+
+// CheckPermission - check if current user has permission in company
+func CheckPermission(ctx context.Context, perm Permission, companyID string) error {
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		return ErrPermissionDenied
+	}
+
+	if !HasPermission(currentUser, perm, companyID) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// CanAssignRole - check if user can give role to users of company
+func CanAssignRole(user *grpc_gateway_user.User, role, companyID string) bool {
+	if !IsValidRole(role) {
+		return false
+	}
+
+	if role == RolePlatformAdmin {
+		return IsPlatformAdmin(user)
+	}
+
+	return HasPermission(user, PermissionUserManage, companyID)
+}
@@ -0,0 +1,53 @@
+package server_test
+
+import (
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	. "gopkg.in/check.v1"
+)
+
+type RBACTestSuite struct{}
+
+var _ = Suite(&RBACTestSuite{})
+
+func (rt *RBACTestSuite) TestUserRole(c *C) {
+	c.Assert(server.UserRole(&grpc_gateway_user.User{Role: server.RoleViewer}), Equals, server.RoleViewer)
+
+	// users created before roles
+	c.Assert(server.UserRole(&grpc_gateway_user.User{IsAdmin: true}), Equals, server.RolePlatformAdmin)
+	c.Assert(server.UserRole(&grpc_gateway_user.User{}), Equals, server.RoleEditor)
+}
+
+func (rt *RBACTestSuite) TestHasPermission(c *C) {
+	platformAdmin := &grpc_gateway_user.User{Role: server.RolePlatformAdmin}
+	companyAdmin := &grpc_gateway_user.User{Role: server.RoleCompanyAdmin, CompanyId: "c1"}
+	editor := &grpc_gateway_user.User{Role: server.RoleEditor, CompanyId: "c1"}
+	viewer := &grpc_gateway_user.User{Role: server.RoleViewer, CompanyId: "c1"}
+
+	c.Assert(server.HasPermission(platformAdmin, server.PermissionUserManage, "c2"), Equals, true)
+	c.Assert(server.HasPermission(platformAdmin, server.PermissionCompanyManage, ""), Equals, true)
+
+	c.Assert(server.HasPermission(companyAdmin, server.PermissionUserManage, "c1"), Equals, true)
+	c.Assert(server.HasPermission(companyAdmin, server.PermissionUserManage, "c2"), Equals, false)
+	c.Assert(server.HasPermission(companyAdmin, server.PermissionUserRead, ""), Equals, false)
+	c.Assert(server.HasPermission(companyAdmin, server.PermissionCompanyManage, "c1"), Equals, false)
+
+	c.Assert(server.HasPermission(editor, server.PermissionEntityWrite, "c1"), Equals, true)
+	c.Assert(server.HasPermission(editor, server.PermissionUserManage, "c1"), Equals, false)
+
+	c.Assert(server.HasPermission(viewer, server.PermissionEntityRead, "c1"), Equals, true)
+	c.Assert(server.HasPermission(viewer, server.PermissionEntityWrite, "c1"), Equals, false)
+}
+
+func (rt *RBACTestSuite) TestCanAssignRole(c *C) {
+	platformAdmin := &grpc_gateway_user.User{Role: server.RolePlatformAdmin}
+	companyAdmin := &grpc_gateway_user.User{Role: server.RoleCompanyAdmin, CompanyId: "c1"}
+
+	c.Assert(server.CanAssignRole(platformAdmin, server.RolePlatformAdmin, ""), Equals, true)
+	c.Assert(server.CanAssignRole(platformAdmin, "unknown", "c1"), Equals, false)
+
+	c.Assert(server.CanAssignRole(companyAdmin, server.RoleViewer, "c1"), Equals, true)
+	c.Assert(server.CanAssignRole(companyAdmin, server.RoleCompanyAdmin, "c1"), Equals, true)
+	c.Assert(server.CanAssignRole(companyAdmin, server.RoleViewer, "c2"), Equals, false)
+	c.Assert(server.CanAssignRole(companyAdmin, server.RolePlatformAdmin, "c1"), Equals, false)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
 	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
 	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
@@ -12,6 +13,7 @@ import (
 	"gitlab.com/grpc-gateway-example/pkg/ui/data/swagger"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -32,12 +34,109 @@ type Config struct {
 
 	EmailConfirmationTTL time.Duration
 	SMSConfirmationTTL   time.Duration
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	PasswordResetTTL     time.Duration
+
+	// LoginMaxFailures, LoginMaxAddressFailures - failed attempts per account and per client address before lockout.
+	// Lockout starts from LoginLockoutBase and doubles with each next failure up to LoginLockoutMax.
+	// Failures older than LoginFailuresWindow are forgotten
+	LoginMaxFailures        int
+	LoginMaxAddressFailures int
+	LoginLockoutBase        time.Duration
+	LoginLockoutMax         time.Duration
+	LoginFailuresWindow     time.Duration
+
+	// TrustedProxies - reverse proxies in front of http listener. Client address is taken from x-forwarded-for
+	// before addresses appended by these proxies, addresses sent by client itself aren't trusted
+	TrustedProxies int
+
+	// SMSCodeMaxAttempts - wrong attempts after which issued sms-code is dropped
+	SMSCodeMaxAttempts int
+
+	// TOTPIssuer - issuer name which is shown in authenticator apps
+	TOTPIssuer string
+
+	// SSOCallbackURL - public url of /v1/sso/callback, which is registered in identity providers.
+	// SSOStateTTL - time for completing login in identity provider
+	SSOCallbackURL string
+	SSOStateTTL    time.Duration
+
+	// ImpersonationTTL - lifetime of token which admin gets for acting as another user
+	ImpersonationTTL time.Duration
+
+	// EntityRetention - deleted entities are kept for this time, after it they can be purged by admin
+	EntityRetention time.Duration
+
+	// EntityImportMaxRows, EntityImportMaxBytes - limits of rows and size of file which is imported into entities
+	EntityImportMaxRows  int
+	EntityImportMaxBytes int
+
+	// EntityImportStaleAfter - import job which wasn't saved for this time is stopped. Its staged entities are
+	// removed and job fails, or they are published if job was publishing them
+	EntityImportStaleAfter time.Duration
+
+	// JWTKeys - keys for signing and verification of tokens, JWTActiveKeyID - id of key for signing
+	JWTKeys        []*JWTKey
+	JWTActiveKeyID string
+
+	// EncryptionKeys - master keys of personal data, EncryptionActiveKeyID - id of key which seals new documents.
+	// BlindIndexKey - key of lookups of encrypted fields, it can't be changed after data is stored.
+	// KeyRotationInterval - period of background sealing of documents which aren't sealed by active key
+	EncryptionKeys        []*EncryptionKey
+	EncryptionActiveKeyID string
+	BlindIndexKey         []byte
+	KeyRotationInterval   time.Duration
+
+	// GRPCTLSCertFile, GRPCTLSKeyFile - certificate of grpc listener, plaintext is used when they are empty.
+	// GRPCClientCAFile - CA of client certificates, GRPCRequireClientCert - accept only clients with certificate
+	GRPCTLSCertFile       string
+	GRPCTLSKeyFile        string
+	GRPCClientCAFile      string
+	GRPCRequireClientCert bool
+
+	// GatewayCertFile, GatewayKeyFile - client certificate of gateway for grpc listener.
+	// GatewayCAFile - CA of grpc listener certificate, GRPCServerName - name in grpc listener certificate
+	GatewayCertFile string
+	GatewayKeyFile  string
+	GatewayCAFile   string
+	GRPCServerName  string
+
+	// HTTPTLSCertFile, HTTPTLSKeyFile - certificate of http listener, HTTPClientCAFile - CA of client certificates
+	HTTPTLSCertFile  string
+	HTTPTLSKeyFile   string
+	HTTPClientCAFile string
+
+	// Storage - storage backend, mongo or memory. Memory storage keeps everything in process and is lost on restart
+	Storage string
+
+	// SkipMigrations - don't apply migrations of database on start, they are applied by migrate command
+	SkipMigrations bool
+
+	// MongoURI - mongo connection string, MongoDatabase - database name, it overrides database from uri
+	MongoURI      string
+	MongoDatabase string
+
+	// MongoPoolLimit - max number of sockets per mongo server, MongoReadPreference - mode of reads,
+	// MongoDialTimeout - timeout of connection, MongoSocketTimeout - timeout of operations
+	MongoPoolLimit      int
+	MongoReadPreference string
+	MongoDialTimeout    time.Duration
+	MongoSocketTimeout  time.Duration
 }
 
 // Server - type of main server which provide this service
 type Server struct {
 	grpcServer *grpc.Server
+	jwtKeySet  *JWTKeySet
 	Config     *Config
+
+	// tls configs of listeners and gateway, nil means plaintext
+	grpcTLS    *tls.Config
+	gatewayTLS *tls.Config
+	httpTLS    *tls.Config
+
+	storage Storage
 }
 
 // GetHTTPClient - return default (for this service) http client
@@ -49,33 +148,195 @@ func GetHTTPClient() *http.Client {
 
 // NewServer - return new instance of Server
 func NewServer(cfg *Config) (*Server, error) {
+	if cfg.AccessTokenTTL == 0 {
+		cfg.AccessTokenTTL = time.Minute * 15
+	}
+
+	if cfg.RefreshTokenTTL == 0 {
+		cfg.RefreshTokenTTL = time.Hour * 24 * 30
+	}
+
+	if cfg.PasswordResetTTL == 0 {
+		cfg.PasswordResetTTL = time.Hour
+	}
+
+	if cfg.LoginMaxFailures == 0 {
+		cfg.LoginMaxFailures = 5
+	}
+
+	if cfg.LoginMaxAddressFailures == 0 {
+		cfg.LoginMaxAddressFailures = 50
+	}
+
+	if cfg.LoginLockoutBase == 0 {
+		cfg.LoginLockoutBase = time.Minute
+	}
+
+	if cfg.LoginLockoutMax == 0 {
+		cfg.LoginLockoutMax = time.Hour
+	}
+
+	if cfg.LoginFailuresWindow == 0 {
+		cfg.LoginFailuresWindow = time.Hour * 24
+	}
+
+	if cfg.SMSCodeMaxAttempts == 0 {
+		cfg.SMSCodeMaxAttempts = 3
+	}
+
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = "FirmQ"
+	}
+
+	if cfg.SSOCallbackURL == "" {
+		cfg.SSOCallbackURL = "http://127.0.0.1:8080/v1/sso/callback"
+	}
+
+	if cfg.SSOStateTTL == 0 {
+		cfg.SSOStateTTL = time.Minute * 10
+	}
+
+	if cfg.ImpersonationTTL == 0 {
+		cfg.ImpersonationTTL = time.Minute * 30
+	}
+
+	if cfg.EntityRetention == 0 {
+		cfg.EntityRetention = time.Hour * 24 * 30
+	}
+
+	if cfg.EntityImportMaxRows == 0 {
+		cfg.EntityImportMaxRows = 5000
+	}
+
+	// grpc server accepts messages up to 4MB, file should fit into request with mapping
+	if cfg.EntityImportMaxBytes == 0 {
+		cfg.EntityImportMaxBytes = 3 << 20
+	}
+
+	if cfg.EntityImportStaleAfter == 0 {
+		cfg.EntityImportStaleAfter = time.Minute * 5
+	}
+
+	if cfg.KeyRotationInterval == 0 {
+		cfg.KeyRotationInterval = time.Hour
+	}
+
+	jwtKeySet, err := NewJWTKeySet(cfg.JWTKeys, cfg.JWTActiveKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// cipher is set before storage is opened, because migrations and commands store documents too
+	fieldCipher, err := NewFieldCipher(cfg.EncryptionKeys, cfg.EncryptionActiveKeyID, cfg.BlindIndexKey)
+	if err != nil {
+		return nil, err
+	}
+	SetFieldCipher(fieldCipher)
+
+	if cfg.Storage == "" {
+		cfg.Storage = StorageMongo
+	}
+
+	if cfg.MongoURI == "" {
+		cfg.MongoURI = "mongodb://mongodb/testdatabase"
+	}
+
+	if cfg.MongoReadPreference == "" {
+		cfg.MongoReadPreference = "primary"
+	}
+
+	if cfg.MongoDialTimeout == 0 {
+		cfg.MongoDialTimeout = time.Second * 10
+	}
+
+	if cfg.MongoSocketTimeout == 0 {
+		cfg.MongoSocketTimeout = time.Minute
+	}
+
+	if cfg.GRPCServerName == "" {
+		cfg.GRPCServerName = "localhost"
+	}
+
 	s := Server{
-		Config: cfg,
+		Config:    cfg,
+		jwtKeySet: jwtKeySet,
 	}
+
+	if err := s.loadTLSConfigs(); err != nil {
+		return nil, err
+	}
+
 	return &s, nil
 }
 
+// loadTLSConfigs - load certificates of listeners and gateway, so misconfiguration is found before start
+func (s *Server) loadTLSConfigs() error {
+	cfg := s.Config
+
+	var err error
+	s.grpcTLS, err = LoadServerTLSConfig(cfg.GRPCTLSCertFile, cfg.GRPCTLSKeyFile, cfg.GRPCClientCAFile, cfg.GRPCRequireClientCert)
+	if err != nil {
+		return err
+	}
+
+	// gateway talks to grpc listener in the same way as other clients
+	if s.grpcTLS != nil {
+		if cfg.GRPCRequireClientCert && cfg.GatewayCertFile == "" {
+			return ErrTLSMisconfigured
+		}
+
+		s.gatewayTLS, err = LoadClientTLSConfig(cfg.GatewayCertFile, cfg.GatewayKeyFile, cfg.GatewayCAFile, cfg.GRPCServerName)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.httpTLS, err = LoadServerTLSConfig(cfg.HTTPTLSCertFile, cfg.HTTPTLSKeyFile, cfg.HTTPClientCAFile, false)
+	return err
+}
+
 func (s *Server) runGRPCServer() error {
-	s.configureGRPCServer()
+	if err := s.configureGRPCServer(); err != nil {
+		return err
+	}
 
 	l, err := net.Listen("tcp", ":9090")
 	if err != nil {
 		return err
 	}
 
+	// the same storage is used by interceptors of running server
+	storageInstance = s.storage
+	trustedProxiesInstance = s.Config.TrustedProxies
+
 	go s.grpcServer.Serve(l)
 	return nil
 }
 
-func (s *Server) configureGRPCServer() {
-	s.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(AuthUnaryInterceptor))
+func (s *Server) configureGRPCServer() error {
+	// unary and streaming calls are authorized by the same policies
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(AuthUnaryInterceptor),
+		grpc.StreamInterceptor(AuthStreamInterceptor),
+	}
+
+	if s.grpcTLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.grpcTLS)))
+	}
+
+	s.grpcServer = grpc.NewServer(opts...)
 
-	userServiceServer := NewUserServer(s.Config)
+	userServiceServer := NewUserServer(s.Config, s.storage)
 	grpc_gateway_user.RegisterUserServiceServer(s.grpcServer, userServiceServer)
 
-	grpc_gateway_company.RegisterCompanyServiceServer(s.grpcServer, NewCompanyServer())
+	grpc_gateway_company.RegisterCompanyServiceServer(s.grpcServer, NewCompanyServer(s.storage))
 
-	grpc_gateway_entity.RegisterEntityServiceServer(s.grpcServer, NewEntityServer())
+	grpc_gateway_entity.RegisterEntityServiceServer(s.grpcServer, NewEntityServer(s.Config, s.storage))
+
+	// every method should have declared access policy
+	if err := ValidateAuthPolicies(s.grpcServer); err != nil {
+		return err
+	}
 
 	// create default user
 	if err := userServiceServer.(*userServer).createDefaultUser(); err != nil {
@@ -83,13 +344,35 @@ func (s *Server) configureGRPCServer() {
 	} else {
 		glog.Info("Default user created")
 	}
+
+	return nil
 }
 
 // RunServer - starts all required functions to move server to working (active) state
 func (s *Server) RunServer() error {
-	connectionPoolInstance = NewConnectionPool()
+	// server doesn't start without database
+	storage, err := NewStorage(s.Config)
+	if err != nil {
+		return err
+	}
+
+	s.storage = storage
 	smsGatewayInstance = NewSMSGateway(s.Config.NexmoAPIKey, s.Config.NexmoSecretKey)
 	emailInstance = NewEmailSender(s.Config)
+	jwtKeySetInstance = s.jwtKeySet
+
+	// documents sealed by retired keys or stored before encryption are sealed by active key in background
+	if mongoStorage, ok := storage.(*MongoStorage); ok && GetFieldCipher() != nil {
+		go mongoStorage.RunKeyRotation(s.Config.KeyRotationInterval)
+	}
+
+	// entities which updates were stopped with process get their latest revisions back
+	if mongoStorage, ok := storage.(*MongoStorage); ok {
+		go mongoStorage.RunRevisionRecovery(entityUpdateTTL)
+	}
+
+	// imports which were stopped with process are finished by any running server
+	go RunEntityImportRecovery(storage, s.Config.EntityImportStaleAfter)
 
 	if err := s.runGRPCServer(); err != nil {
 		return err
@@ -103,7 +386,11 @@ func (s *Server) RunServer() error {
 
 	//grpcMux := runtime.NewServeMux()
 	opts := []grpc.DialOption{grpc.WithInsecure()}
-	err := grpc_gateway_user.RegisterUserServiceHandlerFromEndpoint(ctx, grpcMux, ":9090", opts)
+	if s.gatewayTLS != nil {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(s.gatewayTLS))}
+	}
+
+	err = grpc_gateway_user.RegisterUserServiceHandlerFromEndpoint(ctx, grpcMux, ":9090", opts)
 	if err != nil {
 		return err
 	}
@@ -142,7 +429,21 @@ func (s *Server) RunServer() error {
 		}
 	})
 
+	// set up single sign-on with identity providers of companies
+	mux.Handle("/v1/sso/", NewSSOHandler(s.Config, s.storage))
+
 	mux.Handle("/", grpcMux)
 
-	return http.ListenAndServe(":8080", allowCORS(mux))
+	httpServer := &http.Server{
+		Addr:      ":8080",
+		Handler:   allowCORS(mux),
+		TLSConfig: s.httpTLS,
+	}
+
+	if s.httpTLS != nil {
+		// certificates are already loaded to tls config
+		return httpServer.ListenAndServeTLS("", "")
+	}
+
+	return httpServer.ListenAndServe()
 }
@@ -0,0 +1,203 @@
+package server
+
+import (
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2"
+	"net/http"
+	"time"
+)
+
+// NewAPIKeyResponse - create new instance of api key response
+func NewAPIKeyResponse() *grpc_gateway_user.APIKeyResponse {
+	message := &grpc_gateway_user.APIKeyResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	return message
+}
+
+// NewAPIKeyListResponse - create new instance of api key list response
+func NewAPIKeyListResponse() *grpc_gateway_user.APIKeyListResponse {
+	message := &grpc_gateway_user.APIKeyListResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	return message
+}
+
+// getManagedServiceAccount - get service account which current user can manage
+func getManagedServiceAccount(ctx context.Context, sess StorageSession, id string, meta *grpc_gateway_common.MetaResponse) (*grpc_gateway_user.User, bool) {
+	serviceAccount, err := sess.Users().GetUserByID(id)
+	if err == nil && !serviceAccount.IsServiceAccount {
+		err = mgo.ErrNotFound
+	}
+
+	if err := CheckPermission(ctx, PermissionUserManage, serviceAccount.CompanyId); err != nil {
+		meta.StatusCode = http.StatusForbidden
+		meta.Ok = false
+		meta.Error = err.Error()
+		return nil, false
+	}
+
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			meta.StatusCode = http.StatusNotFound
+		}
+
+		meta.Ok = false
+		meta.Error = err.Error()
+		return nil, false
+	}
+
+	return serviceAccount, true
+}
+
+func (s *userServer) CreateServiceAccount(ctx context.Context, in *grpc_gateway_user.ServiceAccountRequest) (*grpc_gateway_user.UserResponse, error) {
+	message := NewUserResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if in.Role == "" {
+		in.Role = RoleViewer
+	}
+
+	if !CanAssignRole(currentUser, in.Role, in.CompanyId) {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = ErrPermissionDenied.Error()
+		return message, nil
+	}
+
+	message.Data, err = sess.Users().CreateServiceAccount(in.Name, in.CompanyId, in.Role)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	filterUserResponseFields(message)
+	return message, nil
+}
+
+func (s *userServer) CreateAPIKey(ctx context.Context, in *grpc_gateway_user.CreateAPIKeyRequest) (*grpc_gateway_user.APIKeyResponse, error) {
+	message := NewAPIKeyResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	serviceAccount, ok := getManagedServiceAccount(ctx, sess, in.ServiceAccountId, message.Meta)
+	if !ok {
+		return message, nil
+	}
+
+	if in.ExpiresAt != 0 && in.ExpiresAt <= time.Now().Unix() {
+		message.Meta.Ok = false
+		message.Meta.Error = "expiration time should be in future"
+		return message, nil
+	}
+
+	message.Data, message.Key, err = sess.APIKeys().CreateAPIKey(serviceAccount, in.Name, in.ExpiresAt)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Data.KeyHash = ""
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) ListAPIKeys(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_user.APIKeyListResponse, error) {
+	message := NewAPIKeyListResponse()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, APIKeySortFields, "created_at")
+	if err != nil {
+		message.Meta.StatusCode = http.StatusBadRequest
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	if _, ok := getManagedServiceAccount(ctx, sess, in.Id, message.Meta); !ok {
+		return message, nil
+	}
+
+	message, err = sess.APIKeys().GetAPIKeys(in.Id, page)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	for _, apiKey := range message.Data {
+		apiKey.KeyHash = ""
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) RevokeAPIKey(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.APIKeys()
+	apiKey, err := repo.GetAPIKeyByID(in.Id)
+
+	if err := CheckPermission(ctx, PermissionUserManage, apiKey.CompanyId); err != nil {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err == nil {
+		err = repo.RevokeAPIKey(in.Id)
+	}
+
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
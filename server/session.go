@@ -0,0 +1,175 @@
+package server
+
+import (
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"golang.org/x/net/context"
+	"gopkg.in/mgo.v2"
+	"net/http"
+)
+
+// NewSessionListResponse - create new instance of session list response
+func NewSessionListResponse() *grpc_gateway_user.SessionListResponse {
+	message := &grpc_gateway_user.SessionListResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	message.Data = []*grpc_gateway_user.Session{}
+	return message
+}
+
+// filterSessionListResponseFields - hide hashes of refresh tokens and mark session of current request
+func filterSessionListResponseFields(message *grpc_gateway_user.SessionListResponse, currentSessionID string) {
+	for _, session := range message.Data {
+		session.RefreshToken = ""
+		session.PreviousRefreshToken = ""
+		session.IsCurrent = currentSessionID != "" && session.Id == currentSessionID
+	}
+}
+
+// getManagedUser - get user which current user can manage
+func getManagedUser(ctx context.Context, sess StorageSession, id string, meta *grpc_gateway_common.MetaResponse) (*grpc_gateway_user.User, bool) {
+	user, err := sess.Users().GetUserByID(id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			meta.StatusCode = http.StatusNotFound
+		}
+
+		meta.Ok = false
+		meta.Error = err.Error()
+		return nil, false
+	}
+
+	if err = CheckPermission(ctx, PermissionUserManage, user.CompanyId); err != nil {
+		meta.StatusCode = http.StatusForbidden
+		meta.Ok = false
+		meta.Error = err.Error()
+		return nil, false
+	}
+
+	return user, true
+}
+
+// ListSessions - list sessions of current user, id of request isn't used
+func (s *userServer) ListSessions(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_user.SessionListResponse, error) {
+	message := NewSessionListResponse()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, SessionSortFields, "-last_seen_at")
+	if err != nil {
+		message.Meta.StatusCode = http.StatusBadRequest
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	userID := ctx.Value("user_id").(string)
+	message, err = sess.Sessions().GetActiveSessions(userID, page)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sessionID, _ := ctx.Value("session_id").(string)
+	filterSessionListResponseFields(message, sessionID)
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) RevokeSession(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	// users can revoke only own sessions
+	userID := ctx.Value("user_id").(string)
+	if err := sess.Sessions().RevokeUserSession(in.Id, userID); err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) ListUserSessions(ctx context.Context, in *grpc_gateway_common.ListRequest) (*grpc_gateway_user.SessionListResponse, error) {
+	message := NewSessionListResponse()
+
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, SessionSortFields, "-last_seen_at")
+	if err != nil {
+		message.Meta.StatusCode = http.StatusBadRequest
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	if _, ok := getManagedUser(ctx, sess, in.Id, message.Meta); !ok {
+		return message, nil
+	}
+
+	message, err = sess.Sessions().GetActiveSessions(in.Id, page)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	sessionID, _ := ctx.Value("session_id").(string)
+	filterSessionListResponseFields(message, sessionID)
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) RevokeUserSession(ctx context.Context, in *grpc_gateway_user.UserSessionRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	if _, ok := getManagedUser(ctx, sess, in.UserId, message.Meta); !ok {
+		return message, nil
+	}
+
+	if err := sess.Sessions().RevokeUserSession(in.Id, in.UserId); err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// ErrSessionRevoked - error when session of token is revoked or expired
+var ErrSessionRevoked = errors.New("session is revoked or expired")
+
+// ErrInvalidRefreshToken - error when refresh token is unknown, already used or expired
+var ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+
+// sessionLastSeenResolution - last seen time of session isn't updated more often than this, so every request
+// doesn't cause write to database
+const sessionLastSeenResolution = time.Minute
+
+// SessionRepo - model for accessing login sessions in database
+type SessionRepo struct {
+	sess *mgo.Database
+	coll string
+}
+
+// NewSessionRepo - returns new instance of SessionRepo which provide access to session model
+func NewSessionRepo(sess *mgo.Database) *SessionRepo {
+	return &SessionRepo{
+		sess: sess,
+		coll: "sessions",
+	}
+}
+
+// GenerateSecretToken - generate new random token for refresh tokens and one-time links
+func GenerateSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecretToken - secret tokens are stored only as hashes
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSession - returns new session of user. Device and address of client are kept for showing sessions to user
+func newSession(userID, userAgent, ipAddress string, ttl time.Duration) *grpc_gateway_user.Session {
+	now := time.Now()
+	return &grpc_gateway_user.Session{
+		Id:         uuid.NewV4().String(),
+		UserId:     userID,
+		CreatedAt:  now.Unix(),
+		ExpiresAt:  now.Add(ttl).Unix(),
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+		LastSeenAt: now.Unix(),
+	}
+}
+
+// isSessionTouchRequired - check whether last seen time of session is too old
+func isSessionTouchRequired(session *grpc_gateway_user.Session, now int64) bool {
+	return now-session.LastSeenAt >= int64(sessionLastSeenResolution.Seconds())
+}
+
+// CreateSession - create new session for user with refresh token
+func (sr *SessionRepo) CreateSession(userID, refreshToken, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error) {
+	c := sr.sess.C(sr.coll)
+
+	session := newSession(userID, userAgent, ipAddress, ttl)
+	session.RefreshToken = hashSecretToken(refreshToken)
+	return session, c.Insert(session)
+}
+
+// CreateImpersonationSession - create session in which platform admin acts as user. Such session doesn't have
+// refresh token, so it can't be prolonged
+func (sr *SessionRepo) CreateImpersonationSession(userID, impersonatorID, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error) {
+	c := sr.sess.C(sr.coll)
+
+	session := newSession(userID, userAgent, ipAddress, ttl)
+	session.ImpersonatorId = impersonatorID
+	return session, c.Insert(session)
+}
+
+// GetActiveSession - get session which isn't revoked and isn't expired
+func (sr *SessionRepo) GetActiveSession(id, userID string) (*grpc_gateway_user.Session, error) {
+	c := sr.sess.C(sr.coll)
+	var session grpc_gateway_user.Session
+
+	err := c.Find(bson.M{
+		"id":        id,
+		"userid":    userID,
+		"isrevoked": false,
+		"expiresat": bson.M{"$gt": time.Now().Unix()},
+	}).One(&session)
+	return &session, err
+}
+
+// GetActiveSessions - get page of sessions of user which aren't revoked and aren't expired
+func (sr *SessionRepo) GetActiveSessions(userID string, page *Page) (*grpc_gateway_user.SessionListResponse, error) {
+	c := sr.sess.C(sr.coll)
+	sessions := NewSessionListResponse()
+
+	next, total, err := findPage(c, bson.M{
+		"userid":    userID,
+		"isrevoked": false,
+		"expiresat": bson.M{"$gt": time.Now().Unix()},
+	}, page, &sessions.Data)
+	sessions.NextCursor = next
+	sessions.Total = int64(total)
+	return sessions, err
+}
+
+// TouchSession - update last seen time of session
+func (sr *SessionRepo) TouchSession(session *grpc_gateway_user.Session) error {
+	now := time.Now().Unix()
+	if !isSessionTouchRequired(session, now) {
+		return nil
+	}
+
+	c := sr.sess.C(sr.coll)
+	session.LastSeenAt = now
+	return c.Update(bson.M{"id": session.Id}, bson.M{"$set": bson.M{"lastseenat": now}})
+}
+
+// RotateRefreshToken - replace refresh token of session with new one. If refresh token which was already
+// rotated is presented again, the session is revoked, because token was most likely stolen
+func (sr *SessionRepo) RotateRefreshToken(refreshToken, newRefreshToken string) (*grpc_gateway_user.Session, error) {
+	c := sr.sess.C(sr.coll)
+	hash := hashSecretToken(refreshToken)
+
+	var session grpc_gateway_user.Session
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{
+			"refreshtoken":         hashSecretToken(newRefreshToken),
+			"previousrefreshtoken": hash,
+			"lastseenat":           time.Now().Unix(),
+		}},
+		ReturnNew: true,
+	}
+
+	_, err := c.Find(bson.M{
+		"refreshtoken": hash,
+		"isrevoked":    false,
+		"expiresat":    bson.M{"$gt": time.Now().Unix()},
+	}).Apply(change, &session)
+
+	if err == mgo.ErrNotFound {
+		c.UpdateAll(bson.M{"previousrefreshtoken": hash}, bson.M{"$set": bson.M{"isrevoked": true}})
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return &session, err
+}
+
+// RevokeSession - revoke session by id
+func (sr *SessionRepo) RevokeSession(id string) error {
+	c := sr.sess.C(sr.coll)
+	return c.Update(bson.M{"id": id}, bson.M{"$set": bson.M{"isrevoked": true}})
+}
+
+// RevokeUserSession - revoke session of user by id
+func (sr *SessionRepo) RevokeUserSession(id, userID string) error {
+	c := sr.sess.C(sr.coll)
+	return c.Update(bson.M{"id": id, "userid": userID}, bson.M{"$set": bson.M{"isrevoked": true}})
+}
+
+// RevokeUserSessions - revoke all sessions of users
+func (sr *SessionRepo) RevokeUserSessions(userIDs ...string) error {
+	c := sr.sess.C(sr.coll)
+	_, err := c.UpdateAll(bson.M{"userid": bson.M{"$in": userIDs}}, bson.M{"$set": bson.M{"isrevoked": true}})
+	return err
+}
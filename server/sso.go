@@ -0,0 +1,199 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"net/http"
+	"strings"
+)
+
+// ErrSSONotConfigured - error when company doesn't have enabled identity provider
+var ErrSSONotConfigured = errors.New("single sign-on isn't configured for company")
+
+// ErrSSOEmailNotAllowed - error when identity provider didn't verify email or its domain isn't allowed for company
+var ErrSSOEmailNotAllowed = errors.New("email isn't verified or isn't allowed for single sign-on")
+
+// ErrSSOUserNotFound - error when there is no active user of company with verified email
+var ErrSSOUserNotFound = errors.New("there is no user with this email in company")
+
+// ssoHandler - http handler of OpenID Connect authorization code flow. Successful login issues the same tokens
+// as Login method
+type ssoHandler struct {
+	users *userServer
+}
+
+// NewSSOHandler - returns http handler which serves /v1/sso/login/{company_id} and /v1/sso/callback
+func NewSSOHandler(config *Config, storage Storage) http.Handler {
+	h := &ssoHandler{users: &userServer{config: config, storage: storage}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/sso/login/", h.login)
+	mux.HandleFunc("/v1/sso/callback", h.callback)
+	return mux
+}
+
+// login - redirect user to identity provider of company
+func (h *ssoHandler) login(w http.ResponseWriter, r *http.Request) {
+	message := NewCommonResponse()
+	companyID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/sso/login/"), "/")
+
+	sess, err := h.users.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		writeSSOResponse(w, message)
+		return
+	}
+	defer sess.Close()
+
+	redirectURL, err := h.startLogin(sess.IdentityProviders(), companyID)
+	if err != nil {
+		if err == ErrSSONotConfigured {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		writeSSOResponse(w, message)
+		return
+	}
+
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// startLogin - save pending login and return url of identity provider for it
+func (h *ssoHandler) startLogin(repo IdentityProviderStorage, companyID string) (string, error) {
+	provider, err := h.discoverProvider(repo, companyID)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := GenerateSecretToken()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := GenerateSecretToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := repo.CreateSSOState(state, companyID, nonce, h.users.config.SSOStateTTL); err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(h.users.config.SSOCallbackURL, state, nonce), nil
+}
+
+// callback - complete login with authorization code returned by identity provider
+func (h *ssoHandler) callback(w http.ResponseWriter, r *http.Request) {
+	message := NewLoginResponse()
+
+	sess, err := h.users.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		writeSSOResponse(w, message)
+		return
+	}
+	defer sess.Close()
+
+	user, err := h.authenticate(sess, r)
+	if err != nil {
+		message.Meta.StatusCode = http.StatusUnauthorized
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		writeSSOResponse(w, message)
+		return
+	}
+
+	if err := h.users.startSession(sess, message, user, r.UserAgent(), requestAddress(r, h.users.config.TrustedProxies)); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		writeSSOResponse(w, message)
+		return
+	}
+
+	message.Meta.Ok = true
+	writeSSOResponse(w, message)
+}
+
+// authenticate - verify callback of identity provider and find user by verified email
+func (h *ssoHandler) authenticate(sess StorageSession, r *http.Request) (*grpc_gateway_user.User, error) {
+	query := r.URL.Query()
+	repo := sess.IdentityProviders()
+
+	// state is consumed even if provider returned error, so the flow should be started again
+	state, err := repo.ConsumeSSOState(query.Get("state"))
+	if err != nil {
+		return nil, err
+	}
+
+	if errCode := query.Get("error"); errCode != "" {
+		return nil, errors.New("identity provider returned error: " + errCode)
+	}
+
+	provider, err := h.discoverProvider(repo, state.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := provider.Exchange(query.Get("code"), h.users.config.SSOCallbackURL)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := provider.VerifyIDToken(rawIDToken, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	idp, err := repo.GetIdentityProvider(state.CompanyID)
+	if err != nil {
+		return nil, ErrSSONotConfigured
+	}
+
+	if !claims.EmailVerified || !IsEmailDomainAllowed(claims.Email, idp.AllowedDomains) {
+		return nil, ErrSSOEmailNotAllowed
+	}
+
+	user, err := sess.Users().GetSSOUser(claims.Email, state.CompanyID)
+	if err != nil {
+		return nil, ErrSSOUserNotFound
+	}
+
+	glog.Infof("User %v logged in with single sign-on of company %v", user.Id, state.CompanyID)
+	return user, nil
+}
+
+// discoverProvider - load configuration of enabled identity provider of company
+func (h *ssoHandler) discoverProvider(repo IdentityProviderStorage, companyID string) (*OIDCProvider, error) {
+	idp, err := repo.GetIdentityProvider(companyID)
+	if err != nil || !idp.IsEnabled {
+		return nil, ErrSSONotConfigured
+	}
+
+	return DiscoverOIDCProvider(idp.Issuer, idp.ClientId, idp.ClientSecret)
+}
+
+// requestAddress - return address of http client, x-forwarded-for is used only for trusted proxies
+func requestAddress(r *http.Request, trustedProxies int) string {
+	chain := append(r.Header[http.CanonicalHeaderKey("X-Forwarded-For")], peerHost(r.RemoteAddr))
+	return forwardedClient(chain, trustedProxies)
+}
+
+// writeSSOResponse - write response in the same format as grpc gateway does
+func writeSSOResponse(w http.ResponseWriter, message proto.Message) {
+	marshaler := &runtime.JSONPb{OrigName: true, EmitDefaults: true}
+	data, err := marshaler.Marshal(message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", marshaler.ContentType())
+	w.Write(data)
+}
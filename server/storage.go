@@ -0,0 +1,322 @@
+package server
+
+import (
+	"errors"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
+	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/golang/glog"
+	"gopkg.in/mgo.v2"
+	"time"
+)
+
+// Storage backends which can be selected in config
+const (
+	StorageMongo  = "mongo"
+	StorageMemory = "memory"
+)
+
+// ErrUnknownStorage - error when storage backend from config isn't supported
+var ErrUnknownStorage = errors.New("unknown storage backend")
+
+// ErrDuplicateKey - error when object with the same unique field already exists
+var ErrDuplicateKey = errors.New("object with the same unique field already exists")
+
+// Storage - backend of all repositories. Every request works with its own storage session
+type Storage interface {
+	// Open - open session for one request, it should be closed when request is done
+	Open() (StorageSession, error)
+}
+
+// StorageSession - repositories of one request. All implementations return mgo.ErrNotFound when
+// requested object doesn't exist, so handlers don't depend on backend
+type StorageSession interface {
+	Users() UserStorage
+	Companies() CompanyStorage
+	Entities() EntityStorage
+	Sessions() SessionStorage
+	LoginAttempts() LoginAttemptStorage
+	APIKeys() APIKeyStorage
+	Audit() AuditStorage
+	IdentityProviders() IdentityProviderStorage
+	EntityImports() EntityImportStorage
+	Close()
+}
+
+// UserStorage - storage of users, email of user is unique
+type UserStorage interface {
+	CreateUser(user *grpc_gateway_user.User) error
+	CreateServiceAccount(name, companyID, role string) (*grpc_gateway_user.User, error)
+	GetUserByID(id string) (*grpc_gateway_user.User, error)
+	GetUserByEmailCode(user *grpc_gateway_user.User) (*grpc_gateway_user.User, error)
+	GetPendingUserByID(id string) (*grpc_gateway_user.User, error)
+	GetPendingUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error)
+	RegenerateEmailCode(id string) (*grpc_gateway_user.User, error)
+	SetSMSCode(userID, code string) error
+	RegisterSMSCodeFailure(email string, maxAttempts int) error
+	SetTOTPPendingSecret(userID, secret string) error
+	EnableTOTP(userID, secret string, step int64) error
+	SetTOTPLastStep(userID string, step int64) error
+	SetPreferredFactor(userID, factor string) error
+	SetPasswordResetToken(email, token string) (*grpc_gateway_user.User, error)
+	GetUserByPasswordResetToken(token string) (*grpc_gateway_user.User, error)
+	ImportUser(user *grpc_gateway_user.User) error
+	RemoveCompanyUsers(companyID string) (int, error)
+	HasUser(id, email string) (idTaken bool, emailTaken bool, err error)
+	ResetPassword(userID, token, password string) error
+	EnableUserAndSetPasswordPhone(userID, password, phone string) error
+	LoginUser(email, password string) (*grpc_gateway_user.User, error)
+	GetSSOUser(email, companyID string) (*grpc_gateway_user.User, error)
+	GetUserBySMSCode(email, code string) (*grpc_gateway_user.User, error)
+	ConfirmSMSUser(email, code string) error
+	DeleteUserByID(id string) error
+	GetUsers(page *Page) (*grpc_gateway_user.UserListResponse, error)
+	GetUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error)
+	UpdateUserByID(oldUser, user *grpc_gateway_user.User, role, companyID string) (*grpc_gateway_user.User, error)
+}
+
+// CompanyStorage - storage of companies
+type CompanyStorage interface {
+	CreateCompany(company *grpc_gateway_company.Company) error
+	GetCompanyByID(id string) (*grpc_gateway_company.Company, error)
+	GetCompanies(page *Page) (*grpc_gateway_company.CompanyListResponse, error)
+	DeleteCompanyByID(id string) error
+	UpdateCompany(company *grpc_gateway_company.Company) error
+	ImportCompany(company *grpc_gateway_company.Company) error
+	RemoveCompany(id string) error
+	HasCompany(id string) (bool, error)
+}
+
+// EntityStorage - storage of entities. Every update of entity creates new revision, only the last
+// revision is marked as latest. Deletion and restore of entity are revisions too. Imported entities are
+// staged and aren't visible until import publishes them
+type EntityStorage interface {
+	CreateEntity(entity *grpc_gateway_entity.Entity) (*grpc_gateway_entity.Entity, error)
+	GetLatestEntity(id, companyID string) (*grpc_gateway_entity.Entity, error)
+	GetEntityRevs(id, companyID string, page *Page) (*grpc_gateway_entity.EntityListResponse, error)
+	GetCompanyRevisions(companyID string) (*grpc_gateway_entity.EntityListResponse, error)
+	HasEntity(id string) (bool, error)
+	GetEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error)
+	GetDeletedEntities(companyID string, params *grpc_gateway_entity.EntityListRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error)
+	SearchEntities(companyID string, filter *grpc_gateway_entity.EntitySearchRequest, page *Page) (*grpc_gateway_entity.EntityListResponse, error)
+	DeleteEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error)
+	RestoreEntity(id, companyID, userID string) (*grpc_gateway_entity.Entity, error)
+	PurgeDeletedEntities(deletedBefore int64) (int, error)
+	RemoveEntities(companyID string, ids []string) (int, error)
+	StageEntity(entity *grpc_gateway_entity.Entity, importID string) error
+	PublishStagedEntities(companyID, importID string) (int, error)
+	RemoveStagedEntities(companyID, importID string) (int, error)
+	UpdateEntity(entity *grpc_gateway_entity.Entity, baseRev int64) (*grpc_gateway_entity.Entity, error)
+}
+
+// SessionStorage - storage of login sessions
+type SessionStorage interface {
+	CreateSession(userID, refreshToken, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error)
+	CreateImpersonationSession(userID, impersonatorID, userAgent, ipAddress string, ttl time.Duration) (*grpc_gateway_user.Session, error)
+	GetActiveSession(id, userID string) (*grpc_gateway_user.Session, error)
+	GetActiveSessions(userID string, page *Page) (*grpc_gateway_user.SessionListResponse, error)
+	TouchSession(session *grpc_gateway_user.Session) error
+	RotateRefreshToken(refreshToken, newRefreshToken string) (*grpc_gateway_user.Session, error)
+	RevokeSession(id string) error
+	RevokeUserSession(id, userID string) error
+	RevokeUserSessions(userIDs ...string) error
+}
+
+// LoginAttemptStorage - storage of counters of failed login attempts
+type LoginAttemptStorage interface {
+	GetLockout(keys ...string) (time.Duration, error)
+	RegisterFailure(key string, threshold int, base, max, window time.Duration) (time.Duration, error)
+	Reset(keys ...string) error
+}
+
+// APIKeyStorage - storage of api keys of service accounts
+type APIKeyStorage interface {
+	CreateAPIKey(serviceAccount *grpc_gateway_user.User, name string, expiresAt int64) (*grpc_gateway_user.APIKey, string, error)
+	GetAPIKeyByID(id string) (*grpc_gateway_user.APIKey, error)
+	GetAPIKeys(serviceAccountID string, page *Page) (*grpc_gateway_user.APIKeyListResponse, error)
+	RevokeAPIKey(id string) error
+	RevokeServiceAccountAPIKeys(serviceAccountIDs ...string) error
+	Authenticate(key string) (*grpc_gateway_user.APIKey, error)
+}
+
+// AuditStorage - storage of audit trail
+type AuditStorage interface {
+	Record(record *AuditRecord) error
+}
+
+// IdentityProviderStorage - storage of identity providers of companies and pending sso logins
+type IdentityProviderStorage interface {
+	SetIdentityProvider(idp *grpc_gateway_company.IdentityProvider) error
+	GetIdentityProvider(companyID string) (*grpc_gateway_company.IdentityProvider, error)
+	RemoveIdentityProvider(companyID string) error
+	CreateSSOState(state, companyID, nonce string, ttl time.Duration) error
+	ConsumeSSOState(state string) (*SSOState, error)
+}
+
+// EntityImportStorage - storage of jobs which import entities from files
+type EntityImportStorage interface {
+	CreateEntityImport(job *grpc_gateway_entity.EntityImport) error
+	UpdateEntityImport(job *grpc_gateway_entity.EntityImport, status string, updatedAt int64) error
+	GetEntityImport(id, companyID string) (*grpc_gateway_entity.EntityImport, error)
+	GetStaleEntityImports(updatedBefore int64) ([]*grpc_gateway_entity.EntityImport, error)
+}
+
+var storageInstance Storage
+
+// NewStorage - create storage backend selected in config. Mongo storage fails if database is unreachable
+func NewStorage(cfg *Config) (Storage, error) {
+	switch cfg.Storage {
+	case StorageMemory:
+		return NewMemoryStorage(), nil
+	case StorageMongo:
+		pool, err := NewConnectionPool(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		storage := NewMongoStorage(pool)
+		if cfg.SkipMigrations {
+			return storage, nil
+		}
+
+		return storage, storage.Migrate()
+	}
+
+	return nil, ErrUnknownStorage
+}
+
+// MongoStorage - storage which keeps all objects in mongo
+type MongoStorage struct {
+	pool *ConnectionPool
+}
+
+// NewMongoStorage - returns new instance of MongoStorage which works with connections of pool
+func NewMongoStorage(pool *ConnectionPool) *MongoStorage {
+	return &MongoStorage{pool: pool}
+}
+
+// Open - get connection from pool
+func (ms *MongoStorage) Open() (StorageSession, error) {
+	sess, err := ms.pool.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoStorageSession{sess: sess}, nil
+}
+
+// Migrate - apply pending migrations of database
+func (ms *MongoStorage) Migrate() error {
+	sess, err := ms.pool.GetConnection()
+	if err != nil {
+		return err
+	}
+	defer sess.Session.Close()
+
+	runner, err := NewMigrationRunner(sess, Migrations)
+	if err != nil {
+		return err
+	}
+
+	_, err = runner.Run(false)
+	return err
+}
+
+// Reseal - seal personal data of users and entities by active encryption key.
+// Returns number of resealed documents
+func (ms *MongoStorage) Reseal() (int, error) {
+	sess, err := ms.pool.GetConnection()
+	if err != nil {
+		return 0, err
+	}
+	defer sess.Session.Close()
+
+	users, err := NewUserRepo(sess).Reseal()
+	if err != nil {
+		return users, err
+	}
+
+	entities, err := NewEntityRepo(sess).Reseal()
+	return users + entities, err
+}
+
+// RunKeyRotation - reseal documents every interval, it never returns
+func (ms *MongoStorage) RunKeyRotation(interval time.Duration) {
+	for {
+		changed, err := ms.Reseal()
+		if err != nil {
+			glog.Error(err)
+		} else if changed > 0 {
+			glog.Infof("%d documents are sealed by active encryption key", changed)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// RunRevisionRecovery - recover latest revisions of entities which updates or purges didn't finish
+// every interval, it never returns
+func (ms *MongoStorage) RunRevisionRecovery(interval time.Duration) {
+	for {
+		if sess, err := ms.pool.GetConnection(); err != nil {
+			glog.Error(err)
+		} else {
+			recovered, err := NewEntityRepo(sess).RecoverLatestRevisions()
+			if err != nil {
+				glog.Error(err)
+			} else if recovered > 0 {
+				glog.Warningf("Latest revisions of %d entities are recovered", recovered)
+			}
+
+			sess.Session.Close()
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// mongoStorageSession - repositories which work with one mongo connection
+type mongoStorageSession struct {
+	sess *mgo.Database
+}
+
+func (ms *mongoStorageSession) Users() UserStorage {
+	return NewUserRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) Companies() CompanyStorage {
+	return NewCompanyRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) Entities() EntityStorage {
+	return NewEntityRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) Sessions() SessionStorage {
+	return NewSessionRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) LoginAttempts() LoginAttemptStorage {
+	return NewLoginAttemptRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) APIKeys() APIKeyStorage {
+	return NewAPIKeyRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) Audit() AuditStorage {
+	return NewAuditRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) IdentityProviders() IdentityProviderStorage {
+	return NewIdentityProviderRepo(ms.sess)
+}
+
+func (ms *mongoStorageSession) EntityImports() EntityImportStorage {
+	return NewEntityImportRepo(ms.sess)
+}
+
+// Close - return connection to pool
+func (ms *mongoStorageSession) Close() {
+	ms.sess.Session.Close()
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// ErrTLSMisconfigured - error when tls settings are incomplete
+var ErrTLSMisconfigured = errors.New("tls requires both certificate and key, client certificates require tls and CA")
+
+// LoadServerTLSConfig - load tls config of listener. Returns nil config when certificate isn't configured,
+// then listener works in plaintext. Client certificates are verified when they are passed, and required
+// in mTLS-only mode
+func LoadServerTLSConfig(certFile, keyFile, clientCAFile string, requireClientCert bool) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" || requireClientCert {
+			return nil, ErrTLSMisconfigured
+		}
+
+		return nil, nil
+	}
+
+	if certFile == "" || keyFile == "" || (requireClientCert && clientCAFile == "") {
+		return nil, ErrTLSMisconfigured
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		cfg.ClientCAs, err = loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if requireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadClientTLSConfig - load tls config of client. Server certificate is verified by CA from caFile or by
+// system roots when caFile is empty. Client certificate is optional
+func LoadClientTLSConfig(certFile, keyFile, caFile, serverName string) (*tls.Config, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, ErrTLSMisconfigured
+	}
+
+	cfg := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		var err error
+		cfg.RootCAs, err = loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+
+	return pool, nil
+}
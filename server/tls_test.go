@@ -0,0 +1,200 @@
+package server_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	. "gopkg.in/check.v1"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"time"
+)
+
+type TLSTestSuite struct {
+	dir string
+}
+
+var _ = Suite(&TLSTestSuite{})
+
+// testCert - generated certificate and files of its certificate and key
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+func (tt *TLSTestSuite) SetUpTest(c *C) {
+	tt.dir = c.MkDir()
+}
+
+// newTestCert - generate certificate signed by parent, or self-signed CA when parent is nil
+func (tt *TLSTestSuite) newTestCert(c *C, name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	c.Assert(err, IsNil)
+
+	cert, err := x509.ParseCertificate(der)
+	c.Assert(err, IsNil)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	result := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(tt.dir, name+".crt"),
+		keyFile:  filepath.Join(tt.dir, name+".key"),
+	}
+
+	err = ioutil.WriteFile(result.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(result.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	c.Assert(err, IsNil)
+
+	return result
+}
+
+// handshake - make tls connection to listener with server config and return error of handshake
+func handshake(c *C, serverCfg, clientCfg *tls.Config) error {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	c.Assert(err, IsNil)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// echo byte of client, so client sees that handshake was accepted
+		buf := make([]byte, 1)
+		if _, err := conn.Read(buf); err == nil {
+			conn.Write(buf)
+		}
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// server rejects client certificate after client finished its part of handshake
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return err
+	}
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func (tt *TLSTestSuite) TestPlaintext(c *C) {
+	cfg, err := server.LoadServerTLSConfig("", "", "", false)
+	c.Assert(err, IsNil)
+	c.Assert(cfg, IsNil)
+}
+
+func (tt *TLSTestSuite) TestMisconfigured(c *C) {
+	ca := tt.newTestCert(c, "ca", nil)
+	srv := tt.newTestCert(c, "localhost", ca)
+
+	_, err := server.LoadServerTLSConfig(srv.certFile, "", "", false)
+	c.Assert(err, Equals, server.ErrTLSMisconfigured)
+
+	_, err = server.LoadServerTLSConfig("", "", ca.certFile, false)
+	c.Assert(err, Equals, server.ErrTLSMisconfigured)
+
+	// mTLS-only mode needs CA of clients
+	_, err = server.LoadServerTLSConfig(srv.certFile, srv.keyFile, "", true)
+	c.Assert(err, Equals, server.ErrTLSMisconfigured)
+
+	_, err = server.LoadClientTLSConfig(srv.certFile, "", ca.certFile, "localhost")
+	c.Assert(err, Equals, server.ErrTLSMisconfigured)
+
+	// key doesn't match certificate
+	_, err = server.LoadServerTLSConfig(srv.certFile, ca.keyFile, "", false)
+	c.Assert(err, NotNil)
+
+	// server fails on start instead of serving without tls
+	_, err = server.NewServer(&server.Config{GRPCTLSCertFile: srv.certFile, GRPCTLSKeyFile: srv.keyFile, GRPCClientCAFile: ca.certFile, GRPCRequireClientCert: true})
+	c.Assert(err, Equals, server.ErrTLSMisconfigured)
+
+	_, err = server.NewServer(&server.Config{HTTPTLSCertFile: filepath.Join(tt.dir, "missing.crt"), HTTPTLSKeyFile: srv.keyFile})
+	c.Assert(err, NotNil)
+}
+
+func (tt *TLSTestSuite) TestHandshake(c *C) {
+	ca := tt.newTestCert(c, "ca", nil)
+	srv := tt.newTestCert(c, "localhost", ca)
+	client := tt.newTestCert(c, "gateway", ca)
+
+	serverCfg, err := server.LoadServerTLSConfig(srv.certFile, srv.keyFile, ca.certFile, false)
+	c.Assert(err, IsNil)
+
+	anonymousCfg, err := server.LoadClientTLSConfig("", "", ca.certFile, "localhost")
+	c.Assert(err, IsNil)
+
+	clientCfg, err := server.LoadClientTLSConfig(client.certFile, client.keyFile, ca.certFile, "localhost")
+	c.Assert(err, IsNil)
+
+	c.Assert(handshake(c, serverCfg, anonymousCfg), IsNil)
+	c.Assert(handshake(c, serverCfg, clientCfg), IsNil)
+
+	// server name must match certificate
+	wrongNameCfg, err := server.LoadClientTLSConfig(client.certFile, client.keyFile, ca.certFile, "example.com")
+	c.Assert(err, IsNil)
+	c.Assert(handshake(c, serverCfg, wrongNameCfg), NotNil)
+}
+
+func (tt *TLSTestSuite) TestMutualTLSOnly(c *C) {
+	ca := tt.newTestCert(c, "ca", nil)
+	srv := tt.newTestCert(c, "localhost", ca)
+	client := tt.newTestCert(c, "gateway", ca)
+
+	otherCA := tt.newTestCert(c, "other-ca", nil)
+	stranger := tt.newTestCert(c, "stranger", otherCA)
+
+	serverCfg, err := server.LoadServerTLSConfig(srv.certFile, srv.keyFile, ca.certFile, true)
+	c.Assert(err, IsNil)
+
+	anonymousCfg, err := server.LoadClientTLSConfig("", "", ca.certFile, "localhost")
+	c.Assert(err, IsNil)
+	c.Assert(handshake(c, serverCfg, anonymousCfg), NotNil)
+
+	strangerCfg, err := server.LoadClientTLSConfig(stranger.certFile, stranger.keyFile, ca.certFile, "localhost")
+	c.Assert(err, IsNil)
+	c.Assert(handshake(c, serverCfg, strangerCfg), NotNil)
+
+	clientCfg, err := server.LoadClientTLSConfig(client.certFile, client.keyFile, ca.certFile, "localhost")
+	c.Assert(err, IsNil)
+	c.Assert(handshake(c, serverCfg, clientCfg), IsNil)
+}
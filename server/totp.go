@@ -0,0 +1,122 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FactorSMS, FactorTOTP - supported second factors of authorization
+const (
+	FactorSMS  = "sms"
+	FactorTOTP = "totp"
+)
+
+const (
+	totpPeriod = 30
+	totpDigits = 6
+
+	// totpSkew - number of periods before and after current one which are accepted for clock drift
+	totpSkew = 1
+)
+
+// ErrTOTPNotEnrolled - error when user tries to use totp without enrollment
+var ErrTOTPNotEnrolled = errors.New("authenticator app isn't enrolled for this user")
+
+// ErrTOTPConfirmationFailed - error when passed totp code is wrong or already used
+var ErrTOTPConfirmationFailed = errors.New("you passed wrong code from authenticator app")
+
+// ErrUnknownFactor - error when unsupported second factor is requested
+var ErrUnknownFactor = errors.New("unknown second factor")
+
+// GenerateTOTPSecret - generate new random base32-encoded secret for authenticator app
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(base32.StdEncoding.EncodeToString(b), "="), nil
+}
+
+// TOTPURI - return otpauth:// uri which can be imported to authenticator app (usually as qr-code)
+func TOTPURI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	secret = strings.TrimRight(secret, "=")
+	if n := len(secret) % 8; n != 0 {
+		secret += strings.Repeat("=", 8-n)
+	}
+
+	return base32.StdEncoding.DecodeString(secret)
+}
+
+func totpCode(key []byte, step uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, step)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation from RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// GenerateTOTPCode - return RFC 6238 code of secret for time t
+func GenerateTOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return totpCode(key, uint64(t.Unix())/totpPeriod), nil
+}
+
+// ValidateTOTPCode - check code against secret for time t. Steps up to lastStep were already used and
+// are rejected to prevent replay. Returns step of accepted code
+func ValidateTOTPCode(secret, code string, t time.Time, lastStep int64) (int64, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+
+	current := t.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+
+		if hmac.Equal([]byte(totpCode(key, uint64(step))), []byte(code)) {
+			return step, nil
+		}
+	}
+
+	return 0, ErrTOTPConfirmationFailed
+}
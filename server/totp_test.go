@@ -0,0 +1,73 @@
+package server_test
+
+import (
+	"encoding/base32"
+	"git.simplendi.com/FirmQ/frontend-server/server"
+	. "gopkg.in/check.v1"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type TOTPTestSuite struct{}
+
+var _ = Suite(&TOTPTestSuite{})
+
+// secret from test vectors of RFC 6238
+var rfcTOTPSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func (tt *TOTPTestSuite) TestGenerateTOTPCode(c *C) {
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for ts, expected := range vectors {
+		code, err := server.GenerateTOTPCode(rfcTOTPSecret, time.Unix(ts, 0))
+		c.Assert(err, IsNil)
+		c.Assert(code, Equals, expected)
+	}
+}
+
+func (tt *TOTPTestSuite) TestValidateTOTPCode(c *C) {
+	now := time.Unix(1111111109, 0)
+
+	step, err := server.ValidateTOTPCode(rfcTOTPSecret, "081804", now, 0)
+	c.Assert(err, IsNil)
+	c.Assert(step, Equals, int64(1111111109/30))
+
+	// code of previous period is accepted for clock drift
+	_, err = server.ValidateTOTPCode(rfcTOTPSecret, "081804", now.Add(time.Second*30), 0)
+	c.Assert(err, IsNil)
+
+	// but not older ones
+	_, err = server.ValidateTOTPCode(rfcTOTPSecret, "081804", now.Add(time.Minute*2), 0)
+	c.Assert(err, NotNil)
+
+	// used code can't be replayed
+	_, err = server.ValidateTOTPCode(rfcTOTPSecret, "081804", now, step)
+	c.Assert(err, NotNil)
+
+	_, err = server.ValidateTOTPCode(rfcTOTPSecret, "000000", now, 0)
+	c.Assert(err, NotNil)
+}
+
+func (tt *TOTPTestSuite) TestTOTPSecretAndURI(c *C) {
+	secret, err := server.GenerateTOTPSecret()
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(secret, "="), Equals, false)
+
+	_, err = server.GenerateTOTPCode(secret, time.Now())
+	c.Assert(err, IsNil)
+
+	uri, err := url.Parse(server.TOTPURI("FirmQ", "user@test.com", secret))
+	c.Assert(err, IsNil)
+	c.Assert(uri.Scheme, Equals, "otpauth")
+	c.Assert(uri.Host, Equals, "totp")
+	c.Assert(uri.Path, Equals, "/FirmQ:user@test.com")
+	c.Assert(uri.Query().Get("secret"), Equals, secret)
+	c.Assert(uri.Query().Get("issuer"), Equals, "FirmQ")
+}
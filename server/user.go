@@ -6,6 +6,7 @@ import (
 	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
 	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/glog"
 	google_protobuf1 "github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/satori/go.uuid"
@@ -17,54 +18,24 @@ import (
 )
 
 type userServer struct {
-	config *Config
+	config  *Config
+	storage Storage
 }
 
 // GetCurrentUser - retrieve current from context
 func GetCurrentUser(ctx context.Context) (*grpc_gateway_user.User, error) {
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := storageInstance.Open()
 	if err != nil {
 		return nil, err
 	}
+	defer sess.Close()
 
-	userRepo := NewUserRepo(sess)
+	userRepo := sess.Users()
 
 	userID := ctx.Value("user_id").(string)
 	return userRepo.GetUserByID(userID)
 }
 
-// IsAdminUser - check if current user has admin permissions
-func IsAdminUser(ctx context.Context) error {
-	currentUser, err := GetCurrentUser(ctx)
-	if err != nil {
-		err = fmt.Errorf("you should have admin permission for this action")
-		return err
-	}
-
-	if currentUser.IsAdmin == false {
-		err = fmt.Errorf("you should have admin permission for this action")
-		return err
-	}
-
-	return nil
-}
-
-// IsAdminOrCompanyUser - check if current user has admin permissions or linked with companyID company
-func IsAdminOrCompanyUser(ctx context.Context, companyID string) error {
-	currentUser, err := GetCurrentUser(ctx)
-	if err != nil {
-		err = fmt.Errorf("you should have admin permission or be company user for this action")
-		return err
-	}
-
-	if currentUser.IsAdmin == false && currentUser.CompanyId != companyID {
-		err = fmt.Errorf("you should have admin permission or be company user for this action")
-		return err
-	}
-
-	return nil
-}
-
 // NewCommonResponse - create  new instance of common response
 func NewCommonResponse() *grpc_gateway_common.CommonResponse {
 	message := &grpc_gateway_common.CommonResponse{}
@@ -86,6 +57,20 @@ func NewLoginResponse() *grpc_gateway_user.LoginResponse {
 	return message
 }
 
+// NewTOTPEnrollmentResponse - create new instance of totp enrollment response
+func NewTOTPEnrollmentResponse() *grpc_gateway_user.TOTPEnrollmentResponse {
+	message := &grpc_gateway_user.TOTPEnrollmentResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	return message
+}
+
+// NewResetPasswordResponse - create new instance of reset password response
+func NewResetPasswordResponse() *grpc_gateway_user.ResetPasswordResponse {
+	message := &grpc_gateway_user.ResetPasswordResponse{}
+	message.Meta = &grpc_gateway_common.MetaResponse{StatusCode: http.StatusOK}
+	return message
+}
+
 // NewUserResponse - create new instance of user response
 func NewUserResponse() *grpc_gateway_user.UserResponse {
 	message := &grpc_gateway_user.UserResponse{}
@@ -104,19 +89,29 @@ func filterUserResponseFields(resp *grpc_gateway_user.UserResponse) {
 	if resp.Data != nil {
 		resp.Data.Password = ""
 		resp.Data.SmsCode = ""
+		resp.Data.EmailCode = ""
+		resp.Data.TotpSecret = ""
+		resp.Data.TotpPendingSecret = ""
+		resp.Data.PasswordResetToken = ""
 	}
 }
 
 func filterUserListResponseFields(resp *grpc_gateway_user.UserListResponse) {
 	for _, us := range resp.Data {
 		us.Password = ""
+		us.SmsCode = ""
+		us.EmailCode = ""
+		us.TotpSecret = ""
+		us.TotpPendingSecret = ""
+		us.PasswordResetToken = ""
 	}
 }
 
 // NewUserServer - returns new grpc server which provide user-related functionality
-func NewUserServer(config *Config) grpc_gateway_user.UserServiceServer {
+func NewUserServer(config *Config, storage Storage) grpc_gateway_user.UserServiceServer {
 	us := &userServer{
-		config: config,
+		config:  config,
+		storage: storage,
 	}
 
 	return us
@@ -125,92 +120,636 @@ func NewUserServer(config *Config) grpc_gateway_user.UserServiceServer {
 func (s *userServer) Login(ctx context.Context, msg *grpc_gateway_user.LoginRequest) (message *grpc_gateway_user.LoginResponse, err error) {
 	message = NewLoginResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
 	smsGw := GetSMSGateway()
 	code := smsGw.GenerateRandomCode(6)
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
+	attemptRepo := sess.LoginAttempts()
+
+	accountKey := AccountAttemptKey(msg.Email)
+	address := ClientAddress(ctx)
+	addressKey := AddressAttemptKey(address)
+
+	lockout, err := attemptRepo.GetLockout(accountKey, addressKey)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if lockout > 0 {
+		setLockedResponse(message.Meta, lockout)
+		return message, nil
+	}
 
 	// if 1st step of authorization
-	if msg.SmsCode == "" {
+	if msg.SmsCode == "" && msg.TotpCode == "" {
 		user, err := repo.LoginUser(msg.Email, msg.Password)
 		if err != nil {
+			if lockout := s.registerLoginFailure(attemptRepo, accountKey, addressKey); lockout > 0 {
+				setLockedResponse(message.Meta, lockout)
+				return message, nil
+			}
+
 			message.Meta.Ok = false
 			message.Meta.Error = err.Error()
 			return message, nil
 		}
 
+		message.Meta.Ok = true
+		message.Meta.StatusCode = http.StatusPreconditionRequired
+
+		// enrolled users who prefer authenticator app don't get sms
+		if user.TotpEnabled && user.PreferredFactor == FactorTOTP {
+			message.SecondFactor = FactorTOTP
+			return message, nil
+		}
+
 		repo.SetSMSCode(user.Id, code)
 		smsGw.SendSMSMessage(user.Phone, code)
 
+		message.SecondFactor = FactorSMS
+		return message, nil
+	}
+
+	var storedUser *grpc_gateway_user.User
+	if msg.TotpCode != "" {
+		storedUser, err = s.checkTOTPLogin(repo, msg)
+		if err != nil {
+			if lockout := s.registerLoginFailure(attemptRepo, accountKey, addressKey); lockout > 0 {
+				setLockedResponse(message.Meta, lockout)
+				return message, nil
+			}
+
+			message.Meta.Ok = false
+			message.Meta.Error = err.Error()
+			return message, nil
+		}
+	} else {
+		storedUser, err = repo.GetUserBySMSCode(msg.Email, msg.SmsCode)
+		if err != nil {
+			repo.RegisterSMSCodeFailure(msg.Email, s.config.SMSCodeMaxAttempts)
+			if lockout := s.registerLoginFailure(attemptRepo, accountKey, addressKey); lockout > 0 {
+				setLockedResponse(message.Meta, lockout)
+				return message, nil
+			}
+
+			message.Meta.Ok = false
+			message.Meta.Error = ErrSMSConfirmationFailed.Error()
+			return message, nil
+		}
+
+		if time.Unix(storedUser.SmsSentAt.Seconds, 0).Add(s.config.SMSConfirmationTTL).Before(time.Now()) {
+			message.Meta.Ok = false
+			message.Meta.Error = ErrSMSCodeExpired.Error()
+			return message, nil
+		}
+
+		err = repo.ConfirmSMSUser(msg.Email, msg.SmsCode)
+		if err != nil {
+			message.Meta.Ok = false
+			message.Meta.Error = ErrSMSConfirmationFailed.Error()
+			return message, nil
+		}
+	}
+
+	// successful login clears failures of account, failures of address are kept until they expire
+	attemptRepo.Reset(accountKey)
+
+	if err := s.startSession(sess, message, storedUser, clientUserAgent(ctx), address); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+// startSession - create session for authenticated user and put its tokens to response
+func (s *userServer) startSession(sess StorageSession, message *grpc_gateway_user.LoginResponse, user *grpc_gateway_user.User, userAgent, ipAddress string) error {
+	refreshToken, err := GenerateSecretToken()
+	if err != nil {
+		return err
+	}
+
+	session, err := sess.Sessions().CreateSession(user.Id, refreshToken, userAgent, ipAddress, s.config.RefreshTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.issueAccessToken(message, user, session); err != nil {
+		return err
+	}
+
+	message.RefreshToken = refreshToken
+	return nil
+}
+
+// checkTOTPLogin - check credentials and code from authenticator app. Unlike sms-code, totp code isn't bound
+// to first step of authorization, so password is checked again
+func (s *userServer) checkTOTPLogin(repo UserStorage, msg *grpc_gateway_user.LoginRequest) (*grpc_gateway_user.User, error) {
+	user, err := repo.LoginUser(msg.Email, msg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TotpEnabled {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	step, err := ValidateTOTPCode(user.TotpSecret, msg.TotpCode, time.Now(), user.TotpLastStep)
+	if err != nil {
+		return nil, ErrTOTPConfirmationFailed
+	}
+
+	// each code can be used only once
+	if err := repo.SetTOTPLastStep(user.Id, step); err != nil {
+		return nil, ErrTOTPConfirmationFailed
+	}
+
+	return user, nil
+}
+
+// registerLoginFailure - count failed attempt for account and client address, return lockout if any of them is locked
+func (s *userServer) registerLoginFailure(attemptRepo LoginAttemptStorage, accountKey, addressKey string) time.Duration {
+	cfg := s.config
+
+	accountLockout, err := attemptRepo.RegisterFailure(accountKey, cfg.LoginMaxFailures, cfg.LoginLockoutBase, cfg.LoginLockoutMax, cfg.LoginFailuresWindow)
+	if err != nil {
+		glog.Error(err)
+	}
+
+	addressLockout, err := attemptRepo.RegisterFailure(addressKey, cfg.LoginMaxAddressFailures, cfg.LoginLockoutBase, cfg.LoginLockoutMax, cfg.LoginFailuresWindow)
+	if err != nil {
+		glog.Error(err)
+	}
+
+	if addressLockout > accountLockout {
+		return addressLockout
+	}
+
+	return accountLockout
+}
+
+// setLockedResponse - fill meta of response for locked account
+func setLockedResponse(meta *grpc_gateway_common.MetaResponse, lockout time.Duration) {
+	meta.Ok = false
+	meta.StatusCode = http.StatusTooManyRequests
+	meta.Error = fmt.Sprintf("%v, retry after %v", ErrAccountLocked, lockout/time.Second*time.Second+time.Second)
+}
+
+// issueAccessToken - sign short-lived access token for session and put it to response
+func (s *userServer) issueAccessToken(message *grpc_gateway_user.LoginResponse, user *grpc_gateway_user.User, session *grpc_gateway_user.Session) error {
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["admin"] = IsPlatformAdmin(user)
+	claims["role"] = UserRole(user)
+	claims["name"] = user.Name
+	claims["company_id"] = user.CompanyId
+	claims["user_id"] = user.Id
+	claims["sid"] = session.Id
+
+	// impersonation token can't be refreshed, so it lives as long as its session
+	expiresAt := time.Now().Add(s.config.AccessTokenTTL).Unix()
+	if session.ImpersonatorId != "" {
+		claims["impersonator_id"] = session.ImpersonatorId
+		expiresAt = session.ExpiresAt
+	}
+	claims["exp"] = expiresAt
+
+	// sign the token with active key
+	tokenString, err := GetJWTKeySet().SignToken(token)
+	if err != nil {
+		return err
+	}
+
+	message.Token = tokenString
+	message.ExpiresIn = expiresAt - time.Now().Unix()
+	return nil
+}
+
+func (s *userServer) Refresh(ctx context.Context, msg *grpc_gateway_user.RefreshRequest) (*grpc_gateway_user.LoginResponse, error) {
+	message := NewLoginResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	sessionRepo := sess.Sessions()
+	userRepo := sess.Users()
+
+	refreshToken, err := GenerateSecretToken()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	session, err := sessionRepo.RotateRefreshToken(msg.RefreshToken, refreshToken)
+	if err != nil {
+		message.Meta.StatusCode = http.StatusUnauthorized
+		message.Meta.Ok = false
+		message.Meta.Error = ErrInvalidRefreshToken.Error()
+		return message, nil
+	}
+
+	user, err := userRepo.GetUserByID(session.UserId)
+	if err != nil {
+		sessionRepo.RevokeSession(session.Id)
+
+		message.Meta.StatusCode = http.StatusUnauthorized
+		message.Meta.Ok = false
+		message.Meta.Error = ErrSessionRevoked.Error()
+		return message, nil
+	}
+
+	if err := s.issueAccessToken(message, user, session); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	message.RefreshToken = refreshToken
+
+	return message, nil
+}
+
+func (s *userServer) Logout(ctx context.Context, in *google_protobuf1.Empty) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	sessionRepo := sess.Sessions()
+
+	sessionID := ctx.Value("session_id").(string)
+	if err := sessionRepo.RevokeSession(sessionID); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) RequestPasswordReset(ctx context.Context, in *grpc_gateway_user.PasswordResetRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	token, err := GenerateSecretToken()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	// response doesn't depend on existence of user, so it can't be used for checking emails
+	user, err := sess.Users().SetPasswordResetToken(in.Email, token)
+	switch err {
+	case nil:
+		GetEmailSenderInstance().SendPasswordReset(user.Name, user.Email, token)
+	case mgo.ErrNotFound:
+	default:
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) ResetPassword(ctx context.Context, in *grpc_gateway_user.ResetPasswordRequest) (*grpc_gateway_user.ResetPasswordResponse, error) {
+	message := NewResetPasswordResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.Users()
+	attemptRepo := sess.LoginAttempts()
+
+	storedUser, err := repo.GetUserByPasswordResetToken(in.Token)
+	if err != nil || storedUser.PasswordResetSentAt == nil ||
+		time.Unix(storedUser.PasswordResetSentAt.Seconds, 0).Add(s.config.PasswordResetTTL).Before(time.Now()) {
+		message.Meta.StatusCode = http.StatusNotFound
+		message.Meta.Ok = false
+		message.Meta.Error = ErrPasswordResetExpired.Error()
+		return message, nil
+	}
+
+	if in.Password == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = "password is required"
+		return message, nil
+	}
+
+	accountKey := AccountAttemptKey(storedUser.Email)
+	address := ClientAddress(ctx)
+	addressKey := AddressAttemptKey(address)
+
+	lockout, err := attemptRepo.GetLockout(accountKey, addressKey)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if lockout > 0 {
+		setLockedResponse(message.Meta, lockout)
+		return message, nil
+	}
+
+	useTOTP := storedUser.TotpEnabled && (in.TotpCode != "" || storedUser.PreferredFactor == FactorTOTP)
+
+	// password is changed only after confirmation with second factor
+	switch {
+	case useTOTP && in.TotpCode == "":
+		message.Meta.Ok = true
+		message.Meta.StatusCode = http.StatusPreconditionRequired
+		message.SecondFactor = FactorTOTP
+		return message, nil
+
+	case useTOTP:
+		step, err := ValidateTOTPCode(storedUser.TotpSecret, in.TotpCode, time.Now(), storedUser.TotpLastStep)
+		if err == nil {
+			err = repo.SetTOTPLastStep(storedUser.Id, step)
+		}
+
+		if err != nil {
+			if lockout := s.registerLoginFailure(attemptRepo, accountKey, addressKey); lockout > 0 {
+				setLockedResponse(message.Meta, lockout)
+				return message, nil
+			}
+
+			message.Meta.Ok = false
+			message.Meta.Error = ErrTOTPConfirmationFailed.Error()
+			return message, nil
+		}
+
+	case in.SmsCode == "":
+		smsGw := GetSMSGateway()
+		code := smsGw.GenerateRandomCode(6)
+
+		if err := repo.SetSMSCode(storedUser.Id, code); err != nil {
+			message.Meta.Ok = false
+			message.Meta.Error = err.Error()
+			return message, nil
+		}
+
+		if err := smsGw.SendSMSMessage(storedUser.Phone, code); err != nil {
+			message.Meta.Ok = false
+			message.Meta.Error = err.Error()
+			return message, nil
+		}
+
 		message.Meta.Ok = true
 		message.Meta.StatusCode = http.StatusPreconditionRequired
+		message.SecondFactor = FactorSMS
+		return message, nil
+
+	default:
+		if storedUser.SmsSentAt == nil || storedUser.SmsCode == "" || storedUser.SmsCode != in.SmsCode {
+			repo.RegisterSMSCodeFailure(storedUser.Email, s.config.SMSCodeMaxAttempts)
+			if lockout := s.registerLoginFailure(attemptRepo, accountKey, addressKey); lockout > 0 {
+				setLockedResponse(message.Meta, lockout)
+				return message, nil
+			}
+
+			message.Meta.Ok = false
+			message.Meta.Error = ErrSMSConfirmationFailed.Error()
+			return message, nil
+		}
+
+		if time.Unix(storedUser.SmsSentAt.Seconds, 0).Add(s.config.SMSConfirmationTTL).Before(time.Now()) {
+			message.Meta.Ok = false
+			message.Meta.Error = ErrSMSCodeExpired.Error()
+			return message, nil
+		}
+	}
 
+	if err := repo.ResetPassword(storedUser.Id, in.Token, in.Password); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
 		return message, nil
 	}
 
-	storedUser, err := repo.GetUserBySMSCode(msg.Email, msg.SmsCode)
+	// whoever knew old password shouldn't keep access
+	if err := sess.Sessions().RevokeUserSessions(storedUser.Id); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	attemptRepo.Reset(accountKey)
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) EnrollTOTP(ctx context.Context, in *google_protobuf1.Empty) (*grpc_gateway_user.TOTPEnrollmentResponse, error) {
+	message := NewTOTPEnrollmentResponse()
+
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
-		message.Meta.Error = ErrSMSConfirmationFailed.Error()
+		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	if time.Unix(storedUser.SmsSentAt.Seconds, 0).Add(s.config.SMSConfirmationTTL).Before(time.Now()) {
+	repo := sess.Users()
+	user, err := repo.GetUserByID(ctx.Value("user_id").(string))
+	if err != nil {
 		message.Meta.Ok = false
-		message.Meta.Error = ErrSMSCodeExpired.Error()
+		message.Meta.Error = err.Error()
 		return message, nil
 	}
 
-	err = repo.ConfirmSMSUser(msg.Email, msg.SmsCode)
+	secret, err := GenerateTOTPSecret()
 	if err != nil {
 		message.Meta.Ok = false
-		message.Meta.Error = ErrSMSConfirmationFailed.Error()
+		message.Meta.Error = err.Error()
 		return message, nil
 	}
 
-	token := jwt.New(jwt.SigningMethodHS256)
+	// secret becomes active only after confirmation, so current enrollment keeps working until then
+	if err := repo.SetTOTPPendingSecret(user.Id, secret); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["admin"] = storedUser.IsAdmin
-	claims["name"] = storedUser.Name
-	claims["company_id"] = storedUser.CompanyId
-	claims["user_id"] = storedUser.Id
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
+	message.Meta.Ok = true
+	message.Secret = secret
+	message.Uri = TOTPURI(s.config.TOTPIssuer, user.Email, secret)
+	return message, nil
+}
+
+func (s *userServer) ConfirmTOTP(ctx context.Context, in *grpc_gateway_user.TOTPConfirmationRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
 
-	//Sign the token with our secret
-	tokenString, _ := token.SignedString(secretKey)
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.Users()
+	user, err := repo.GetUserByID(ctx.Value("user_id").(string))
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if user.TotpPendingSecret == "" {
+		message.Meta.Ok = false
+		message.Meta.Error = ErrTOTPNotEnrolled.Error()
+		return message, nil
+	}
+
+	step, err := ValidateTOTPCode(user.TotpPendingSecret, in.Code, time.Now(), 0)
+	if err != nil {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = ErrTOTPConfirmationFailed.Error()
+		return message, nil
+	}
+
+	if err := repo.EnableTOTP(user.Id, user.TotpPendingSecret, step); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
 
 	message.Meta.Ok = true
-	message.Token = tokenString
+	return message, nil
+}
 
+func (s *userServer) SetPreferredFactor(ctx context.Context, in *grpc_gateway_user.PreferredFactorRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	repo := sess.Users()
+	user, err := repo.GetUserByID(ctx.Value("user_id").(string))
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	switch in.Factor {
+	case FactorSMS:
+	case FactorTOTP:
+		if !user.TotpEnabled {
+			message.Meta.Ok = false
+			message.Meta.Error = ErrTOTPNotEnrolled.Error()
+			return message, nil
+		}
+	default:
+		message.Meta.Ok = false
+		message.Meta.Error = ErrUnknownFactor.Error()
+		return message, nil
+	}
+
+	if err := repo.SetPreferredFactor(user.Id, in.Factor); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
 	return message, nil
 }
 
+// requestedRole - role which is requested in user object. Clients which don't know about roles pass is_admin flag,
+// then fallback is used for non-admins
+func requestedRole(user *grpc_gateway_user.User, fallback string) string {
+	if user.Role != "" {
+		return user.Role
+	}
+
+	if user.IsAdmin {
+		return RolePlatformAdmin
+	}
+
+	if fallback == RolePlatformAdmin {
+		return RoleEditor
+	}
+
+	return fallback
+}
+
 func (s *userServer) CreateUser(ctx context.Context, user *grpc_gateway_user.User) (*grpc_gateway_user.UserResponse, error) {
 	message := NewUserResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
 
-	if err = IsAdminUser(ctx); err != nil {
-		message.Meta.StatusCode = http.StatusForbidden
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
 
+	// company admins can create users only in their company and can't create platform admins
+	user.Role = requestedRole(user, RoleEditor)
+	if !CanAssignRole(currentUser, user.Role, user.CompanyId) {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = ErrPermissionDenied.Error()
+		return message, nil
+	}
+
 	code := uuid.NewV4().String()
 	user.EmailCode = code
 	user.EmailSentAt = &timestamp.Timestamp{}
@@ -239,14 +778,15 @@ func (s *userServer) CreateUser(ctx context.Context, user *grpc_gateway_user.Use
 
 func (s *userServer) ConfirmEmail(ctx context.Context, incomeUser *grpc_gateway_user.User) (*grpc_gateway_user.UserResponse, error) {
 	message := NewUserResponse()
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
 
 	storedUser, err := repo.GetUserByEmailCode(incomeUser)
 	if err != nil {
@@ -277,6 +817,8 @@ func (s *userServer) ConfirmEmail(ctx context.Context, incomeUser *grpc_gateway_
 			}
 
 		} else {
+			repo.RegisterSMSCodeFailure(storedUser.Email, s.config.SMSCodeMaxAttempts)
+
 			message.Meta.Ok = false
 			message.Meta.Error = ErrSMSConfirmationFailed.Error()
 			return message, nil
@@ -318,26 +860,28 @@ func (s *userServer) ConfirmEmail(ctx context.Context, incomeUser *grpc_gateway_
 func (s *userServer) GetUser(ctx context.Context, id *grpc_gateway_common.IDRequest) (user *grpc_gateway_user.UserResponse, err error) {
 	user = NewUserResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		user.Meta.Ok = false
 		user.Meta.Error = err.Error()
 		return user, nil
 	}
+	defer sess.Close()
 
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
 
 	userID := ctx.Value("user_id").(string)
 
-	if err = IsAdminUser(ctx); userID != id.Id && err != nil {
+	user.Data, err = repo.GetUserByID(id.Id)
+
+	if userID != id.Id && CheckPermission(ctx, PermissionUserRead, user.Data.CompanyId) != nil {
+		user.Data = nil
 		user.Meta.StatusCode = http.StatusForbidden
 		user.Meta.Ok = false
-		user.Meta.Error = err.Error()
+		user.Meta.Error = ErrPermissionDenied.Error()
 		return user, nil
 	}
 
-	user.Data, err = repo.GetUserByID(id.Id)
-
 	if err == mgo.ErrNotFound {
 		user.Meta.StatusCode = http.StatusNotFound
 		user.Meta.Ok = false
@@ -358,16 +902,18 @@ func (s *userServer) GetUser(ctx context.Context, id *grpc_gateway_common.IDRequ
 func (s *userServer) DeleteUser(ctx context.Context, in *grpc_gateway_common.IDRequest) (message *grpc_gateway_common.CommonResponse, err error) {
 	message = NewCommonResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	userRepo := NewUserRepo(sess)
+	userRepo := sess.Users()
 
-	if err = IsAdminUser(ctx); err != nil {
+	storedUser, err := userRepo.GetUserByID(in.Id)
+	if err = CheckPermission(ctx, PermissionUserManage, storedUser.CompanyId); err != nil {
 		message.Meta.StatusCode = http.StatusForbidden
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
@@ -375,6 +921,11 @@ func (s *userServer) DeleteUser(ctx context.Context, in *grpc_gateway_common.IDR
 	}
 
 	err = userRepo.DeleteUserByID(in.Id)
+	if err == nil {
+		// outstanding tokens of deleted user shouldn't work anymore
+		err = sess.Sessions().RevokeUserSessions(in.Id)
+	}
+
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
@@ -385,27 +936,69 @@ func (s *userServer) DeleteUser(ctx context.Context, in *grpc_gateway_common.IDR
 	return message, nil
 }
 
-func (s *userServer) GetUserByCompany(ctx context.Context, in *grpc_gateway_common.IDRequest) (users *grpc_gateway_user.UserListResponse, err error) {
+func (s *userServer) UnlockUser(ctx context.Context, in *grpc_gateway_common.IDRequest) (*grpc_gateway_common.CommonResponse, error) {
+	message := NewCommonResponse()
+
+	sess, err := s.storage.Open()
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+	defer sess.Close()
+
+	user, err := sess.Users().GetUserByID(in.Id)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			message.Meta.StatusCode = http.StatusNotFound
+		}
+
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err = CheckPermission(ctx, PermissionUserManage, user.CompanyId); err != nil {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	if err = sess.LoginAttempts().Reset(AccountAttemptKey(user.Email)); err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	message.Meta.Ok = true
+	return message, nil
+}
+
+func (s *userServer) GetUserByCompany(ctx context.Context, in *grpc_gateway_common.ListRequest) (users *grpc_gateway_user.UserListResponse, err error) {
 	users = NewUserListResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, UserSortFields, "email")
 	if err != nil {
+		users.Meta.StatusCode = http.StatusBadRequest
 		users.Meta.Ok = false
 		users.Meta.Error = err.Error()
 		return users, nil
 	}
 
-	if err = IsAdminOrCompanyUser(ctx, in.Id); err != nil {
-		users.Meta.StatusCode = http.StatusForbidden
+	sess, err := s.storage.Open()
+	if err != nil {
 		users.Meta.Ok = false
 		users.Meta.Error = err.Error()
 		return users, nil
-
 	}
+	defer sess.Close()
+
+	userRepo := sess.Users()
 
-	userRepo := NewUserRepo(sess)
+	users, err = userRepo.GetUsersByCompanyID(in.Id, page)
 
-	users, err = userRepo.GetUsersByCompanyID(in.Id)
+	filterUserListResponseFields(users)
 	if err != nil {
 		users.Meta.Ok = false
 		users.Meta.Error = err.Error()
@@ -417,26 +1010,28 @@ func (s *userServer) GetUserByCompany(ctx context.Context, in *grpc_gateway_comm
 
 }
 
-func (s *userServer) GetUsers(ctx context.Context, in *google_protobuf1.Empty) (users *grpc_gateway_user.UserListResponse, err error) {
+func (s *userServer) GetUsers(ctx context.Context, in *grpc_gateway_common.ListRequest) (users *grpc_gateway_user.UserListResponse, err error) {
 	users = NewUserListResponse()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	page, err := NewPage(in.Cursor, in.Limit, in.Sort, UserSortFields, "email")
 	if err != nil {
+		users.Meta.StatusCode = http.StatusBadRequest
 		users.Meta.Ok = false
 		users.Meta.Error = err.Error()
 		return users, nil
 	}
 
-	if err = IsAdminUser(ctx); err != nil {
-		users.Meta.StatusCode = http.StatusForbidden
+	sess, err := s.storage.Open()
+	if err != nil {
 		users.Meta.Ok = false
 		users.Meta.Error = err.Error()
 		return users, nil
 	}
+	defer sess.Close()
 
-	userRepo := NewUserRepo(sess)
+	userRepo := sess.Users()
 
-	users, err = userRepo.GetUsers()
+	users, err = userRepo.GetUsers(page)
 
 	filterUserListResponseFields(users)
 	if err != nil {
@@ -456,28 +1051,63 @@ func (s *userServer) UpdateUser(ctx context.Context, in *grpc_gateway_user.User)
 		filterUserResponseFields(message)
 	}()
 
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		message.Meta.Ok = false
 		message.Meta.Error = err.Error()
 		return message, nil
 	}
+	defer sess.Close()
 
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
 	userID := ctx.Value("user_id").(string)
 
-	isAdminUser := IsAdminUser(ctx) == nil
-	if in.Id != userID && !isAdminUser {
+	currentUser, err := GetCurrentUser(ctx)
+	if err != nil {
+		message.Meta.Ok = false
+		message.Meta.Error = err.Error()
+		return message, nil
+	}
+
+	storedUser, err := repo.GetUserByID(in.Id)
+
+	canManage := HasPermission(currentUser, PermissionUserManage, storedUser.CompanyId)
+	if in.Id != userID && !canManage {
 		message.Meta.StatusCode = http.StatusForbidden
 		message.Meta.Ok = false
 		message.Meta.Error = ErrOwner.Error()
 		return message, nil
 	}
 
+	// role and company are changed only by managers, who can't grant more than they have
+	role := requestedRole(in, UserRole(storedUser))
+	companyID := storedUser.CompanyId
+	if canManage {
+		if IsPlatformAdmin(currentUser) {
+			companyID = in.CompanyId
+		}
+
+		if !CanAssignRole(currentUser, role, companyID) {
+			message.Meta.StatusCode = http.StatusForbidden
+			message.Meta.Ok = false
+			message.Meta.Error = ErrPermissionDenied.Error()
+			return message, nil
+		}
+	} else {
+		role = UserRole(storedUser)
+	}
+
+	// admin who acts as user can't change credentials of user, email is login and receives password resets
+	if impersonatorID, _ := ctx.Value("impersonator_id").(string); impersonatorID != "" && (in.Password != "" || in.Phone != storedUser.Phone || in.Email != storedUser.Email) {
+		message.Meta.StatusCode = http.StatusForbidden
+		message.Meta.Ok = false
+		message.Meta.Error = ErrImpersonationForbidden.Error()
+		return message, nil
+	}
+
 	smsGw := GetSMSGateway()
 	newSMSCode := smsGw.GenerateRandomCode(6)
 
-	storedUser, err := repo.GetUserByID(in.Id)
 	message.Data = storedUser
 
 	if storedUser.Phone != in.Phone {
@@ -497,6 +1127,8 @@ func (s *userServer) UpdateUser(ctx context.Context, in *grpc_gateway_user.User)
 		}
 
 		if storedUser.SmsCode != in.SmsCode {
+			repo.RegisterSMSCodeFailure(storedUser.Email, s.config.SMSCodeMaxAttempts)
+
 			message.Meta.Ok = false
 			message.Meta.Error = ErrSMSConfirmationFailed.Error()
 			message.Meta.StatusCode = http.StatusForbidden
@@ -504,7 +1136,7 @@ func (s *userServer) UpdateUser(ctx context.Context, in *grpc_gateway_user.User)
 		}
 	}
 
-	storedUser, err = repo.UpdateUserByID(isAdminUser, storedUser, in)
+	storedUser, err = repo.UpdateUserByID(storedUser, in, role, companyID)
 	message.Data = storedUser
 
 	if err != nil {
@@ -521,29 +1153,30 @@ func (s *userServer) UpdateUser(ctx context.Context, in *grpc_gateway_user.User)
 }
 
 func (s *userServer) createDefaultUser() error {
-	sess, err := connectionPoolInstance.GetConnection()
+	sess, err := s.storage.Open()
 	if err != nil {
 		return err
 	}
+	defer sess.Close()
 
-	repo := NewUserRepo(sess)
+	repo := sess.Users()
 	user := &grpc_gateway_user.User{
 		Name:        "Default admin user",
 		Email:       "user_admin@simplendi.com",
 		Phone:       "9999",
 		IsAdmin:     true,
+		Role:        RolePlatformAdmin,
 		IsEnabled:   true,
 		IsConfirmed: true,
 		Password:    "[frth[fr",
 	}
 
-	// create required indexes in user collection
-	repo.CreateIndexes()
-
-	err = repo.CreateUser(user)
-	if err == mgo.ErrNotFound {
-		return err
+	// user is created without password and phone like invited users, so they are set by confirmation
+	password, phone := user.Password, user.Phone
+	if err := repo.CreateUser(user); err != nil {
+		// default user already exists
+		return nil
 	}
 
-	return nil
+	return repo.EnableUserAndSetPasswordPhone(user.Id, password, phone)
 }
@@ -9,6 +9,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
+	"regexp"
 	"time"
 )
 
@@ -24,53 +25,247 @@ var ErrOwner = errors.New("you should be owner of this object for making any cha
 // ErrEmailCodeExpired - error when email code is expired, needs to be regenerated
 var ErrEmailCodeExpired = errors.New("email code for this user is expired")
 
+// ErrInvitationAccepted - error when invitation is resent to user who already confirmed email
+var ErrInvitationAccepted = errors.New("user already accepted invitation")
+
+// ErrPasswordResetExpired - error when password reset link is unknown, already used or expired
+var ErrPasswordResetExpired = errors.New("password reset link is invalid or expired")
+
 // ErrSMSCodeExpired - error when sms-code is expired, needs to be regenerated
 var ErrSMSCodeExpired = errors.New("sms-code for this user is expired")
 
 // UserRepo - model for accessing users in database
 type UserRepo struct {
-	sess *mgo.Database
-	coll string
+	sess   *mgo.Database
+	coll   string
+	cipher *FieldCipher
 }
 
 // NewUserRepo - returns new instance of UserRepo which provide access to user model
 func NewUserRepo(sess *mgo.Database) *UserRepo {
 	return &UserRepo{
-		sess: sess,
-		coll: "users",
+		sess:   sess,
+		coll:   "users",
+		cipher: GetFieldCipher(),
 	}
 }
 
-// CreateUser - create new user
-func (ur *UserRepo) CreateUser(user *grpc_gateway_user.User) error {
+// userSecrets - personal data of user which is sealed in stored documents
+type userSecrets struct {
+	Phone string
+}
+
+// storedUser - stored document of user. When encryption is configured personal data is kept only in sealed fields
+type storedUser struct {
+	grpc_gateway_user.User `bson:",inline"`
+	Sealed                 *SealedFields `bson:"sealed,omitempty"`
+}
+
+// userAAD - sealed fields are bound to user
+func userAAD(userID string) string {
+	return "users:" + userID
+}
+
+// sealPhone - fields of stored document with phone, which is sealed if cipher is configured
+func (ur *UserRepo) sealPhone(userID, phone string) (bson.M, error) {
+	if ur.cipher == nil {
+		return bson.M{"phone": phone}, nil
+	}
+
+	sealed, err := ur.cipher.Seal(&userSecrets{Phone: phone}, userAAD(userID))
+	return bson.M{"phone": "", "sealed": sealed}, err
+}
+
+// document - stored document of user, personal data is sealed if cipher is configured
+func (ur *UserRepo) document(user *grpc_gateway_user.User) (*storedUser, error) {
+	doc := &storedUser{User: *cloneUser(user)}
+	if ur.cipher == nil {
+		return doc, nil
+	}
+
+	sealed, err := ur.cipher.Seal(&userSecrets{Phone: user.Phone}, userAAD(user.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Sealed = sealed
+	doc.Phone = ""
+	return doc, nil
+}
+
+// open - user of stored document with opened personal data
+func (ur *UserRepo) open(doc *storedUser) (*grpc_gateway_user.User, error) {
+	user := cloneUser(&doc.User)
+	if doc.Sealed == nil {
+		return user, nil
+	}
+
+	secrets := userSecrets{}
+	if err := ur.cipher.Open(doc.Sealed, userAAD(user.Id), &secrets); err != nil {
+		return &grpc_gateway_user.User{}, err
+	}
+
+	user.Phone = secrets.Phone
+	return user, nil
+}
+
+// findUser - find one user which matches query
+func (ur *UserRepo) findUser(query bson.M) (*grpc_gateway_user.User, error) {
+	c := ur.sess.C(ur.coll)
+	doc := storedUser{}
+
+	if err := c.Find(query).One(&doc); err != nil {
+		return &grpc_gateway_user.User{}, err
+	}
+
+	return ur.open(&doc)
+}
+
+// applyUser - change one user which matches query and return changed user
+func (ur *UserRepo) applyUser(query bson.M, change mgo.Change) (*grpc_gateway_user.User, error) {
+	c := ur.sess.C(ur.coll)
+	doc := storedUser{}
+
+	if _, err := c.Find(query).Apply(change, &doc); err != nil {
+		return &grpc_gateway_user.User{}, err
+	}
+
+	return ur.open(&doc)
+}
+
+// findUsers - find page of users which match query
+func (ur *UserRepo) findUsers(query bson.M, page *Page) (*grpc_gateway_user.UserListResponse, error) {
 	c := ur.sess.C(ur.coll)
+	users := NewUserListResponse()
+
+	docs := []*storedUser{}
+	next, total, err := findPage(c, query, page, &docs)
+	if err != nil {
+		return users, err
+	}
 
+	for _, doc := range docs {
+		user, err := ur.open(doc)
+		if err != nil {
+			return users, err
+		}
+
+		users.Data = append(users.Data, user)
+	}
+
+	users.NextCursor = next
+	users.Total = int64(total)
+	return users, nil
+}
+
+// prepareNewUser - set fields of new user which can't be passed by client
+func prepareNewUser(user *grpc_gateway_user.User) {
 	user.Id = uuid.NewV4().String()
 
+	// is_admin is kept for clients which don't know about roles
+	user.IsAdmin = user.Role == RolePlatformAdmin
+
 	// don't set password and phone until email isn't confirmed
 	user.Password = ""
 	user.Phone = ""
 	user.IsEnabled = true
 
-	return c.Insert(user)
+	// second factor is configured by the user after enrollment
+	user.PreferredFactor = FactorSMS
+	user.TotpEnabled = false
+	user.TotpSecret = ""
+	user.TotpPendingSecret = ""
+	user.TotpLastStep = 0
+	user.PasswordResetToken = ""
+	user.PasswordResetSentAt = nil
+	user.IsServiceAccount = false
 }
 
-// GetUserByID - get user from database by id
-func (ur *UserRepo) GetUserByID(id string) (*grpc_gateway_user.User, error) {
+// CreateUser - create new user
+func (ur *UserRepo) CreateUser(user *grpc_gateway_user.User) error {
+	c := ur.sess.C(ur.coll)
+
+	prepareNewUser(user)
+	doc, err := ur.document(user)
+	if err != nil {
+		return err
+	}
+
+	return c.Insert(doc)
+}
+
+// newServiceAccount - returns new service account of company
+func newServiceAccount(name, companyID, role string) *grpc_gateway_user.User {
+	id := uuid.NewV4().String()
+	user := &grpc_gateway_user.User{
+		Id:        id,
+		Name:      name,
+		CompanyId: companyID,
+		Role:      role,
+		IsAdmin:   role == RolePlatformAdmin,
+
+		// email is unique, so service account gets email which can't receive any letters
+		Email:            id + "@service-account.invalid",
+		IsEnabled:        true,
+		IsConfirmed:      true,
+		IsServiceAccount: true,
+	}
+
+	return user
+}
+
+// CreateServiceAccount - create service account. Service accounts can't login, they use api keys only
+func (ur *UserRepo) CreateServiceAccount(name, companyID, role string) (*grpc_gateway_user.User, error) {
 	c := ur.sess.C(ur.coll)
-	var user grpc_gateway_user.User
 
-	err := c.Find(bson.M{"id": id, "isenabled": true, "isconfirmed": true}).One(&user)
-	return &user, err
+	user := newServiceAccount(name, companyID, role)
+	doc, err := ur.document(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, c.Insert(doc)
+}
+
+// GetUserByID - get user from database by id
+func (ur *UserRepo) GetUserByID(id string) (*grpc_gateway_user.User, error) {
+	return ur.findUser(bson.M{"id": id, "isenabled": true, "isconfirmed": true})
 }
 
 // GetUserByEmailCode - get user from database by id
 func (ur *UserRepo) GetUserByEmailCode(user *grpc_gateway_user.User) (*grpc_gateway_user.User, error) {
-	c := ur.sess.C(ur.coll)
+	return ur.findUser(bson.M{"emailcode": user.EmailCode})
+}
+
+// pendingUserQuery - query of invited users who didn't confirm email yet
+func pendingUserQuery(query bson.M) bson.M {
+	query["isconfirmed"] = false
+	query["isenabled"] = true
+	query["isserviceaccount"] = bson.M{"$ne": true}
+	return query
+}
+
+// GetPendingUserByID - get invited user who didn't confirm email yet
+func (ur *UserRepo) GetPendingUserByID(id string) (*grpc_gateway_user.User, error) {
+	return ur.findUser(pendingUserQuery(bson.M{"id": id}))
+}
+
+// GetPendingUsersByCompanyID - get page of invited users of company who didn't confirm email yet
+func (ur *UserRepo) GetPendingUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return ur.findUsers(pendingUserQuery(bson.M{"companyid": companyID}), page)
+}
 
-	storedUser := new(grpc_gateway_user.User)
-	err := c.Find(bson.M{"emailcode": user.EmailCode}).One(&storedUser)
-	return storedUser, err
+// RegenerateEmailCode - replace email code of invited user, so previous invitation link stops working
+func (ur *UserRepo) RegenerateEmailCode(id string) (*grpc_gateway_user.User, error) {
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{
+			"emailcode":   uuid.NewV4().String(),
+			"emailsentat": &timestamp.Timestamp{Seconds: time.Now().Unix()},
+		}},
+		ReturnNew: true,
+	}
+
+	return ur.applyUser(pendingUserQuery(bson.M{"id": id}), change)
 }
 
 // SetSMSCode - set sms-code for specific user
@@ -78,53 +273,144 @@ func (ur *UserRepo) SetSMSCode(userID, code string) error {
 	c := ur.sess.C(ur.coll)
 
 	tm := timestamp.Timestamp{Seconds: time.Now().Unix()}
-	return c.Update(bson.M{"id": userID}, bson.M{"$set": bson.M{"smscode": code, "smssentat": tm}})
+	return c.Update(bson.M{"id": userID}, bson.M{"$set": bson.M{"smscode": code, "smssentat": tm, "smsattempts": 0}})
 }
 
-// EnableUserAndSetPasswordPhone - enable user after success confirmation
-func (ur *UserRepo) EnableUserAndSetPasswordPhone(userID, password, phone string) error {
+// RegisterSMSCodeFailure - count wrong attempt for issued sms-code. When maxAttempts is reached
+// the code is dropped, so new code should be requested
+func (ur *UserRepo) RegisterSMSCodeFailure(email string, maxAttempts int) error {
+	c := ur.sess.C(ur.coll)
+
+	err := c.Update(bson.M{"email": email}, bson.M{"$inc": bson.M{"smsattempts": 1}})
+	if err != nil {
+		return err
+	}
+
+	err = c.Update(bson.M{"email": email, "smsattempts": bson.M{"$gte": maxAttempts}}, bson.M{"$set": bson.M{"smscode": "", "smssentat": nil}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// SetTOTPPendingSecret - set secret of authenticator app which waits for confirmation
+func (ur *UserRepo) SetTOTPPendingSecret(userID, secret string) error {
+	c := ur.sess.C(ur.coll)
+	return c.Update(bson.M{"id": userID}, bson.M{"$set": bson.M{"totppendingsecret": secret}})
+}
+
+// EnableTOTP - activate confirmed secret of authenticator app, step is step of confirmation code
+func (ur *UserRepo) EnableTOTP(userID, secret string, step int64) error {
+	c := ur.sess.C(ur.coll)
+	return c.Update(bson.M{"id": userID, "totppendingsecret": secret}, bson.M{"$set": bson.M{
+		"totpsecret":        secret,
+		"totppendingsecret": "",
+		"totpenabled":       true,
+		"totplaststep":      step,
+	}})
+}
+
+// SetTOTPLastStep - remember step of used totp code. Fails if the same or later step was already used
+func (ur *UserRepo) SetTOTPLastStep(userID string, step int64) error {
+	c := ur.sess.C(ur.coll)
+	return c.Update(bson.M{"id": userID, "totplaststep": bson.M{"$lt": step}}, bson.M{"$set": bson.M{"totplaststep": step}})
+}
+
+// SetPreferredFactor - set second factor which is used by default on login
+func (ur *UserRepo) SetPreferredFactor(userID, factor string) error {
+	c := ur.sess.C(ur.coll)
+	return c.Update(bson.M{"id": userID}, bson.M{"$set": bson.M{"preferredfactor": factor}})
+}
+
+// SetPasswordResetToken - store hash of password reset token for enabled user with email
+func (ur *UserRepo) SetPasswordResetToken(email, token string) (*grpc_gateway_user.User, error) {
+	change := mgo.Change{
+		Update: bson.M{"$set": bson.M{
+			"passwordresettoken":  hashSecretToken(token),
+			"passwordresetsentat": timestamp.Timestamp{Seconds: time.Now().Unix()},
+		}},
+		ReturnNew: true,
+	}
+
+	return ur.applyUser(bson.M{"email": email, "isenabled": true, "isconfirmed": true, "isserviceaccount": bson.M{"$ne": true}}, change)
+}
+
+// GetUserByPasswordResetToken - find user by password reset token
+func (ur *UserRepo) GetUserByPasswordResetToken(token string) (*grpc_gateway_user.User, error) {
+	return ur.findUser(bson.M{"passwordresettoken": hashSecretToken(token), "isenabled": true, "isconfirmed": true})
+}
+
+// ResetPassword - set new password and drop password reset token, so it can't be used again
+func (ur *UserRepo) ResetPassword(userID, token, password string) error {
 	c := ur.sess.C(ur.coll)
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	return c.Update(bson.M{"id": userID}, bson.M{"$set": bson.M{
-		"emailcode":   "",
-		"smscode":     "",
-		"emailsentat": nil,
-		"smssentat":   nil,
-		"isconfirmed": true,
-		"password":    string(hash),
-		"phone":       phone,
+	err = c.Update(bson.M{"id": userID, "passwordresettoken": hashSecretToken(token)}, bson.M{"$set": bson.M{
+		"password":            string(hash),
+		"passwordresettoken":  "",
+		"passwordresetsentat": nil,
+		"smscode":             "",
+		"smssentat":           nil,
 	}})
+	if err == mgo.ErrNotFound {
+		return ErrPasswordResetExpired
+	}
+
+	return err
 }
 
-// LoginUser - check if user passed correct credentials
-func (ur *UserRepo) LoginUser(email, password string) (*grpc_gateway_user.User, error) {
+// EnableUserAndSetPasswordPhone - enable user after success confirmation
+func (ur *UserRepo) EnableUserAndSetPasswordPhone(userID, password, phone string) error {
 	c := ur.sess.C(ur.coll)
-	var user grpc_gateway_user.User
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
 
-	err := c.Find(bson.M{"email": email, "isenabled": true, "isconfirmed": true}).One(&user)
+	fields, err := ur.sealPhone(userID, phone)
 	if err != nil {
-		return &user, err
+		return err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	fields["emailcode"] = ""
+	fields["smscode"] = ""
+	fields["emailsentat"] = nil
+	fields["smssentat"] = nil
+	fields["isconfirmed"] = true
+	fields["password"] = string(hash)
+	return c.Update(bson.M{"id": userID}, bson.M{"$set": fields})
+}
+
+// LoginUser - check if user passed correct credentials
+func (ur *UserRepo) LoginUser(email, password string) (*grpc_gateway_user.User, error) {
+	user, err := ur.findUser(bson.M{"email": email, "isenabled": true, "isconfirmed": true, "isserviceaccount": bson.M{"$ne": true}})
 	if err != nil {
-		return &user, err
+		return user, err
 	}
 
-	return &user, err
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	return user, err
+}
+
+// GetSSOUser - find active user of company by email verified by identity provider. Emails are compared
+// without case because identity providers don't keep case of registered address
+func (ur *UserRepo) GetSSOUser(email, companyID string) (*grpc_gateway_user.User, error) {
+	return ur.findUser(bson.M{
+		"email":            bson.RegEx{Pattern: "^" + regexp.QuoteMeta(email) + "$", Options: "i"},
+		"companyid":        companyID,
+		"isenabled":        true,
+		"isconfirmed":      true,
+		"isserviceaccount": bson.M{"$ne": true},
+	})
 }
 
 // GetUserBySMSCode - find user by email and sms code. Uses in all confirmation-required services
 func (ur *UserRepo) GetUserBySMSCode(email, code string) (*grpc_gateway_user.User, error) {
-	c := ur.sess.C(ur.coll)
-	var user grpc_gateway_user.User
-
-	err := c.Find(bson.M{"email": email, "smscode": code}).One(&user)
-	return &user, err
+	return ur.findUser(bson.M{"email": email, "smscode": code})
 }
 
 // ConfirmSMSUser - confirm sms-code passed from user
@@ -142,43 +428,32 @@ func (ur *UserRepo) DeleteUserByID(id string) error {
 	return err
 }
 
-// GetUsers - get users from database
-func (ur *UserRepo) GetUsers() (*grpc_gateway_user.UserListResponse, error) {
-	c := ur.sess.C(ur.coll)
-	users := NewUserListResponse()
-
-	err := c.Find(bson.M{"isenabled": true, "isconfirmed": true}).All(&users.Data)
-	return users, err
+// GetUsers - get page of users from database
+func (ur *UserRepo) GetUsers(page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return ur.findUsers(bson.M{"isenabled": true, "isconfirmed": true}, page)
 }
 
-// GetUsersByCompanyID - get users from database by company id
-func (ur *UserRepo) GetUsersByCompanyID(companyID string) (*grpc_gateway_user.UserListResponse, error) {
-	c := ur.sess.C(ur.coll)
-	users := NewUserListResponse()
-
-	err := c.Find(bson.M{"isenabled": true, "companyid": companyID}).All(&users.Data)
-	return users, err
+// GetUsersByCompanyID - get page of users from database by company id, nil page means all users
+func (ur *UserRepo) GetUsersByCompanyID(companyID string, page *Page) (*grpc_gateway_user.UserListResponse, error) {
+	return ur.findUsers(bson.M{"isenabled": true, "companyid": companyID}, page)
 }
 
-// UpdateUserByID - update user information by user id
-func (ur *UserRepo) UpdateUserByID(isAdminUser bool, oldUser, user *grpc_gateway_user.User) (*grpc_gateway_user.User, error) {
-	c := ur.sess.C(ur.coll)
-
+// applyUserUpdate - copy fields which can be changed by client from user to stored user
+func applyUserUpdate(oldUser, user *grpc_gateway_user.User, role, companyID string) error {
 	if user.Password != "" {
 		hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 		//hash, err := argon2.Hash(argon2.NewContext(), []byte(user.Password), []byte("somesalt"))
 		if err != nil {
-			return oldUser, err
+			return err
 		}
 
 		oldUser.Password = string(hash)
 	}
 
-	// if user admin - allow set admin user
-	if isAdminUser {
-		oldUser.IsAdmin = user.IsAdmin
-		oldUser.CompanyId = user.CompanyId
-	}
+	// role and company are checked by caller
+	oldUser.Role = role
+	oldUser.IsAdmin = role == RolePlatformAdmin
+	oldUser.CompanyId = companyID
 
 	// update allowed fields
 	oldUser.Email = user.Email
@@ -186,21 +461,102 @@ func (ur *UserRepo) UpdateUserByID(isAdminUser bool, oldUser, user *grpc_gateway
 	oldUser.Phone = user.Phone
 	oldUser.SmsCode = ""
 	oldUser.SmsSentAt = nil
+	return nil
+}
+
+// UpdateUserByID - update user information by user id
+func (ur *UserRepo) UpdateUserByID(oldUser, user *grpc_gateway_user.User, role, companyID string) (*grpc_gateway_user.User, error) {
+	c := ur.sess.C(ur.coll)
+
+	if err := applyUserUpdate(oldUser, user, role, companyID); err != nil {
+		return oldUser, err
+	}
+
+	doc, err := ur.document(oldUser)
+	if err != nil {
+		return oldUser, err
+	}
 
-	err := c.Update(bson.M{"id": user.Id}, oldUser)
+	err = c.Update(bson.M{"id": user.Id}, doc)
 	return oldUser, err
 }
 
-// CreateIndexes - create necessary indexes for fast executing
-func (ur *UserRepo) CreateIndexes() {
+// ImportUser - store user with id and state from another environment, secrets of user aren't moved
+func (ur *UserRepo) ImportUser(user *grpc_gateway_user.User) error {
 	c := ur.sess.C(ur.coll)
-	c.EnsureIndex(mgo.Index{
-		Key:    []string{"email"},
-		Unique: true,
-	})
 
-	c.EnsureIndex(mgo.Index{
-		Key:    []string{"id"},
-		Unique: true,
-	})
+	doc, err := ur.document(user)
+	if err != nil {
+		return err
+	}
+
+	return c.Insert(doc)
+}
+
+// RemoveCompanyUsers - remove users of company from storage, unlike DeleteUserByID users aren't kept
+func (ur *UserRepo) RemoveCompanyUsers(companyID string) (int, error) {
+	c := ur.sess.C(ur.coll)
+
+	info, err := c.RemoveAll(bson.M{"companyid": companyID})
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Removed, nil
+}
+
+// HasUser - check if id or email are taken by any user, including deleted users
+func (ur *UserRepo) HasUser(id, email string) (bool, bool, error) {
+	c := ur.sess.C(ur.coll)
+
+	ids, err := c.Find(bson.M{"id": id}).Count()
+	if err != nil {
+		return false, false, err
+	}
+
+	emails, err := c.Find(bson.M{"email": email}).Count()
+	return ids > 0, emails > 0, err
+}
+
+// Reseal - seal personal data of users which is stored in plaintext or sealed by retired key.
+// Returns number of resealed users
+func (ur *UserRepo) Reseal() (int, error) {
+	c := ur.sess.C(ur.coll)
+	if ur.cipher == nil {
+		return 0, nil
+	}
+
+	query := bson.M{"$or": []bson.M{
+		{"sealed": bson.M{"$exists": false}},
+		{"sealed.kid": bson.M{"$ne": ur.cipher.ActiveKeyID()}},
+	}}
+
+	changed := 0
+	doc := storedUser{}
+	iter := c.Find(query).Iter()
+	for iter.Next(&doc) {
+		user, err := ur.open(&doc)
+		if err != nil {
+			iter.Close()
+			return changed, err
+		}
+
+		fields, err := ur.sealPhone(user.Id, user.Phone)
+		if err != nil {
+			iter.Close()
+			return changed, err
+		}
+
+		// user which was updated after it was read is already sealed by active key
+		err = c.Update(bson.M{"id": user.Id, "sealed": doc.Sealed, "phone": doc.Phone}, bson.M{"$set": fields})
+		if err != nil && err != mgo.ErrNotFound {
+			iter.Close()
+			return changed, err
+		}
+		if err == nil {
+			changed++
+		}
+	}
+
+	return changed, iter.Close()
 }
@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_company "git.simplendi.com/FirmQ/frontend-server/server/proto/company"
 	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/jsonpb"
 	. "gopkg.in/check.v1"
@@ -18,8 +20,10 @@ import (
 
 var HttpStatusOK = int32(http.StatusOK)
 var HttpStatusForbidden = int32(http.StatusForbidden)
+var HttpStatusUnauthorized = int32(http.StatusUnauthorized)
 var HttpStatusNotFound = int32(http.StatusNotFound)
 var HttpStatusPreconditionRequired = int32(http.StatusPreconditionRequired)
+var HttpStatusConflict = int32(http.StatusConflict)
 
 func Test(t *testing.T) { TestingT(t) }
 
@@ -34,8 +38,12 @@ func (ut *UserTestSuite) SetUpSuite(c *C) {
 	flag.Parse()
 
 	cfg := &server.Config{
+		Storage:              testStorage(),
 		EmailConfirmationTTL: time.Second * 5,
 		SMSConfirmationTTL:   time.Second * 2,
+
+		// all tests are running from one address
+		LoginMaxAddressFailures: 100000,
 	}
 	ut.server, err = server.NewServer(cfg)
 
@@ -54,6 +62,10 @@ func getDefaultUserPassword() string {
 }
 
 func getTestLoginToken(cred string) string {
+	return "Bearer " + getTestLoginResponse(cred).Token
+}
+
+func getTestLoginResponse(cred string) *grpc_gateway_user.LoginResponse {
 	creds := make(map[string]interface{})
 	json.Unmarshal([]byte(cred), &creds)
 
@@ -76,7 +88,20 @@ func getTestLoginToken(cred string) string {
 
 	respObj := server.NewLoginResponse()
 	jsonpb.Unmarshal(resp.Body, respObj)
-	return "Bearer " + respObj.Token
+	return respObj
+}
+
+func refreshTestToken(refreshToken string) (*grpc_gateway_user.LoginResponse, error) {
+	body := strings.NewReader(fmt.Sprintf(`{"refresh_token":"%s"}`, refreshToken))
+	resp, err := http.Post("http://127.0.0.1:8080/v1/refresh", "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respObj := server.NewLoginResponse()
+	err = jsonpb.Unmarshal(resp.Body, respObj)
+	return respObj, err
 }
 
 func getTestDefaultAuthToken() string {
@@ -486,6 +511,862 @@ func (ut *UserTestSuite) TestDeleteUser(c *C) {
 	c.Assert(isFound, Equals, false)
 }
 
+func (ut *UserTestSuite) TestRefreshAndLogout(c *C) {
+	login := getTestLoginResponse(fmt.Sprintf(`{"email":"%s", "password": "%s"}`, getDefaultUserEmail(), getDefaultUserPassword()))
+	c.Assert(login.Meta.Ok, Equals, true)
+	c.Assert(login.RefreshToken, Not(Equals), "")
+	c.Assert(login.ExpiresIn > 0, Equals, true)
+
+	// refresh token is exchanged for new pair of tokens
+	refreshed, err := refreshTestToken(login.RefreshToken)
+	c.Assert(err, IsNil)
+	c.Assert(refreshed.Meta.Ok, Equals, true)
+	c.Assert(refreshed.Token, Not(Equals), "")
+	c.Assert(refreshed.RefreshToken, Not(Equals), login.RefreshToken)
+
+	// refresh token can be used only once
+	reused, err := refreshTestToken(login.RefreshToken)
+	c.Assert(err, IsNil)
+	c.Assert(reused.Meta.Ok, Equals, false)
+	c.Assert(reused.Meta.StatusCode, Equals, int32(http.StatusUnauthorized))
+
+	// reuse of old refresh token revokes the whole session
+	reused, err = refreshTestToken(refreshed.RefreshToken)
+	c.Assert(err, IsNil)
+	c.Assert(reused.Meta.Ok, Equals, false)
+
+	login = getTestLoginResponse(fmt.Sprintf(`{"email":"%s", "password": "%s"}`, getDefaultUserEmail(), getDefaultUserPassword()))
+	token := "Bearer " + login.Token
+
+	req, err := http.NewRequest("POST", "http://127.0.0.1:8080/v1/logout", strings.NewReader("{}"))
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", token)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	mess := server.NewCommonResponse()
+	err = jsonpb.Unmarshal(resp.Body, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	// access token and refresh token of closed session are rejected
+	userResponse, err := ut.getUser("unknown", token)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.StatusCode, Equals, int32(http.StatusUnauthorized))
+
+	reused, err = refreshTestToken(login.RefreshToken)
+	c.Assert(err, IsNil)
+	c.Assert(reused.Meta.Ok, Equals, false)
+}
+
+func (ut *UserTestSuite) TestSessions(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUser(email, token, company.Id, false)
+	c.Assert(err, IsNil)
+
+	cred := fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email)
+	firstToken := getTestLoginToken(cred)
+	secondToken := getTestLoginToken(cred)
+
+	sessions := server.NewSessionListResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/session", secondToken, sessions)
+	c.Assert(err, IsNil)
+	c.Assert(sessions.Meta.Ok, Equals, true)
+	c.Assert(len(sessions.Data), Equals, 2)
+
+	var firstSession *grpc_gateway_user.Session
+	for _, session := range sessions.Data {
+		c.Assert(session.RefreshToken, Equals, "")
+		c.Assert(session.UserAgent, Matches, "Go-http-client.*")
+		c.Assert(session.IpAddress, Equals, "127.0.0.1")
+		c.Assert(session.CreatedAt > 0, Equals, true)
+		c.Assert(session.LastSeenAt >= session.CreatedAt, Equals, true)
+
+		if !session.IsCurrent {
+			firstSession = session
+		}
+	}
+	c.Assert(firstSession, NotNil)
+
+	// sessions are listed page by page
+	sessions = server.NewSessionListResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/session?limit=1", secondToken, sessions)
+	c.Assert(err, IsNil)
+	c.Assert(sessions.Meta.Ok, Equals, true)
+	c.Assert(sessions.Data, HasLen, 1)
+	c.Assert(sessions.Total, Equals, int64(2))
+	c.Assert(sessions.NextCursor, Not(Equals), "")
+	pageSession, cursor := sessions.Data[0].Id, sessions.NextCursor
+
+	sessions = server.NewSessionListResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/session?limit=1&cursor="+cursor, secondToken, sessions)
+	c.Assert(err, IsNil)
+	c.Assert(sessions.Meta.Ok, Equals, true)
+	c.Assert(sessions.Data, HasLen, 1)
+	c.Assert(sessions.Data[0].Id, Not(Equals), pageSession)
+	c.Assert(sessions.NextCursor, Equals, "")
+
+	// another user can't revoke session
+	otherEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err = createTestUser(otherEmail, token, company.Id, false)
+	c.Assert(err, IsNil)
+	otherToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, otherEmail))
+
+	mess := server.NewCommonResponse()
+	err = sendTestRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8080/v1/session/%v", firstSession.Id), otherToken, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusNotFound)
+
+	mess = server.NewCommonResponse()
+	err = sendTestRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/session/%v", user.Id, firstSession.Id), otherToken, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// revoked session can't be used anymore
+	mess = server.NewCommonResponse()
+	err = sendTestRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8080/v1/session/%v", firstSession.Id), secondToken, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	userResponse, err := ut.getUser(user.Id, firstToken)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	// admin sees sessions of user and can revoke them
+	sessions = server.NewSessionListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/session", user.Id), token, sessions)
+	c.Assert(err, IsNil)
+	c.Assert(sessions.Meta.Ok, Equals, true)
+	c.Assert(len(sessions.Data), Equals, 1)
+	c.Assert(sessions.Data[0].IsCurrent, Equals, false)
+
+	mess = server.NewCommonResponse()
+	err = sendTestRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/session/%v", user.Id, sessions.Data[0].Id), token, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	userResponse, err = ut.getUser(user.Id, secondToken)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+}
+
+func (ut *UserTestSuite) TestImpersonate(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUserWithRole(email, token, company.Id, server.RoleCompanyAdmin, false)
+	c.Assert(err, IsNil)
+	userToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	impersonateURL := fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/impersonate", user.Id)
+
+	// reason is required
+	login := server.NewLoginResponse()
+	err = postTestRequest(impersonateURL, token, &grpc_gateway_user.ImpersonateRequest{}, login)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+
+	// only platform admins can impersonate
+	login = server.NewLoginResponse()
+	err = postTestRequest(impersonateURL, userToken, &grpc_gateway_user.ImpersonateRequest{Reason: "debug"}, login)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	login = server.NewLoginResponse()
+	err = postTestRequest(impersonateURL, token, &grpc_gateway_user.ImpersonateRequest{Reason: "debug"}, login)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, true)
+	c.Assert(login.RefreshToken, Equals, "")
+	c.Assert(login.ExpiresIn > 0, Equals, true)
+	impersonatedToken := "Bearer " + login.Token
+
+	// token carries both users
+	payload, err := jwt.DecodeSegment(strings.Split(login.Token, ".")[1])
+	c.Assert(err, IsNil)
+
+	claims := map[string]interface{}{}
+	c.Assert(json.Unmarshal(payload, &claims), IsNil)
+	c.Assert(claims["user_id"], Equals, user.Id)
+	c.Assert(claims["impersonator_id"], Not(Equals), "")
+	c.Assert(claims["impersonator_id"], Not(Equals), user.Id)
+
+	// admin sees what user sees
+	users := server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), impersonatedToken, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.Ok, Equals, true)
+
+	users = server.NewUserListResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/user", impersonatedToken, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// but can't change credentials
+	update, err := updateTestUser(user.Id, &grpc_gateway_user.User{
+		Id:       user.Id,
+		Name:     user.Name,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Password: "54321",
+	}, impersonatedToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	update, err = updateTestUser(user.Id, &grpc_gateway_user.User{
+		Id:    user.Id,
+		Name:  user.Name,
+		Email: user.Email,
+		Phone: "1111",
+	}, impersonatedToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	update, err = updateTestUser(user.Id, &grpc_gateway_user.User{
+		Id:    user.Id,
+		Name:  user.Name,
+		Email: fmt.Sprintf("taken_over_%v@test.com", time.Now().UnixNano()),
+		Phone: user.Phone,
+	}, impersonatedToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	stored := server.NewUserResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v", user.Id), token, stored)
+	c.Assert(err, IsNil)
+	c.Assert(stored.Data.Email, Equals, email)
+
+	enrollment := server.NewTOTPEnrollmentResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/totp/enroll", impersonatedToken, struct{}{}, enrollment)
+	c.Assert(err, IsNil)
+	c.Assert(enrollment.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// changes which don't touch credentials are allowed
+	update, err = updateTestUser(user.Id, &grpc_gateway_user.User{
+		Id:    user.Id,
+		Name:  "renamed",
+		Email: user.Email,
+		Phone: user.Phone,
+	}, impersonatedToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.Ok, Equals, true)
+
+	// platform admins can't be impersonated
+	adminEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	admin, err := createTestUserWithRole(adminEmail, token, "", server.RolePlatformAdmin, true)
+	c.Assert(err, IsNil)
+
+	login = server.NewLoginResponse()
+	err = postTestRequest(fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/impersonate", admin.Id), token, &grpc_gateway_user.ImpersonateRequest{Reason: "debug"}, login)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Equals, HttpStatusForbidden)
+}
+
+// getTestSSOLogin - pass whole sso flow with identity provider of company
+func getTestSSOLogin(companyID string) (*grpc_gateway_user.LoginResponse, error) {
+	resp, err := server.GetHTTPClient().Get(fmt.Sprintf("http://127.0.0.1:8080/v1/sso/login/%v", companyID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respObj := server.NewLoginResponse()
+	err = jsonpb.Unmarshal(resp.Body, respObj)
+	return respObj, err
+}
+
+func postTestLogin(cred string) (*grpc_gateway_user.LoginResponse, error) {
+	resp, err := http.Post("http://127.0.0.1:8080/v1/login", "application/json", strings.NewReader(cred))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respObj := server.NewLoginResponse()
+	err = jsonpb.Unmarshal(resp.Body, respObj)
+	return respObj, err
+}
+
+func (ut *UserTestSuite) TestLoginLockout(c *C) {
+	token := getTestDefaultAuthToken()
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUser(email, token, "", false)
+	c.Assert(err, IsNil)
+
+	wrongCred := fmt.Sprintf(`{"email":"%s", "password": "wrong"}`, email)
+	cred := fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email)
+
+	for i := 0; i < 4; i++ {
+		login, err := postTestLogin(wrongCred)
+		c.Assert(err, IsNil)
+		c.Assert(login.Meta.Ok, Equals, false)
+		c.Assert(login.Meta.StatusCode, Not(Equals), int32(http.StatusTooManyRequests))
+	}
+
+	// 5th failure locks account
+	login, err := postTestLogin(wrongCred)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+	c.Assert(login.Meta.StatusCode, Equals, int32(http.StatusTooManyRequests))
+
+	// correct password doesn't help while account is locked
+	login, err = postTestLogin(cred)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Equals, int32(http.StatusTooManyRequests))
+
+	// admin unlocks account
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/unlock", user.Id), strings.NewReader("{}"))
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", token)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	mess := server.NewCommonResponse()
+	err = jsonpb.Unmarshal(resp.Body, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	login, err = postTestLogin(cred)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, true)
+	c.Assert(login.Meta.StatusCode, Equals, HttpStatusPreconditionRequired)
+
+	smsCode := strings.TrimSpace(strings.Split(server.GetSMSGateway().GetLatestMessage().Text, ":")[1])
+
+	// issued sms-code is dropped after too many wrong attempts
+	for i := 0; i < 3; i++ {
+		login, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345", "sms_code": "wrong"}`, email))
+		c.Assert(err, IsNil)
+		c.Assert(login.Meta.Ok, Equals, false)
+	}
+
+	login, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345", "sms_code": "%s"}`, email, smsCode))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+	c.Assert(login.Token, Equals, "")
+}
+
+func (ut *UserTestSuite) TestTOTPLogin(c *C) {
+	token := getTestDefaultAuthToken()
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err := createTestUser(email, token, "", false)
+	c.Assert(err, IsNil)
+
+	cred := fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email)
+	createdUserToken := getTestLoginToken(cred)
+
+	// totp can't be preferred before enrollment
+	mess := server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/preferred-factor", createdUserToken, map[string]string{"factor": server.FactorTOTP}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, false)
+
+	enrollment := server.NewTOTPEnrollmentResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/totp/enroll", createdUserToken, map[string]string{}, enrollment)
+	c.Assert(err, IsNil)
+	c.Assert(enrollment.Meta.Ok, Equals, true)
+	c.Assert(enrollment.Secret, Not(Equals), "")
+	c.Assert(strings.HasPrefix(enrollment.Uri, "otpauth://totp/"), Equals, true)
+
+	// enrollment is confirmed with first code
+	mess = server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/totp/confirm", createdUserToken, map[string]string{"code": "000000"}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, false)
+
+	code, err := server.GenerateTOTPCode(enrollment.Secret, time.Now())
+	c.Assert(err, IsNil)
+
+	mess = server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/totp/confirm", createdUserToken, map[string]string{"code": code}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	mess = server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/preferred-factor", createdUserToken, map[string]string{"factor": server.FactorTOTP}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	// first step asks for code from authenticator app
+	login, err := postTestLogin(cred)
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.StatusCode, Equals, HttpStatusPreconditionRequired)
+	c.Assert(login.SecondFactor, Equals, server.FactorTOTP)
+
+	// code which was used for confirmation can't be replayed
+	login, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345", "totp_code": "%s"}`, email, code))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+
+	// wait for next period
+	time.Sleep(time.Duration(30-time.Now().Unix()%30+1) * time.Second)
+	code, err = server.GenerateTOTPCode(enrollment.Secret, time.Now())
+	c.Assert(err, IsNil)
+
+	login, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "wrong", "totp_code": "%s"}`, email, code))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+
+	login, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345", "totp_code": "%s"}`, email, code))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, true)
+	c.Assert(login.Token, Not(Equals), "")
+}
+
+func (ut *UserTestSuite) TestPasswordReset(c *C) {
+	token := getTestDefaultAuthToken()
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUser(email, token, "", false)
+	c.Assert(err, IsNil)
+
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	// unknown emails get the same response
+	mess := server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/password-reset", "", map[string]string{"email": "unknown@test.com"}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	mess = server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/password-reset", "", map[string]string{"email": email}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	emailBody := bytes.NewBufferString("")
+	_, err = server.GetEmailSenderInstance().GetLatestMessage().WriteTo(emailBody)
+	c.Assert(err, IsNil)
+
+	resetToken := strings.TrimSpace(strings.Split(emailBody.String(), "reset-password/")[1])
+	resetURL := "http://127.0.0.1:8080/v1/reset-password/" + resetToken
+
+	// new password is set only after sms confirmation
+	reset := server.NewResetPasswordResponse()
+	err = postTestRequest(resetURL, "", map[string]string{"password": "54321"}, reset)
+	c.Assert(err, IsNil)
+	c.Assert(reset.Meta.Ok, Equals, true)
+	c.Assert(reset.Meta.StatusCode, Equals, HttpStatusPreconditionRequired)
+	c.Assert(reset.SecondFactor, Equals, server.FactorSMS)
+
+	smsCode := strings.TrimSpace(strings.Split(server.GetSMSGateway().GetLatestMessage().Text, ":")[1])
+
+	reset = server.NewResetPasswordResponse()
+	err = postTestRequest(resetURL, "", map[string]string{"password": "54321", "sms_code": "wrong"}, reset)
+	c.Assert(err, IsNil)
+	c.Assert(reset.Meta.Ok, Equals, false)
+
+	reset = server.NewResetPasswordResponse()
+	err = postTestRequest(resetURL, "", map[string]string{"password": "54321", "sms_code": smsCode}, reset)
+	c.Assert(err, IsNil)
+	c.Assert(reset.Meta.Ok, Equals, true)
+	c.Assert(reset.Meta.StatusCode, Equals, HttpStatusOK)
+
+	// link can be used only once
+	reset = server.NewResetPasswordResponse()
+	err = postTestRequest(resetURL, "", map[string]string{"password": "11111"}, reset)
+	c.Assert(err, IsNil)
+	c.Assert(reset.Meta.Ok, Equals, false)
+
+	// sessions opened with old password are revoked
+	userResponse, err := ut.getUser(user.Id, createdUserToken)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.StatusCode, Equals, int32(http.StatusUnauthorized))
+
+	login, err := postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+
+	newToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "54321"}`, email))
+	userResponse, err = ut.getUser(user.Id, newToken)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.Ok, Equals, true)
+}
+
+func (ut *UserTestSuite) TestCompanyAdmin(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	otherCompany, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err = createTestUserWithRole(email, token, company.Id, server.RoleCompanyAdmin, false)
+	c.Assert(err, IsNil)
+
+	companyAdminToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	// company admin creates users of own company
+	email = fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	viewer, err := createTestUserWithRole(email, companyAdminToken, company.Id, server.RoleViewer, false)
+	c.Assert(err, IsNil)
+	c.Assert(viewer.Id, Not(Equals), "")
+
+	userResponse, err := ut.getUser(viewer.Id, companyAdminToken)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.Ok, Equals, true)
+	c.Assert(userResponse.Data.Role, Equals, server.RoleViewer)
+
+	// but can't create platform admins or users of other companies
+	mess, err := rawCreateTestUser(&grpc_gateway_user.User{
+		Email:     fmt.Sprintf("test_%v@test.com", time.Now().UnixNano()),
+		CompanyId: company.Id,
+		Role:      server.RolePlatformAdmin,
+	}, companyAdminToken)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	mess, err = rawCreateTestUser(&grpc_gateway_user.User{
+		Email:     fmt.Sprintf("test_%v@test.com", time.Now().UnixNano()),
+		CompanyId: otherCompany.Id,
+		Role:      server.RoleEditor,
+	}, companyAdminToken)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// role of own company user can be changed, but not raised to platform admin
+	update, err := updateTestUser(viewer.Id, &grpc_gateway_user.User{
+		Id:    viewer.Id,
+		Name:  viewer.Name,
+		Email: viewer.Email,
+		Phone: viewer.Phone,
+		Role:  server.RoleEditor,
+	}, companyAdminToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.Ok, Equals, true)
+	c.Assert(update.Data.Role, Equals, server.RoleEditor)
+
+	update, err = updateTestUser(viewer.Id, &grpc_gateway_user.User{
+		Id:    viewer.Id,
+		Name:  viewer.Name,
+		Email: viewer.Email,
+		Phone: viewer.Phone,
+		Role:  server.RolePlatformAdmin,
+	}, companyAdminToken, "")
+	c.Assert(err, IsNil)
+	c.Assert(update.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// list of all users is available only for platform admin
+	req, err := http.NewRequest("GET", "http://127.0.0.1:8080/v1/user", nil)
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", companyAdminToken)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	users := server.NewUserListResponse()
+	err = jsonpb.Unmarshal(resp.Body, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// editors and viewers can't manage users
+	viewerToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, viewer.Email))
+	mess, err = rawCreateTestUser(&grpc_gateway_user.User{
+		Email:     fmt.Sprintf("test_%v@test.com", time.Now().UnixNano()),
+		CompanyId: company.Id,
+		Role:      server.RoleViewer,
+	}, viewerToken)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+}
+
+// users of company are listed without passwords, codes and second factor secrets
+func (ut *UserTestSuite) TestGetUsersByCompanyWithoutSecrets(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	adminEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err = createTestUserWithRole(adminEmail, token, company.Id, server.RoleCompanyAdmin, false)
+	c.Assert(err, IsNil)
+	adminToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, adminEmail))
+
+	viewerEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err = createTestUserWithRole(viewerEmail, token, company.Id, server.RoleViewer, false)
+	c.Assert(err, IsNil)
+	viewerToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, viewerEmail))
+
+	// admin has pending totp secret, password reset token and sms code
+	enrollment := server.NewTOTPEnrollmentResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/totp/enroll", adminToken, map[string]string{}, enrollment)
+	c.Assert(err, IsNil)
+	c.Assert(enrollment.Meta.Ok, Equals, true)
+
+	mess := server.NewCommonResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/password-reset", "", map[string]string{"email": adminEmail}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	_, err = postTestLogin(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, adminEmail))
+	c.Assert(err, IsNil)
+
+	// reset email and sms-code of admin aren't used, remove them from queues
+	server.GetEmailSenderInstance().GetLatestMessage()
+	server.GetSMSGateway().GetLatestMessage()
+
+	users := server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), viewerToken, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.Ok, Equals, true)
+	c.Assert(users.Data, HasLen, 2)
+
+	for _, user := range users.Data {
+		c.Assert(user.Password, Equals, "")
+		c.Assert(user.SmsCode, Equals, "")
+		c.Assert(user.EmailCode, Equals, "")
+		c.Assert(user.TotpSecret, Equals, "")
+		c.Assert(user.TotpPendingSecret, Equals, "")
+		c.Assert(user.PasswordResetToken, Equals, "")
+	}
+}
+
+func (ut *UserTestSuite) TestUnlockByNonAdmin(c *C) {
+	token := getTestDefaultAuthToken()
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUser(email, token, "", false)
+	c.Assert(err, IsNil)
+
+	createdUserToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, email))
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/unlock", user.Id), strings.NewReader("{}"))
+	c.Assert(err, IsNil)
+	req.Header.Add("Authorization", createdUserToken)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+
+	mess := server.NewCommonResponse()
+	err = jsonpb.Unmarshal(resp.Body, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, false)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+}
+
+func (ut *UserTestSuite) TestServiceAccountAPIKey(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	serviceAccount := server.NewUserResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/service-account", token, &grpc_gateway_user.ServiceAccountRequest{
+		Name:      "integration",
+		CompanyId: company.Id,
+		Role:      server.RoleViewer,
+	}, serviceAccount)
+	c.Assert(err, IsNil)
+	c.Assert(serviceAccount.Meta.Ok, Equals, true)
+	c.Assert(serviceAccount.Data.IsServiceAccount, Equals, true)
+	c.Assert(serviceAccount.Data.Role, Equals, server.RoleViewer)
+
+	apiKeyURL := fmt.Sprintf("http://127.0.0.1:8080/v1/service-account/%v/api-key", serviceAccount.Data.Id)
+	apiKey := server.NewAPIKeyResponse()
+	err = postTestRequest(apiKeyURL, token, &grpc_gateway_user.CreateAPIKeyRequest{Name: "ci"}, apiKey)
+	c.Assert(err, IsNil)
+	c.Assert(apiKey.Meta.Ok, Equals, true)
+	c.Assert(apiKey.Key, Not(Equals), "")
+	c.Assert(apiKey.Data.KeyHash, Equals, "")
+
+	// service account works with permissions of its role in its company
+	users := server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), "ApiKey "+apiKey.Key, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.Ok, Equals, true)
+
+	users = server.NewUserListResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/user", "ApiKey "+apiKey.Key, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// usage of key is tracked, secret isn't exposed
+	apiKeys := server.NewAPIKeyListResponse()
+	err = sendTestRequest("GET", apiKeyURL, token, apiKeys)
+	c.Assert(err, IsNil)
+	c.Assert(apiKeys.Meta.Ok, Equals, true)
+	c.Assert(len(apiKeys.Data), Equals, 1)
+	c.Assert(apiKeys.Total, Equals, int64(1))
+	c.Assert(apiKeys.Data[0].KeyHash, Equals, "")
+	c.Assert(apiKeys.Data[0].LastUsedAt > 0, Equals, true)
+
+	// keys can't be sorted by hashes of secrets
+	apiKeys = server.NewAPIKeyListResponse()
+	err = sendTestRequest("GET", apiKeyURL+"?sort=key_hash", token, apiKeys)
+	c.Assert(err, IsNil)
+	c.Assert(apiKeys.Meta.StatusCode, Equals, int32(http.StatusBadRequest))
+
+	// service account can't login with password
+	login, err := postTestLogin(fmt.Sprintf(`{"email":"%s", "password": ""}`, serviceAccount.Data.Email))
+	c.Assert(err, IsNil)
+	c.Assert(login.Meta.Ok, Equals, false)
+
+	// revoked key is rejected
+	revoke := server.NewCommonResponse()
+	err = sendTestRequest("DELETE", fmt.Sprintf("http://127.0.0.1:8080/v1/api-key/%v", apiKey.Data.Id), token, revoke)
+	c.Assert(err, IsNil)
+	c.Assert(revoke.Meta.Ok, Equals, true)
+
+	users = server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), "ApiKey "+apiKey.Key, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	// expired keys can't be created
+	apiKey = server.NewAPIKeyResponse()
+	err = postTestRequest(apiKeyURL, token, &grpc_gateway_user.CreateAPIKeyRequest{
+		Name:      "expired",
+		ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+	}, apiKey)
+	c.Assert(err, IsNil)
+	c.Assert(apiKey.Meta.Ok, Equals, false)
+}
+
+func (ut *UserTestSuite) TestServiceAccountOfDisabledCompany(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	serviceAccount := server.NewUserResponse()
+	err = postTestRequest("http://127.0.0.1:8080/v1/service-account", token, &grpc_gateway_user.ServiceAccountRequest{
+		Name:      "integration",
+		CompanyId: company.Id,
+		Role:      server.RoleViewer,
+	}, serviceAccount)
+	c.Assert(err, IsNil)
+	c.Assert(serviceAccount.Meta.Ok, Equals, true)
+
+	apiKeyURL := fmt.Sprintf("http://127.0.0.1:8080/v1/service-account/%v/api-key", serviceAccount.Data.Id)
+	apiKey := server.NewAPIKeyResponse()
+	err = postTestRequest(apiKeyURL, token, &grpc_gateway_user.CreateAPIKeyRequest{Name: "ci"}, apiKey)
+	c.Assert(err, IsNil)
+	c.Assert(apiKey.Meta.Ok, Equals, true)
+
+	companyURL := fmt.Sprintf("http://127.0.0.1:8080/v1/company/%v", company.Id)
+	users := server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), "ApiKey "+apiKey.Key, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.Ok, Equals, true)
+
+	mess := server.NewCommonResponse()
+	err = sendTestRequest("DELETE", companyURL, token, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	// keys of disabled company are revoked with sessions
+	users = server.NewUserListResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/user_by_company/%v", company.Id), "ApiKey "+apiKey.Key, users)
+	c.Assert(err, IsNil)
+	c.Assert(users.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	apiKeys := server.NewAPIKeyListResponse()
+	err = sendTestRequest("GET", apiKeyURL, token, apiKeys)
+	c.Assert(err, IsNil)
+	c.Assert(apiKeys.Meta.Ok, Equals, true)
+	c.Assert(apiKeys.Data, HasLen, 1)
+	c.Assert(apiKeys.Data[0].IsRevoked, Equals, true)
+}
+
+func (ut *UserTestSuite) TestSSOLogin(c *C) {
+	token := getTestDefaultAuthToken()
+
+	idp, err := newStubIdP("firmq", "secret")
+	c.Assert(err, IsNil)
+	defer idp.Close()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	otherCompany, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	// company without identity provider
+	loginResp, err := getTestSSOLogin(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.StatusCode, Equals, HttpStatusNotFound)
+
+	mess := server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf("http://127.0.0.1:8080/v1/company/%v/sso", company.Id), token, &grpc_gateway_company.IdentityProvider{
+		Issuer:         idp.Issuer(),
+		ClientId:       "firmq",
+		ClientSecret:   "secret",
+		AllowedDomains: []string{"test.com"},
+		IsEnabled:      true,
+	}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	idpResp := server.NewIdentityProviderResponse()
+	err = sendTestRequest("GET", fmt.Sprintf("http://127.0.0.1:8080/v1/company/%v/sso", company.Id), token, idpResp)
+	c.Assert(err, IsNil)
+	c.Assert(idpResp.Meta.Ok, Equals, true)
+	c.Assert(idpResp.Data.Issuer, Equals, idp.Issuer())
+	c.Assert(idpResp.Data.ClientSecret, Equals, "")
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	user, err := createTestUser(email, token, company.Id, false)
+	c.Assert(err, IsNil)
+
+	otherEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	_, err = createTestUser(otherEmail, token, otherCompany.Id, false)
+	c.Assert(err, IsNil)
+
+	// verified email of company user gets the same tokens as password login
+	idp.SetUser(strings.ToUpper(email), true)
+	loginResp, err = getTestSSOLogin(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.Ok, Equals, true)
+	c.Assert(loginResp.Token, Not(Equals), "")
+	c.Assert(loginResp.RefreshToken, Not(Equals), "")
+
+	userResponse, err := ut.getUser(user.Id, "Bearer "+loginResp.Token)
+	c.Assert(err, IsNil)
+	c.Assert(userResponse.Meta.Ok, Equals, true)
+
+	refreshed, err := refreshTestToken(loginResp.RefreshToken)
+	c.Assert(err, IsNil)
+	c.Assert(refreshed.Meta.Ok, Equals, true)
+
+	// unverified emails, users of other companies and unknown users aren't logged in
+	idp.SetUser(email, false)
+	loginResp, err = getTestSSOLogin(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	idp.SetUser(otherEmail, true)
+	loginResp, err = getTestSSOLogin(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	idp.SetUser(fmt.Sprintf("test_%v@other.com", time.Now().UnixNano()), true)
+	loginResp, err = getTestSSOLogin(company.Id)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+
+	// state of completed login can't be replayed
+	loginResp = server.NewLoginResponse()
+	err = sendTestRequest("GET", "http://127.0.0.1:8080/v1/sso/callback?state=unknown&code=code", "", loginResp)
+	c.Assert(err, IsNil)
+	c.Assert(loginResp.Meta.StatusCode, Equals, HttpStatusUnauthorized)
+}
+
 func (ut *UserTestSuite) TestDeleteByNonAdmin(c *C) {
 	token := getTestDefaultAuthToken()
 
@@ -607,7 +1488,7 @@ func (ut *UserTestSuite) TestGetUsersByNonAdmin(c *C) {
 
 	defer resp.Body.Close()
 
-	userResponse := server.NewUserResponse()
+	userResponse := server.NewUserListResponse()
 	err = jsonpb.Unmarshal(resp.Body, userResponse)
 	c.Assert(err, IsNil)
 
@@ -918,6 +1799,104 @@ func (ut *UserTestSuite) TestGetUsersByCompany(c *C) {
 	c.Assert(userListResponse.Meta.StatusCode, Equals, HttpStatusOK)
 }
 
+// getTestEmailCode - return email code from the latest sent invitation
+func getTestEmailCode() string {
+	emailBody := bytes.NewBufferString("")
+	server.GetEmailSenderInstance().GetLatestMessage().WriteTo(emailBody)
+	return strings.Split(emailBody.String(), "/confirm-email/")[1]
+}
+
+func (ut *UserTestSuite) TestResendInvitation(c *C) {
+	token := getTestDefaultAuthToken()
+
+	company, err := createTestCompany(fmt.Sprintf("company_%v", time.Now().UnixNano()), token)
+	c.Assert(err, IsNil)
+
+	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	userResp, err := rawCreateTestUser(&grpc_gateway_user.User{
+		Name:      "invited",
+		Email:     email,
+		CompanyId: company.Id,
+		Role:      server.RoleViewer,
+	}, token)
+	c.Assert(err, IsNil)
+	c.Assert(userResp.Meta.Ok, Equals, true)
+	code := getTestEmailCode()
+
+	invitationsURL := fmt.Sprintf("http://127.0.0.1:8080/v1/invitation_by_company/%v", company.Id)
+	invitations := server.NewInvitationListResponse()
+	err = sendTestRequest("GET", invitationsURL, token, invitations)
+	c.Assert(err, IsNil)
+	c.Assert(invitations.Meta.Ok, Equals, true)
+	c.Assert(len(invitations.Data), Equals, 1)
+	c.Assert(invitations.Data[0].Email, Equals, email)
+	c.Assert(invitations.Data[0].Role, Equals, server.RoleViewer)
+	c.Assert(invitations.Data[0].IsExpired, Equals, false)
+	c.Assert(invitations.Data[0].ExpiresAt > invitations.Data[0].SentAt, Equals, true)
+	userID := invitations.Data[0].UserId
+
+	// valid link can't be renewed
+	renewURL := "http://127.0.0.1:8080/v1/confirm-email/%v/renew"
+	mess := server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf(renewURL, code), "", struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, false)
+
+	time.Sleep(ut.server.Config.EmailConfirmationTTL * 2)
+
+	invitations = server.NewInvitationListResponse()
+	err = sendTestRequest("GET", invitationsURL, token, invitations)
+	c.Assert(err, IsNil)
+	c.Assert(invitations.Data[0].IsExpired, Equals, true)
+
+	// holder of expired link gets new one, old link stops working
+	mess = server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf(renewURL, code), "", struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+
+	renewedCode := getTestEmailCode()
+	c.Assert(renewedCode, Not(Equals), code)
+
+	mess = server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf(renewURL, code), "", struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusNotFound)
+
+	invitations = server.NewInvitationListResponse()
+	err = sendTestRequest("GET", invitationsURL, token, invitations)
+	c.Assert(err, IsNil)
+	c.Assert(invitations.Data[0].IsExpired, Equals, false)
+
+	// admin can resend invitation any time, users of company can't
+	mess = server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/invitation", userID), token, struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.Ok, Equals, true)
+	c.Assert(getTestEmailCode(), Not(Equals), renewedCode)
+
+	editorEmail := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
+	editor, err := createTestUserWithRole(editorEmail, token, company.Id, server.RoleEditor, false)
+	c.Assert(err, IsNil)
+	editorToken := getTestLoginToken(fmt.Sprintf(`{"email":"%s", "password": "12345"}`, editorEmail))
+
+	mess = server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/invitation", userID), editorToken, struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	invitations = server.NewInvitationListResponse()
+	err = sendTestRequest("GET", invitationsURL, editorToken, invitations)
+	c.Assert(err, IsNil)
+	c.Assert(invitations.Meta.StatusCode, Equals, HttpStatusForbidden)
+
+	// confirmed users don't have pending invitations
+	mess = server.NewCommonResponse()
+	err = postTestRequest(fmt.Sprintf("http://127.0.0.1:8080/v1/user/%v/invitation", editor.Id), token, struct{}{}, mess)
+	c.Assert(err, IsNil)
+	c.Assert(mess.Meta.StatusCode, Equals, HttpStatusNotFound)
+}
+
 func (ut *UserTestSuite) TestEmailConfirmationEmailTokenTimeToLive(c *C) {
 	token := getTestDefaultAuthToken()
 	email := fmt.Sprintf("test_%v@test.com", time.Now().UnixNano())
@@ -5,13 +5,69 @@ import (
 	"encoding/json"
 	"fmt"
 	"git.simplendi.com/FirmQ/frontend-server/server"
+	grpc_gateway_common "git.simplendi.com/FirmQ/frontend-server/server/proto/common"
+	grpc_gateway_entity "git.simplendi.com/FirmQ/frontend-server/server/proto/entity"
 	grpc_gateway_user "git.simplendi.com/FirmQ/frontend-server/server/proto/user"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	. "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
+// testStorage - storage backend of test server. Tests run with memory storage unless
+// SIMPLENDI_TEST_STORAGE=mongo is set
+func testStorage() string {
+	if storage := os.Getenv("SIMPLENDI_TEST_STORAGE"); storage != "" {
+		return storage
+	}
+
+	return server.StorageMemory
+}
+
+// testMongoDatabase - connect to new database of test mongo, SIMPLENDI_TEST_MONGO_URI can override
+// default address. Tests which need mongo are skipped when tests run with memory storage
+func testMongoDatabase(c *C) *mgo.Database {
+	if testStorage() != server.StorageMongo {
+		c.Skip("tests run with memory storage")
+	}
+
+	uri := os.Getenv("SIMPLENDI_TEST_MONGO_URI")
+	if uri == "" {
+		uri = "mongodb://mongodb/testdatabase"
+	}
+
+	pool, err := server.NewConnectionPool(&server.Config{
+		MongoURI:            uri,
+		MongoDatabase:       fmt.Sprintf("test_%v", time.Now().UnixNano()),
+		MongoReadPreference: "primary",
+		MongoDialTimeout:    time.Second * 5,
+	})
+	c.Assert(err, IsNil)
+
+	sess, err := pool.GetConnection()
+	c.Assert(err, IsNil)
+	return sess
+}
+
+// migrateTestDatabase - create indexes of test database by migrations
+func migrateTestDatabase(c *C, sess *mgo.Database) {
+	runner, err := server.NewMigrationRunner(sess, server.Migrations)
+	c.Assert(err, IsNil)
+
+	_, err = runner.Run(false)
+	c.Assert(err, IsNil)
+}
+
 func createTestUser(email, token, companyId string, isAdmin bool) (*grpc_gateway_user.User, error) {
+	return createTestUserWithRole(email, token, companyId, "", isAdmin)
+}
+
+func createTestUserWithRole(email, token, companyId, role string, isAdmin bool) (*grpc_gateway_user.User, error) {
 	url := "http://127.0.0.1:8080/v1/user"
 	password := "12345"
 
@@ -22,6 +78,7 @@ func createTestUser(email, token, companyId string, isAdmin bool) (*grpc_gateway
 		IsEnabled: true,
 		CompanyId: companyId,
 		IsAdmin:   isAdmin,
+		Role:      role,
 		Phone:     "9999",
 	}
 
@@ -123,3 +180,403 @@ func rawCreateTestUser(user *grpc_gateway_user.User, token string) (*grpc_gatewa
 	err = jsonpb.Unmarshal(resp.Body, mess)
 	return mess, err
 }
+
+func postTestRequest(url, token string, obj interface{}, mess proto.Message) error {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Authorization", token)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return jsonpb.Unmarshal(resp.Body, mess)
+}
+
+func sendTestRequest(method, url, token string, mess proto.Message) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Authorization", token)
+
+	resp, err := server.GetHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return jsonpb.Unmarshal(resp.Body, mess)
+}
+
+// checkConcurrentEntityUpdates - update the same revision of entity concurrently, only one update
+// of every revision succeeds and entity always has one latest revision
+func checkConcurrentEntityUpdates(c *C, entities server.EntityStorage) {
+	entity := &grpc_gateway_entity.Entity{Id: fmt.Sprintf("entity_%v", time.Now().UnixNano()), CompanyId: "c1", Latest: true}
+	_, err := entities.CreateEntity(entity)
+	c.Assert(err, IsNil)
+
+	const updaters = 10
+	for baseRev := int64(0); baseRev < 3; baseRev++ {
+		wg := sync.WaitGroup{}
+		errs := make(chan error, updaters)
+		for i := 0; i < updaters; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				_, err := entities.UpdateEntity(&grpc_gateway_entity.Entity{
+					Id:         entity.Id,
+					CompanyId:  entity.CompanyId,
+					CommonName: fmt.Sprintf("update_%d", i),
+				}, baseRev)
+				errs <- err
+			}(i)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		updated := 0
+		for err := range errs {
+			if err == nil {
+				updated++
+			} else {
+				c.Assert(err, Equals, server.ErrEntityConflict)
+			}
+		}
+		c.Assert(updated, Equals, 1)
+		checkEntityListedOnce(c, entities, entity)
+	}
+
+	revs, err := entities.GetEntityRevs(entity.Id, entity.CompanyId, nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 4)
+
+	latest := 0
+	for i, rev := range revs.Data {
+		c.Assert(rev.Rev, Equals, int64(3-i))
+		if rev.Latest {
+			latest++
+		}
+	}
+	c.Assert(latest, Equals, 1)
+	c.Assert(revs.Data[0].Latest, Equals, true)
+}
+
+// checkEntityListedOnce - only the latest revision of entity is listed and found by search
+func checkEntityListedOnce(c *C, entities server.EntityStorage, entity *grpc_gateway_entity.Entity) {
+	list, err := entities.GetEntities(entity.CompanyId, &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+
+	found, err := entities.SearchEntities(entity.CompanyId, &grpc_gateway_entity.EntitySearchRequest{Query: "update"}, nil)
+	c.Assert(err, IsNil)
+
+	for _, data := range [][]*grpc_gateway_entity.Entity{list.Data, found.Data} {
+		listed := 0
+		for _, listedEntity := range data {
+			if listedEntity.Id == entity.Id {
+				c.Assert(listedEntity.Latest, Equals, true)
+				listed++
+			}
+		}
+		c.Assert(listed, Equals, 1)
+	}
+}
+
+// checkEntityTrash - delete, restore and purge entities
+func checkEntityTrash(c *C, entities server.EntityStorage) {
+
+	_, err := entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", Latest: true})
+	c.Assert(err, IsNil)
+	_, err = entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "e2", CompanyId: "c1", Latest: true})
+	c.Assert(err, IsNil)
+
+	deleted, err := entities.DeleteEntity("e1", "c1", "u1")
+	c.Assert(err, IsNil)
+	c.Assert(deleted.Rev, Equals, int64(1))
+	c.Assert(deleted.IsDeleted, Equals, true)
+	c.Assert(deleted.DeletedBy, Equals, "u1")
+
+	_, err = entities.GetLatestEntity("e1", "c1")
+	c.Assert(err, Equals, mgo.ErrNotFound)
+	_, err = entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1"}, 1)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+	_, err = entities.DeleteEntity("e1", "c1", "u1")
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	list, err := entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+
+	trash, err := entities.GetDeletedEntities("c1", &grpc_gateway_entity.EntityListRequest{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(trash.Data, HasLen, 1)
+	c.Assert(trash.Data[0].Id, Equals, "e1")
+
+	// entity which isn't deleted can't be restored
+	_, err = entities.RestoreEntity("e2", "c1", "u1")
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	restored, err := entities.RestoreEntity("e1", "c1", "u2")
+	c.Assert(err, IsNil)
+	c.Assert(restored.Rev, Equals, int64(2))
+	c.Assert(restored.IsDeleted, Equals, false)
+
+	latest, err := entities.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.CreatedBy, Equals, "u2")
+
+	revs, err := entities.GetEntityRevs("e1", "c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 3)
+
+	// entities deleted within retention period aren't purged
+	_, err = entities.DeleteEntity("e2", "c1", "u1")
+	c.Assert(err, IsNil)
+
+	purged, err := entities.PurgeDeletedEntities(time.Now().Add(-time.Hour).Unix())
+	c.Assert(err, IsNil)
+	c.Assert(purged, Equals, 0)
+
+	purged, err = entities.PurgeDeletedEntities(time.Now().Add(time.Hour).Unix())
+	c.Assert(err, IsNil)
+	c.Assert(purged, Equals, 1)
+
+	revs, err = entities.GetEntityRevs("e2", "c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 0)
+
+	_, err = entities.GetLatestEntity("e1", "c1")
+	c.Assert(err, IsNil)
+}
+
+// checkEntityPages - read all entities page by page, every entity is returned once in order of sort
+func checkEntityPages(c *C, entities server.EntityStorage) {
+	names := []string{"b", "a", "c", "a", "b", "a", "d"}
+	for i, name := range names {
+		_, err := entities.CreateEntity(&grpc_gateway_entity.Entity{
+			Id:         fmt.Sprintf("e%d", i),
+			CompanyId:  "c1",
+			CommonName: name,
+			CreatedAt:  int64(100 - i),
+			Latest:     true,
+		})
+		c.Assert(err, IsNil)
+	}
+
+	_, err := entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "other", CompanyId: "c2", CommonName: "a", Latest: true})
+	c.Assert(err, IsNil)
+
+	readAll := func(sort string) []*grpc_gateway_entity.Entity {
+		all := []*grpc_gateway_entity.Entity{}
+		cursor := ""
+		for pages := 0; ; pages++ {
+			c.Assert(pages < len(names), Equals, true)
+
+			page, err := server.NewPage(cursor, 3, sort, server.EntitySortFields, "common_name")
+			c.Assert(err, IsNil)
+
+			list, err := entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, page)
+			c.Assert(err, IsNil)
+			c.Assert(list.Total, Equals, int64(len(names)))
+			c.Assert(len(list.Data) <= 3, Equals, true)
+
+			all = append(all, list.Data...)
+			if list.NextCursor == "" {
+				return all
+			}
+			cursor = list.NextCursor
+		}
+	}
+
+	byName := readAll("common_name")
+	c.Assert(byName, HasLen, len(names))
+
+	ids := []string{}
+	for _, entity := range byName {
+		ids = append(ids, entity.Id)
+	}
+	c.Assert(ids, DeepEquals, []string{"e1", "e3", "e5", "e0", "e4", "e2", "e6"})
+
+	byDate := readAll("-created_at")
+	c.Assert(byDate, HasLen, len(names))
+	for i, entity := range byDate {
+		c.Assert(entity.Id, Equals, fmt.Sprintf("e%d", i))
+	}
+
+	// cursor is valid only for sort it was issued for
+	page, err := server.NewPage("", 3, "common_name", server.EntitySortFields, "")
+	c.Assert(err, IsNil)
+	list, err := entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, page)
+	c.Assert(err, IsNil)
+
+	_, err = server.NewPage(list.NextCursor, 3, "-created_at", server.EntitySortFields, "")
+	c.Assert(err, Equals, server.ErrInvalidCursor)
+}
+
+// checkEntitySearch - search entities of company, names and countries are matched without case and diacritics
+func checkEntitySearch(c *C, entities server.EntityStorage) {
+	for _, entity := range []*grpc_gateway_entity.Entity{
+		{Id: "e1", CompanyId: "c1", Type: "person", GivenName: "Jürgen", FamilyName: "Müller", Nationality: "Deutsch", CreatedAt: 100,
+			ResidentialAddress: &grpc_gateway_common.Address{Country: "Deutschland"}},
+		{Id: "e2", CompanyId: "c1", Type: "company", CommonName: "Øresund Holding", RegisteredName: "Øresund Holding B.V.", Kvk: "12345678",
+			LegalForm: "B.V.", CreatedAt: 200, VisitingAddress: &grpc_gateway_common.Address{Country: "Nederland"},
+			RegisteredAddress: &grpc_gateway_common.Address{Country: "Danmark"}},
+		{Id: "e3", CompanyId: "c1", Type: "company", CommonName: "Muller Trading", TradeName: "MT", Rsin: "87654321", CreatedAt: 300},
+		{Id: "e4", CompanyId: "c2", Type: "person", FamilyName: "Muller", Kvk: "12345678", CreatedAt: 100},
+		{Id: "e5", CompanyId: "c1", Type: "person", FamilyName: "Müller", CreatedAt: 100},
+	} {
+		entity.Latest = true
+		_, err := entities.CreateEntity(entity)
+		c.Assert(err, IsNil)
+	}
+
+	_, err := entities.DeleteEntity("e5", "c1", "u1")
+	c.Assert(err, IsNil)
+
+	search := func(companyID string, filter *grpc_gateway_entity.EntitySearchRequest) []string {
+		page, err := server.NewPage("", 0, "id", server.EntitySortFields, "")
+		c.Assert(err, IsNil)
+
+		list, err := entities.SearchEntities(companyID, filter, page)
+		c.Assert(err, IsNil)
+		c.Assert(list.Total, Equals, int64(len(list.Data)))
+
+		ids := []string{}
+		for _, entity := range list.Data {
+			ids = append(ids, entity.Id)
+		}
+		return ids
+	}
+
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "MULLER"}), DeepEquals, []string{"e1", "e3"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: " jurgen   muller "}), DeepEquals, []string{"e1"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "oresund holding b.v"}), DeepEquals, []string{"e2"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "(.*)"}), DeepEquals, []string{})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "muller", Type: "company"}), DeepEquals, []string{"e3"})
+
+	// identifiers are matched exactly
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Kvk: "12345678"}), DeepEquals, []string{"e2"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Kvk: "1234"}), DeepEquals, []string{})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Rsin: "87654321"}), DeepEquals, []string{"e3"})
+
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Country: "danmark"}), DeepEquals, []string{"e2"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Country: "NEDERLAND"}), DeepEquals, []string{"e2"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Nationality: "deutsch"}), DeepEquals, []string{"e1"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{LegalForm: "b.v."}), DeepEquals, []string{"e2"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{CreatedFrom: 150, CreatedTo: 300}), DeepEquals, []string{"e2", "e3"})
+
+	// search sees only latest revision of entity
+	updated, err := entities.GetLatestEntity("e3", "c1")
+	c.Assert(err, IsNil)
+	updated.CommonName = "Smit Trading"
+	_, err = entities.UpdateEntity(updated, updated.Rev)
+	c.Assert(err, IsNil)
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "muller"}), DeepEquals, []string{"e1"})
+	c.Assert(search("c1", &grpc_gateway_entity.EntitySearchRequest{Query: "smit"}), DeepEquals, []string{"e3"})
+
+	c.Assert(search("c2", &grpc_gateway_entity.EntitySearchRequest{Query: "muller"}), DeepEquals, []string{"e4"})
+
+	_, err = entities.SearchEntities("", &grpc_gateway_entity.EntitySearchRequest{Query: "muller"}, nil)
+	c.Assert(err, Equals, server.ErrMissedRequiredField)
+}
+
+// checkRemoveEntities - all revisions of removed entities are removed, entities of another company are kept
+func checkRemoveEntities(c *C, entities server.EntityStorage) {
+	for _, entity := range []*grpc_gateway_entity.Entity{
+		{Id: "e1", CompanyId: "c1", Latest: true},
+		{Id: "e2", CompanyId: "c1", Latest: true},
+		{Id: "e3", CompanyId: "c2", Latest: true},
+	} {
+		_, err := entities.CreateEntity(entity)
+		c.Assert(err, IsNil)
+	}
+
+	_, err := entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", Latest: true}, 0)
+	c.Assert(err, IsNil)
+
+	removed, err := entities.RemoveEntities("c1", []string{"e1", "e3"})
+	c.Assert(err, IsNil)
+	c.Assert(removed, Equals, 2)
+
+	revs, err := entities.GetEntityRevs("e1", "c1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 0)
+
+	for _, entity := range [][2]string{{"e2", "c1"}, {"e3", "c2"}} {
+		_, err = entities.GetLatestEntity(entity[0], entity[1])
+		c.Assert(err, IsNil)
+	}
+}
+
+// checkStagedEntities - staged entities of import aren't visible until import publishes them, staged
+// entities of other import are removed
+func checkStagedEntities(c *C, entities server.EntityStorage) {
+	_, err := entities.CreateEntity(&grpc_gateway_entity.Entity{Id: "e1", CompanyId: "c1", CommonName: "Visible", Latest: true})
+	c.Assert(err, IsNil)
+
+	for _, entity := range []*grpc_gateway_entity.Entity{
+		{Id: "s1", CompanyId: "c1", CommonName: "Staged"},
+		{Id: "s2", CompanyId: "c1", CommonName: "Staged"},
+	} {
+		c.Assert(entities.StageEntity(entity, "import1"), IsNil)
+	}
+	c.Assert(entities.StageEntity(&grpc_gateway_entity.Entity{Id: "s3", CompanyId: "c1", CommonName: "Staged"}, "import2"), IsNil)
+
+	page, err := server.NewPage("", 10, "id", server.EntitySortFields, "")
+	c.Assert(err, IsNil)
+
+	list, err := entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, page)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 1)
+
+	_, err = entities.GetLatestEntity("s1", "c1")
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	_, err = entities.UpdateEntity(&grpc_gateway_entity.Entity{Id: "s1", CompanyId: "c1"}, 0)
+	c.Assert(err, Equals, mgo.ErrNotFound)
+
+	revs, err := entities.GetCompanyRevisions("c1")
+	c.Assert(err, IsNil)
+	c.Assert(revs.Data, HasLen, 1)
+
+	published, err := entities.PublishStagedEntities("c1", "import1")
+	c.Assert(err, IsNil)
+	c.Assert(published, Equals, 2)
+
+	removed, err := entities.RemoveStagedEntities("c1", "import2")
+	c.Assert(err, IsNil)
+	c.Assert(removed, Equals, 1)
+
+	// published entities aren't removed with staged entities
+	removed, err = entities.RemoveStagedEntities("c1", "import1")
+	c.Assert(err, IsNil)
+	c.Assert(removed, Equals, 0)
+
+	page, err = server.NewPage("", 10, "id", server.EntitySortFields, "")
+	c.Assert(err, IsNil)
+
+	list, err = entities.GetEntities("c1", &grpc_gateway_entity.EntityListRequest{}, page)
+	c.Assert(err, IsNil)
+	c.Assert(list.Data, HasLen, 3)
+
+	latest, err := entities.GetLatestEntity("s1", "c1")
+	c.Assert(err, IsNil)
+	c.Assert(latest.Latest, Equals, true)
+
+	has, err := entities.HasEntity("s3")
+	c.Assert(err, IsNil)
+	c.Assert(has, Equals, false)
+}